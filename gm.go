@@ -23,28 +23,40 @@ import (
 	"github.com/kordamp/gm/gum"
 )
 
-var gmVersion string
-var gmBuildCommit string
-var gmBuildTimestamp string
-
 func main() {
 	args := gum.ParseArgs(os.Args[1:])
+	gum.ApplyEnvFlags(gum.NewDefaultContext(false), &args)
+
+	if len(args.Errors) > 0 {
+		for _, err := range args.Errors {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(-1)
+	}
 
 	bachBuild := args.HasGumFlag("gb")
 	gradleBuild := args.HasGumFlag("gg")
 	mavenBuild := args.HasGumFlag("gm")
 	jbangBuild := args.HasGumFlag("gj")
 	antBuild := args.HasGumFlag("ga")
+	sbtBuild := args.HasGumFlag("gs")
+	bootBuild := args.HasGumFlag("gbt")
+	buck2Build := args.HasGumFlag("gbk")
+	makeBuild := args.HasGumFlag("gmk")
+	cmakeBuild := args.HasGumFlag("gcm")
+	nodeBuild := args.HasGumFlag("gnode")
+	grailsBuild := args.HasGumFlag("ggr")
+	clojureBuild := args.HasGumFlag("gcl")
+	bldBuild := args.HasGumFlag("gbld")
+	leinBuild := args.HasGumFlag("gl")
+	bazelBuild := args.HasGumFlag("gz")
 	version := args.HasGumFlag("gv")
 	help := args.HasGumFlag("gh")
 
 	if version {
-		fmt.Println("------------------------------------------------------------")
-		fmt.Println("gm " + normalize(gmVersion))
-		fmt.Println("------------------------------------------------------------")
-		fmt.Println("Build time: " + normalize(gmBuildTimestamp))
-		fmt.Println("Revision:   " + normalize(gmBuildCommit))
-		fmt.Println("------------------------------------------------------------")
+		for _, line := range gum.VersionInfo() {
+			fmt.Println(line)
+		}
 		os.Exit(0)
 	}
 
@@ -52,19 +64,81 @@ func main() {
 		fmt.Println("Usage of gm:")
 		fmt.Println("  -ga\tforce Ant build")
 		fmt.Println("  -gb\tforce Bach build")
-		fmt.Println("  -gc\tdisplays current configuration and quits")
-		fmt.Println("  -gd\tdisplays debug information")
+		fmt.Println("  -gbg, --gum-background\truns the build with low priority")
+		fmt.Println("  -gbk\tforce Buck2 build")
+		fmt.Println("  -gbld\tforce bld build")
+		fmt.Println("  -gbt\tforce Boot build")
+		fmt.Println("  -gc, --gum-show-config\tdisplays current configuration and quits")
+		fmt.Println("  -gcl\tforce Clojure CLI build")
+		fmt.Println("  -gcm\tforce CMake build")
+		fmt.Println("  -gconfirm, --gum-confirm\tanswers yes to the confirmation of tasks listed in general.confirm")
+		fmt.Println("  -gd, --gum-debug\tdisplays debug information")
 		fmt.Println("  -gg\tforce Gradle build")
-		fmt.Println("  -gh\tdisplays help information")
+		fmt.Println("  -ggr\tforce Grails build")
+		fmt.Println("  -gh, --gum-help\tdisplays help information")
+		fmt.Println("  -gi, --gum-interactive\tpasses stdin to the build for interactive tasks")
 		fmt.Println("  -gj\tforce JBang execution")
+		fmt.Println("  -gl\tforce Leiningen build")
 		fmt.Println("  -gm\tforce Maven build")
-		fmt.Println("  -gn\texecutes nearest build file")
-		fmt.Println("  -gq\trun gm in quiet mode")
-		fmt.Println("  -gr\tdo not replace goals/tasks")
-		fmt.Println("  -gv\tdisplays version information")
+		fmt.Println("  -gmd, --gum-mvnd\tprefers mvnd over mvnw/mvn for Maven builds")
+		fmt.Println("  -gmk\tforce make build")
+		fmt.Println("  -gn, --gum-nearest\texecutes nearest build file")
+		fmt.Println("  -gnode\tforce Node package manager build")
+		fmt.Println("  -gnoscan, --gum-no-scan\tsuppresses Gradle build scans (--no-scan)")
+		fmt.Println("  -gp, --gum-profile <name>\tactivates a profile defined in config, such as -gp ci")
+		fmt.Println("  -gq, --gum-quiet\trun gm in quiet mode")
+		fmt.Println("  -gr, --gum-no-replace\tdo not replace goals/tasks")
+		fmt.Println("  -gR, --gum-retries <n>\treruns a failed build up to n times")
+		fmt.Println("  -gs\tforce sbt build")
+		fmt.Println("  -gscan, --gum-scan\tpublishes a Gradle build scan (--scan)")
+		fmt.Println("  -gshow, --gum-show\tdisplays original and final args side by side")
+		fmt.Println("  -gt, --gum-timeout <duration>\tkills the build if it does not finish in time, such as -gt 30m")
+		fmt.Println("  -gv, --gum-version\tdisplays version information, same as gm gum version")
+		fmt.Println("  -gx, --gum-dry-run\tprints the resolved command without running it")
+		fmt.Println("  -gy, --gum-yes\ttrust project wrappers and answer yes to wrapper prompts")
+		fmt.Println("  -gz\tforce Bazel build")
+		fmt.Println("  --gum-composite\truns an included Gradle build from the composite that includes it")
+		fmt.Println("  --gum-events <fd|file>\tappends NDJSON events to a file or file descriptor")
+		fmt.Println("  --gum-tool <name>\tforces a tool by name, such as maven, gradle, or a custom tool")
+		fmt.Println("  --no-pty\tdoes not run the build under a pseudo terminal")
+		fmt.Println("  --no-wizard\tdoes not offer the first run wizard")
+		fmt.Println("")
+		fmt.Println("Single letter flags that take no value may be bundled, such as -gnd for -gn -gd")
+		fmt.Println("Some long flags may be set with environment variables instead, such as GUM_NEAREST=true for --gum-nearest")
+		fmt.Println("or GUM_TIMEOUT=30m for --gum-timeout 30m: debug, interactive, mvnd, nearest, no-replace, no-scan, quiet,")
+		fmt.Println("retries, timeout, and yes")
+		fmt.Println("")
+		fmt.Println("Commands (gm gum <command>):")
+		fmt.Println("  alias add <name> <args> [--project]\tadds an alias to user (or project) config")
+		fmt.Println("  alias ls [--project]\t\t\tlists aliases")
+		fmt.Println("  alias rm <name> [--project]\t\tremoves an alias from user (or project) config")
+		fmt.Println("  cache clean\t\t\t\tdeletes the caches kept by gm")
+		fmt.Println("  config [--validate]\t\t\tdisplays the effective configuration and the source of each value,")
+		fmt.Println("\t\t\t\t\tor validates config files")
+		fmt.Println("  daemon status|stop|stop-all\t\tdisplays or stops the Gradle/mvnd daemons of the project, or stops all of them")
+		fmt.Println("  discover [--json]\t\t\truns detection only and reports every candidate tool found")
+		fmt.Println("  doctor\t\t\t\tdiagnoses the environment and suggests fixes")
+		fmt.Println("  export-script [--shell bash|powershell] [--output <file>] <args>")
+		fmt.Println("\t\t\t\t\twrites a standalone script that reproduces the invocation of args")
+		fmt.Println("  init [--force]\t\t\twrites a starter config at the project's root")
+		fmt.Println("  last\t\t\t\t\tdisplays the previous invocation")
+		fmt.Println("  lock [--check [--strict]]\t\trecords Gradle/Maven and JDK versions in gum.lock or checks them")
+		fmt.Println("  prompt [--no-icons]\t\t\tprints the build tool of the current directory for shell prompts")
+		fmt.Println("  release [<command>] [args]\t\truns JReleaser's command (full-release by default) with its CLI or build plugin")
+		fmt.Println("  run [options] <step> ...\t\texecutes each step in order, stopping at the first failure")
+		fmt.Println("    --continue-on-error\t\t\tcontinues with the next step if the following step fails")
+		fmt.Println("    --only-if-failed\t\t\truns the following step only if a previous step failed")
+		fmt.Println("  stats\t\t\t\t\tdisplays aggregate durations of invocations per project")
+		fmt.Println("  version\t\t\t\tdisplays version, revision, build time, and Go version of gm")
 		os.Exit(0)
 	}
 
+	gum.RunWizard(gum.NewDefaultContext(true), &args)
+
+	if gum.IsGumCommand(&args) {
+		os.Exit(gum.ExecuteGumCommand(gum.NewDefaultContext(false), &args))
+	}
+
 	count := 0
 	if gradleBuild {
 		count = count + 1
@@ -81,30 +155,56 @@ func main() {
 	if antBuild {
 		count = count + 1
 	}
+	if sbtBuild {
+		count = count + 1
+	}
+	if bootBuild {
+		count = count + 1
+	}
+	if buck2Build {
+		count = count + 1
+	}
+	if makeBuild {
+		count = count + 1
+	}
+	if cmakeBuild {
+		count = count + 1
+	}
+	if nodeBuild {
+		count = count + 1
+	}
+	if grailsBuild {
+		count = count + 1
+	}
+	if clojureBuild {
+		count = count + 1
+	}
+	if bldBuild {
+		count = count + 1
+	}
+	if leinBuild {
+		count = count + 1
+	}
+	if bazelBuild {
+		count = count + 1
+	}
+	if args.HasGumFlag("-gum-tool") {
+		count = count + 1
+	}
 
 	if count > 1 {
-		fmt.Println("You cannot define -gb, -gg, -gm, gj, or -ga flags at the same time")
+		fmt.Fprintln(os.Stderr, "You cannot define --gum-tool, -gb, -gg, -gm, -gs, -gz, -gbk, -gl, -gcl, -gbt, -gbld, -ggr, -gnode, -gcm, -gmk, -gj, or -ga flags at the same time")
 		os.Exit(-1)
 	}
 
-	if gradleBuild {
-		gum.FindGradle(gum.NewDefaultContext(true), &args).Execute()
-	} else if mavenBuild {
-		gum.FindMaven(gum.NewDefaultContext(true), &args).Execute()
-	} else if jbangBuild {
-		gum.FindJbang(gum.NewDefaultContext(true), &args).Execute()
-	} else if bachBuild {
-		gum.FindBach(gum.NewDefaultContext(true), &args).Execute()
-	} else if antBuild {
-		gum.FindAnt(gum.NewDefaultContext(true), &args).Execute()
-	} else {
-		gum.FindTool(&args)
+	if args.HasGumFlag("gscan") && args.HasGumFlag("gnoscan") {
+		fmt.Fprintln(os.Stderr, "You cannot define -gscan and -gnoscan flags at the same time")
+		os.Exit(-1)
 	}
-}
 
-func normalize(s string) string {
-	if len(s) > 0 {
-		return s
+	context := gum.NewDefaultContext(false)
+	if command := gum.FindTool(context, &args); command != nil {
+		os.Exit(command.Execute())
 	}
-	return "undefined"
+	os.Exit(gum.ReportNoTool(context, &args))
 }
@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Expands the first argument if it matches an alias. Aliases may start with
+// another alias; each alias is expanded at most once, thus cycles are broken
+func expandAliases(config *Config, args *ParsedArgs) {
+	expanded := make(map[string]bool)
+	for len(args.Args) > 0 {
+		name := args.Args[0]
+		alias, ok := config.aliases[name]
+		if !ok || expanded[name] {
+			return
+		}
+
+		expanded[name] = true
+		args.Args = append(splitCommandLine(alias), args.Args[1:]...)
+	}
+}
+
+// Handles "gm gum alias add|ls|rm [--project]"
+func runAliasCommand(context Context, args *ParsedArgs, params []string) int {
+	project, params := hasOption("--project", params)
+
+	path := findUserConfigFile(context)
+	if project {
+		path = resolveConfigFile(context, resolveProjectRootDir(context), projectConfigFiles)
+	}
+
+	if len(params) == 0 {
+		fmt.Fprintln(gumOutput, "Usage: gm gum alias add <name> <args> | ls | rm <name> [--project]")
+		return -1
+	}
+
+	switch params[0] {
+	case "add":
+		if len(params) < 3 {
+			fmt.Fprintln(gumOutput, "Usage: gm gum alias add <name> <args> [--project]")
+			return -1
+		}
+		err := writeAlias(path, params[1], strings.Join(params[2:], " "))
+		if err != nil {
+			fmt.Fprintln(gumOutput, err)
+			return -1
+		}
+	case "ls":
+		var config *Config
+		if project {
+			config = ReadConfigFile(context, path)
+		} else {
+			config = ReadConfig(context, resolveProjectRootDir(context))
+		}
+		if len(config.aliases) > 0 {
+			config.theme.t.PrintSection("aliases")
+			config.theme.t.PrintMap(config.aliases)
+		}
+	case "rm":
+		if len(params) != 2 {
+			fmt.Fprintln(gumOutput, "Usage: gm gum alias rm <name> [--project]")
+			return -1
+		}
+		removed, err := removeAlias(path, params[1])
+		if err != nil {
+			fmt.Fprintln(gumOutput, err)
+			return -1
+		}
+		if !removed {
+			fmt.Fprintln(gumOutput, "Alias "+params[1]+" not found in "+path)
+			return -1
+		}
+	default:
+		fmt.Fprintln(gumOutput, "Unsupported alias command: "+params[0])
+		return -1
+	}
+
+	return 0
+}
+
+// Adds or replaces an alias in the given config file.
+// The file is edited in place to keep comments and formatting intact
+func writeAlias(path string, name string, value string) error {
+	lines, err := readConfigLines(path)
+	if err != nil {
+		return err
+	}
+
+	entry := formatTomlKey(name) + " = " + strconv.Quote(value)
+	start, end := findTomlSection(lines, "aliases")
+
+	if start == -1 {
+		if len(lines) > 0 && len(strings.TrimSpace(lines[len(lines)-1])) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "[aliases]", entry)
+	} else if i := findTomlKey(lines, start, end, name); i > -1 {
+		lines[i] = entry
+	} else {
+		lines = append(lines[:start+1], append([]string{entry}, lines[start+1:]...)...)
+	}
+
+	return writeConfigLines(path, lines)
+}
+
+// Removes an alias from the given config file
+func removeAlias(path string, name string) (bool, error) {
+	lines, err := readConfigLines(path)
+	if err != nil {
+		return false, err
+	}
+
+	start, end := findTomlSection(lines, "aliases")
+	if start == -1 {
+		return false, nil
+	}
+
+	i := findTomlKey(lines, start, end, name)
+	if i == -1 {
+		return false, nil
+	}
+
+	lines = append(lines[:i], lines[i+1:]...)
+	return true, writeConfigLines(path, lines)
+}
+
+// Reads a TOML config file for editing. YAML files are rejected as these can not be
+// edited in place without losing comments and formatting
+func readConfigLines(path string) ([]string, error) {
+	if isYamlFile(path) {
+		return nil, errors.New("Can not edit " + path + " as it is written in YAML. Please edit it by hand")
+	}
+
+	doc, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return make([]string, 0), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(strings.TrimRight(string(doc), "\n"), "\n"), nil
+}
+
+func writeConfigLines(path string, lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// Finds the line range [start, end) of a TOML section. Start is the header line
+func findTomlSection(lines []string, section string) (int, int) {
+	start := -1
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if start == -1 {
+			if line == "["+section+"]" {
+				start = i
+			}
+		} else if strings.HasPrefix(line, "[") {
+			return start, i
+		}
+	}
+	return start, len(lines)
+}
+
+// Finds the line of a key within a TOML section
+func findTomlKey(lines []string, start int, end int, key string) int {
+	for i := start + 1; i < end; i++ {
+		parts := strings.SplitN(lines[i], "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		k := strings.TrimSpace(parts[0])
+		if k == key || k == strconv.Quote(key) || k == "'"+key+"'" {
+			return i
+		}
+	}
+	return -1
+}
+
+func formatTomlKey(key string) string {
+	for _, r := range key {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return strconv.Quote(key)
+		}
+	}
+	return key
+}
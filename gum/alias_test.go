@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAliasAddAndRemove(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".gm.toml")
+	ioutil.WriteFile(path, []byte("# my settings\n[general]\nquiet = true\n"), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: dir}
+
+	// when:
+	writeAlias(path, "qa", "clean check -x slowTest")
+	writeAlias(path, "ci", "clean build")
+	writeAlias(path, "qa", "clean check")
+	config := ReadConfigFile(context, path)
+
+	// then:
+	if config.aliases["qa"] != "clean check" {
+		t.Errorf("aliases.qa: got %s, want %s", config.aliases["qa"], "clean check")
+	}
+	if config.aliases["ci"] != "clean build" {
+		t.Errorf("aliases.ci: got %s, want %s", config.aliases["ci"], "clean build")
+	}
+	if !config.general.q.WithMaybeAsFalse() {
+		t.Error("general.quiet: got false, want true")
+	}
+
+	// when:
+	removed, _ := removeAlias(path, "qa")
+	config = ReadConfigFile(context, path)
+	doc, _ := ioutil.ReadFile(path)
+
+	// then:
+	if !removed {
+		t.Error("Expected alias qa to be removed")
+	}
+	if _, ok := config.aliases["qa"]; ok {
+		t.Error("Expected alias qa to be missing")
+	}
+	if !strings.HasPrefix(string(doc), "# my settings") {
+		t.Error("Expected comments to be preserved")
+	}
+}
+
+func TestExpandAliases(t *testing.T) {
+	// given:
+	config := newConfig()
+	config.aliases["qa"] = "clean check -x \"slow Test\""
+
+	// when:
+	args := ParseArgs([]string{"-gq", "qa", "-S"})
+	expandAliases(config, &args)
+
+	// then:
+	expected := []string{"clean", "check", "-x", "slow Test", "-S"}
+	if strings.Join(args.Args, ",") != strings.Join(expected, ",") {
+		t.Errorf("args: got %s, want %s", args.Args, expected)
+	}
+}
+
+func TestExpandNestedAliases(t *testing.T) {
+	// given:
+	config := newConfig()
+	config.aliases["qa"] = "clean check"
+	config.aliases["ci"] = "qa build --no-daemon --stacktrace"
+	config.aliases["loop"] = "loop build"
+
+	var checks = []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"ci", "-S"}, "clean check build --no-daemon --stacktrace -S"},
+		{[]string{"loop"}, "loop build"},
+		{[]string{"build", "qa"}, "build qa"},
+	}
+
+	for _, check := range checks {
+		// when:
+		args := ParseArgs(check.args)
+		expandAliases(config, &args)
+
+		// then:
+		if strings.Join(args.Args, " ") != check.expected {
+			t.Errorf("args: got %s, want %s", args.Args, check.expected)
+		}
+	}
+}
+
+func TestAliasAddWithYamlUserConfig(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	configDir := filepath.Join(dir, "gum")
+	os.MkdirAll(configDir, 0755)
+	ioutil.WriteFile(filepath.Join(configDir, "gm.yml"), []byte("general:\n  quiet: true\n"), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: dir,
+		homeDir:    dir,
+		env:        map[string]string{"GUM_HOME": configDir}}
+
+	// when:
+	result := runAliasCommand(context, &ParsedArgs{}, []string{"add", "qa", "clean", "check"})
+
+	// then:
+	if result == 0 {
+		t.Error("Expected alias add to fail for a YAML config")
+	}
+	if context.FileExists(filepath.Join(configDir, "gm.toml")) {
+		t.Error("Expected gm.toml not to be created")
+	}
+}
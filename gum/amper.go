@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Amper descriptors. Leaf modules define module.yaml instead of a Gradle build file
+const (
+	amperModuleFile  = "module.yaml"
+	amperProjectFile = "project.yaml"
+	amperPluginID    = "org.jetbrains.amper"
+)
+
+// Checks if the Gradle build given by its settings file is an Amper project,
+// either by applying the Amper settings plugin or by defining project.yaml
+func isAmperProject(context Context, settingsFile string) bool {
+	if len(settingsFile) == 0 {
+		return false
+	}
+
+	if context.FileExists(filepath.Join(filepath.Dir(settingsFile), amperProjectFile)) {
+		return true
+	}
+
+	data, err := ioutil.ReadFile(settingsFile)
+	return err == nil && strings.Contains(string(data), amperPluginID)
+}
+
+// Checks if the given file is an Amper descriptor
+func isAmperDescriptor(file string) bool {
+	name := filepath.Base(file)
+	return name == amperModuleFile || name == amperProjectFile
+}
+
+// Finds the nearest Amper module file, stopping at the given root dir
+func findAmperModuleFile(context Context, dir string, rootdir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	path := filepath.Join(dir, amperModuleFile)
+	if context.FileExists(path) {
+		return filepath.Abs(path)
+	}
+
+	abs, _ := filepath.Abs(dir)
+	if abs == rootdir || parentdir == dir {
+		return "", errors.New("Did not find Amper module file")
+	}
+
+	return findAmperModuleFile(context, parentdir, rootdir)
+}
+
+// Selects the nearest of the Gradle build file and the Amper module file
+func nearestGradleOrAmperFile(buildFile string, moduleFile string) string {
+	if len(buildFile) == 0 || len(filepath.Dir(moduleFile)) > len(filepath.Dir(buildFile)) {
+		return moduleFile
+	}
+	return buildFile
+}
+
+// Reads the project paths of the modules listed in project.yaml, such as
+// "- ./app" or "- ./libs/*". Only dirs that define module.yaml are included
+func readAmperModules(rootdir string) []string {
+	includes := make([]string, 0)
+
+	file, err := os.Open(filepath.Join(rootdir, amperProjectFile))
+	if err != nil {
+		return includes
+	}
+	defer file.Close()
+
+	inModules := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			inModules = strings.HasPrefix(trimmed, "modules:")
+			continue
+		}
+		if !inModules || !strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+
+		pattern := strings.Trim(strings.TrimSpace(trimmed[1:]), `"'`)
+		matches, _ := filepath.Glob(filepath.Join(rootdir, filepath.FromSlash(pattern)))
+		for _, match := range matches {
+			if !isRegularFile(filepath.Join(match, amperModuleFile)) {
+				continue
+			}
+			if path := gradleProjectPath(rootdir, match); len(path) > 0 {
+				includes = append(includes, path)
+			}
+		}
+	}
+
+	return includes
+}
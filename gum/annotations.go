@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Checks if compiler messages and test failures should be reported as GitHub Actions
+// workflow commands, as set with general.annotations when running under GitHub Actions
+func isGitHubAnnotations(context Context, config *Config) bool {
+	return config.general.annotations && context.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// Prints compiler messages and failed tests as workflow commands, thus they show up as
+// annotations of the workflow run and pull request. Workflow commands must go to stdout
+func printGitHubAnnotations(context Context, compiler *compilerWatcher, tests *testResults) {
+	workspace := context.Getenv("GITHUB_WORKSPACE")
+	if compiler != nil {
+		for _, m := range compiler.messages {
+			fmt.Fprintln(os.Stdout, formatGitHubAnnotation(m, workspace))
+		}
+	}
+	if tests != nil {
+		for _, name := range tests.failed {
+			fmt.Fprintln(os.Stdout, "::error title=Test failed::"+escapeWorkflowData(name))
+		}
+	}
+}
+
+// Formats a compiler message such as ::error file=src/Foo.java,line=12::cannot find symbol.
+// Files are made relative to the workspace, as expected by GitHub
+func formatGitHubAnnotation(m compilerMessage, workspace string) string {
+	file := strings.Replace(relativeTo(workspace, m.file), "\\", "/", -1)
+	return "::" + m.severity +
+		" file=" + escapeWorkflowProperty(file) +
+		",line=" + strconv.Itoa(m.line) +
+		"::" + escapeWorkflowData(m.message)
+}
+
+func escapeWorkflowData(s string) string {
+	s = strings.Replace(s, "%", "%25", -1)
+	s = strings.Replace(s, "\r", "%0D", -1)
+	return strings.Replace(s, "\n", "%0A", -1)
+}
+
+func escapeWorkflowProperty(s string) string {
+	s = escapeWorkflowData(s)
+	s = strings.Replace(s, ":", "%3A", -1)
+	return strings.Replace(s, ",", "%2C", -1)
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"testing"
+)
+
+func TestFormatGitHubAnnotation(t *testing.T) {
+	var checks = []struct {
+		title     string
+		message   compilerMessage
+		workspace string
+		expected  string
+	}{
+		{"Error", compilerMessage{"error", "/w/src/Foo.java", 12, "cannot find symbol"}, "/w",
+			"::error file=src/Foo.java,line=12::cannot find symbol"},
+		{"Warning", compilerMessage{"warning", "/w/src/Foo.kt", 3, "100% unused"}, "/w",
+			"::warning file=src/Foo.kt,line=3::100%25 unused"},
+		{"OutsideWorkspace", compilerMessage{"error", "/o/a,b.java", 1, "oops"}, "/w",
+			"::error file=/o/a%2Cb.java,line=1::oops"},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := formatGitHubAnnotation(check.message, check.workspace)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestIsGitHubAnnotations(t *testing.T) {
+	var checks = []struct {
+		title       string
+		annotations bool
+		env         map[string]string
+		expected    bool
+	}{
+		{"Enabled", true, map[string]string{"GITHUB_ACTIONS": "true"}, true},
+		{"NotOnGitHub", true, map[string]string{}, false},
+		{"Disabled", false, map[string]string{"GITHUB_ACTIONS": "true"}, false},
+	}
+
+	for _, check := range checks {
+		// given:
+		context := testContext{env: check.env}
+		config := newConfig()
+		config.general.annotations = check.annotations
+
+		// when:
+		actual := isGitHubAnnotations(context, config)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %t, want %t", check.title, actual, check.expected)
+		}
+	}
+}
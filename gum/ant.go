@@ -20,7 +20,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -32,14 +31,15 @@ type AntCommand struct {
 	rootdir           string
 	executable        string
 	args              *ParsedArgs
+	rootBuildFile     string
 	buildFile         string
 	explicitBuildFile string
 }
 
 // Execute executes the given command
-func (c AntCommand) Execute() {
+func (c AntCommand) Execute() int {
 	c.doConfigureAnt()
-	c.doExecuteAnt()
+	return ExitCode(c.doExecuteAnt())
 }
 
 func (c *AntCommand) doConfigureAnt() {
@@ -47,41 +47,72 @@ func (c *AntCommand) doConfigureAnt() {
 
 	banner := make([]string, 0)
 	banner = append(banner, "Using Ant at '"+c.executable+"'")
-
+	nearest := c.args.HasGumFlag("gn")
 	debug := c.args.HasGumFlag("gd")
 
 	if debug {
 		c.config.setDebug(debug)
 	}
 	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
 	oargs := c.args.Args
 
 	if len(c.explicitBuildFile) > 0 {
 		args = append(args, "-f")
 		args = append(args, c.explicitBuildFile)
 		banner = append(banner, "to run buildFile '"+c.explicitBuildFile+"':")
-	} else if len(c.buildFile) > 0 {
+	} else if nearest && len(c.buildFile) > 0 {
 		args = append(args, "-f")
 		args = append(args, c.buildFile)
 		banner = append(banner, "to run buildFile '"+c.buildFile+"':")
+	} else if len(c.rootBuildFile) > 0 {
+		args = append(args, "-f")
+		args = append(args, c.rootBuildFile)
+		banner = append(banner, "to run buildFile '"+c.rootBuildFile+"':")
 	}
 
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "ant"))
 	args = appendSafe(args, c.args.Tool)
-	args = append(args, "-Dbasedir="+c.rootdir)
+	args = append(args, "-Dbasedir="+filepath.Dir(c.resolveBuildFile()))
 	c.args.Args = appendSafe(args, oargs)
 
 	c.debugAnt(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
 
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
 	}
 }
 
-func (c *AntCommand) doExecuteAnt() {
-	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Run()
+func (c *AntCommand) doExecuteAnt() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *AntCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *AntCommand) invocation() invocation {
+	buildFile := c.resolveBuildFile()
+	return invocation{
+		tool:       "ant",
+		executable: c.executable,
+		rootDir:    filepath.Dir(buildFile),
+		buildFile:  buildFile,
+		args:       c.args}
+}
+
+// Resolves the build file used by the invocation
+func (c *AntCommand) resolveBuildFile() string {
+	if len(c.explicitBuildFile) > 0 {
+		return c.explicitBuildFile
+	} else if c.args.HasGumFlag("gn") && len(c.buildFile) > 0 {
+		return c.buildFile
+	}
+	return c.rootBuildFile
 }
 
 func (c *AntCommand) debugConfig() {
@@ -93,13 +124,15 @@ func (c *AntCommand) debugConfig() {
 
 func (c *AntCommand) debugAnt(config *Config, oargs []string) {
 	if c.config.general.debug {
-		fmt.Println("rootdir            = ", c.rootdir)
-		fmt.Println("executable         = ", c.executable)
-		fmt.Println("buildFile          = ", c.buildFile)
-		fmt.Println("explicitBuildFile  = ", c.explicitBuildFile)
-		fmt.Println("original args      = ", oargs)
-		fmt.Println("actual args        = ", c.args.Args)
-		fmt.Println("")
+		fmt.Fprintln(gumOutput, "nearest            = ", c.args.HasGumFlag("gn"))
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "rootBuildFile      = ", c.rootBuildFile)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "explicitBuildFile  = ", c.explicitBuildFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
 	}
 }
 
@@ -109,9 +142,10 @@ func FindAnt(context Context, args *ParsedArgs) *AntCommand {
 
 	ant, noAnt := findAntExec(context)
 	explicitBuildFileSet, explicitBuildFile := findExplicitAntBuildFile(args)
+	rootBuildFile, noRootBuildFile := findAntBuildFile(context, filepath.Join(pwd, ".."))
 	buildFile, noBuildFile := findAntBuildFile(context, pwd)
 
-	rootdir := resolveAntRootDir(context, explicitBuildFile, buildFile)
+	rootdir := resolveAntRootDir(context, explicitBuildFile, buildFile, rootBuildFile)
 	config := ReadConfig(context, rootdir)
 	quiet := args.HasGumFlag("gq")
 
@@ -140,44 +174,52 @@ func FindAnt(context Context, args *ParsedArgs) *AntCommand {
 			explicitBuildFile: explicitBuildFile}
 	}
 
+	if noRootBuildFile != nil {
+		rootBuildFile = buildFile
+	}
+
 	if noBuildFile != nil {
 		if context.IsExplicit() {
-			fmt.Println("No Ant project found")
-			fmt.Println()
+			fmt.Fprintln(gumOutput, "No Ant project found")
+			fmt.Fprintln(gumOutput)
 			context.Exit(-1)
 		}
 		return nil
 	}
 
 	return &AntCommand{
-		context:    context,
-		config:     config,
-		rootdir:    rootdir,
-		executable: executable,
-		args:       args,
-		buildFile:  buildFile}
+		context:       context,
+		config:        config,
+		rootdir:       rootdir,
+		executable:    executable,
+		args:          args,
+		rootBuildFile: rootBuildFile,
+		buildFile:     buildFile}
 }
 
 func resolveAntRootDir(context Context,
 	explicitBuildFile string,
-	buildFile string) string {
+	buildFile string,
+	rootBuildFile string) string {
 
 	if context.FileExists(explicitBuildFile) {
 		return filepath.Dir(explicitBuildFile)
+	} else if context.FileExists(rootBuildFile) {
+		return filepath.Dir(rootBuildFile)
 	}
 	return filepath.Dir(buildFile)
 }
 
 func warnNoAnt(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s found in path. Please install Ant.", resolveAntExec(context))
-		fmt.Println()
-		fmt.Println("(https://ant.apache.org/bindownload.cgi)")
-		fmt.Println()
+		fmt.Fprintf(gumOutput, "No %s found in path or $ANT_HOME. Please install Ant.", resolveAntExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://ant.apache.org/bindownload.cgi)")
+		fmt.Fprintln(gumOutput)
 	}
 }
 
-// Finds the ant executable
+// Finds the ant executable, either in path or in $ANT_HOME/bin
 func findAntExec(context Context) (string, error) {
 	ant := resolveAntExec(context)
 	paths := context.GetPaths()
@@ -189,6 +231,14 @@ func findAntExec(context Context) (string, error) {
 		}
 	}
 
+	antHome := context.Getenv("ANT_HOME")
+	if len(antHome) > 0 {
+		name := filepath.Join(antHome, "bin", ant)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
 	return "", errors.New(ant + " not found")
 }
 
@@ -225,6 +275,10 @@ func findAntBuildFile(context Context, dir string) (string, error) {
 		return filepath.Abs(path)
 	}
 
+	if isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find build.xml")
+	}
+
 	return findAntBuildFile(context, parentdir)
 }
 
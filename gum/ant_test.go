@@ -47,6 +47,7 @@ func TestAntSingle(t *testing.T) {
 		title, actual, expected string
 	}{
 		{"Executable", cmd.executable, filepath.Join(bin, "ant")},
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(pwd, "build.xml")},
 		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "build.xml")},
 		{"ExplicitBuildFile", cmd.explicitBuildFile, ""},
 	}
@@ -84,6 +85,7 @@ func TestAntParent(t *testing.T) {
 		title, actual, expected string
 	}{
 		{"Executable", cmd.executable, filepath.Join(bin, "ant")},
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(pwd, "..", "build.xml")},
 		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "build.xml")},
 		{"ExplicitBuildFile", cmd.explicitBuildFile, ""},
 	}
@@ -157,3 +159,71 @@ func TestAntWithoutExecutables(t *testing.T) {
 		t.Error("Expected a nil command but got something")
 	}
 }
+
+func TestAntParentNearest(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "ant", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "ant", "parent", "child"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "-gn", "build"})
+	cmd := FindAnt(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureAnt()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"BuildFileFlag", cmd.args.Args[0], "-f"},
+		{"BuildFile", cmd.args.Args[1], filepath.Join(pwd, "build.xml")},
+		{"BaseDir", cmd.args.Args[2], "-Dbasedir=" + pwd},
+		{"Target", cmd.args.Args[3], "build"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestAntFromAntHome(t *testing.T) {
+	// given:
+	home, _ := filepath.Abs(filepath.Join("..", "tests", "ant", "home"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "ant", "single"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		env:        map[string]string{"ANT_HOME": home},
+		paths:      []string{}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindAnt(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	if cmd.executable != filepath.Join(home, "bin", "ant") {
+		t.Errorf("Executable: got %s, want %s", cmd.executable, filepath.Join(home, "bin", "ant"))
+	}
+}
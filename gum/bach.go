@@ -20,7 +20,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -35,9 +34,9 @@ type BachCommand struct {
 }
 
 // Execute executes the given command
-func (c BachCommand) Execute() {
+func (c BachCommand) Execute() int {
 	c.doConfigureBach()
-	c.doExecuteBach()
+	return ExitCode(c.doExecuteBach())
 }
 
 func (c *BachCommand) doConfigureBach() {
@@ -52,27 +51,41 @@ func (c *BachCommand) doConfigureBach() {
 		c.config.setDebug(debug)
 	}
 	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
 	oargs := c.args.Args
 
 	execParts := strings.Split(c.executable, " ")
 	c.executable = execParts[0]
 
 	args = appendSafe(args, execParts[1:])
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "bach"))
 	args = appendSafe(args, c.args.Tool)
 	c.args.Args = appendSafe(args, oargs)
 
 	c.debugBach(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
 
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
 	}
 }
 
-func (c *BachCommand) doExecuteBach() {
-	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Run()
+func (c *BachCommand) doExecuteBach() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *BachCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *BachCommand) invocation() invocation {
+	return invocation{
+		tool:       "bach",
+		executable: c.executable,
+		args:       c.args}
 }
 
 func (c *BachCommand) debugConfig() {
@@ -84,11 +97,11 @@ func (c *BachCommand) debugConfig() {
 
 func (c *BachCommand) debugBach(config *Config, oargs []string) {
 	if c.config.general.debug {
-		fmt.Println("rootdir            = ", c.rootdir)
-		fmt.Println("executable         = ", c.executable)
-		fmt.Println("original args      = ", oargs)
-		fmt.Println("actual args        = ", c.args.Args)
-		fmt.Println("")
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
 	}
 }
 
@@ -117,8 +130,8 @@ func FindBach(context Context, args *ParsedArgs) *BachCommand {
 
 	if noRootdir != nil {
 		if context.IsExplicit() {
-			fmt.Println("No Bach project found")
-			fmt.Println()
+			fmt.Fprintln(gumOutput, "No Bach project found")
+			fmt.Fprintln(gumOutput)
 			context.Exit(-1)
 		}
 		return nil
@@ -128,8 +141,8 @@ func FindBach(context Context, args *ParsedArgs) *BachCommand {
 	r, _ := filepath.Abs(rootdir)
 	if p != r {
 		if context.IsExplicit() {
-			fmt.Println("Bach must be invoked from " + rootdir)
-			fmt.Println()
+			fmt.Fprintln(gumOutput, "Bach must be invoked from "+rootdir)
+			fmt.Fprintln(gumOutput)
 			context.Exit(-1)
 		}
 		return nil
@@ -155,12 +168,16 @@ func resolveBachRootDir(context Context, dir string) (string, error) {
 		return filepath.Abs(dir)
 	}
 
+	if isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find root")
+	}
+
 	return resolveBachRootDir(context, parentdir)
 }
 
 func warnNoBach(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Println("No java/jshell found in path. Please install Java 16+")
+		fmt.Fprintln(gumOutput, "No java/jshell found in path. Please install Java 16+")
 	}
 }
 
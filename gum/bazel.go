@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// bazelWorkspaceFiles lists the files that mark the root of a Bazel workspace
+var bazelWorkspaceFiles = []string{"MODULE.bazel", "WORKSPACE.bazel", "WORKSPACE"}
+
+// BazelCommand defines an executable Bazel command
+type BazelCommand struct {
+	context       Context
+	config        *Config
+	rootdir       string
+	executable    string
+	args          *ParsedArgs
+	workspaceFile string
+}
+
+// Execute executes the given command
+func (c BazelCommand) Execute() int {
+	c.doConfigureBazel()
+	return ExitCode(c.doExecuteBazel())
+}
+
+func (c *BazelCommand) doConfigureBazel() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using Bazel at '"+c.executable+"'")
+	banner = append(banner, "to run workspace '"+c.rootdir+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "bazel"))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugBazel(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *BazelCommand) doExecuteBazel() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *BazelCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *BazelCommand) invocation() invocation {
+	return invocation{
+		tool:       "bazel",
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		buildFile:  c.workspaceFile,
+		args:       c.args}
+}
+
+func (c *BazelCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *BazelCommand) debugBazel(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "workspaceFile      = ", c.workspaceFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindBazel finds and executes Bazel
+func FindBazel(context Context, args *ParsedArgs) *BazelCommand {
+	pwd := context.GetWorkingDir()
+
+	workspaceFile, noWorkspaceFile := findBazelWorkspaceFile(context, pwd)
+	if noWorkspaceFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No Bazel workspace found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	rootdir := filepath.Dir(workspaceFile)
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	bazelisk, noBazelisk := findBazelExec(context, resolveBazeliskExec(context))
+	bazel, noBazel := findBazelExec(context, resolveBazelExec(context))
+
+	var executable string
+	if noBazelisk == nil {
+		executable = bazelisk
+	} else if noBazel == nil {
+		executable = bazel
+	} else {
+		warnNoBazel(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &BazelCommand{
+		context:       context,
+		config:        config,
+		rootdir:       rootdir,
+		executable:    executable,
+		args:          args,
+		workspaceFile: workspaceFile}
+}
+
+func warnNoBazel(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s nor %s found in path. Please install Bazelisk.", resolveBazeliskExec(context), resolveBazelExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://github.com/bazelbuild/bazelisk)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the nearest workspace file, which marks the root of the workspace
+func findBazelWorkspaceFile(context Context, dir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	if parentdir == dir {
+		return "", errors.New("Did not find a Bazel workspace")
+	}
+
+	for _, name := range bazelWorkspaceFiles {
+		path := filepath.Join(dir, name)
+		if context.FileExists(path) {
+			return filepath.Abs(path)
+		}
+	}
+
+	if isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find a Bazel workspace")
+	}
+
+	return findBazelWorkspaceFile(context, parentdir)
+}
+
+// Finds the given executable (either bazelisk or bazel)
+func findBazelExec(context Context, bazel string) (string, error) {
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], bazel)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(bazel + " not found")
+}
+
+// Resolves the bazelisk executable (OS dependent)
+func resolveBazeliskExec(context Context) string {
+	if context.IsWindows() {
+		return "bazelisk.exe"
+	}
+	return "bazelisk"
+}
+
+// Resolves the bazel executable (OS dependent)
+func resolveBazelExec(context Context) string {
+	if context.IsWindows() {
+		return "bazel.exe"
+	}
+	return "bazel"
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBazelWorkspaceFromPackage(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "workspace"))
+	pwd := filepath.Join(root, "pkg", "sub")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build", "//..."})
+	cmd := FindBazel(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureBazel()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "bazel")},
+		{"RootDir", cmd.rootdir, root},
+		{"WorkspaceFile", cmd.workspaceFile, filepath.Join(root, "WORKSPACE")},
+		{"Command", cmd.args.Args[0], "build"},
+		{"Target", cmd.args.Args[1], "//..."},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestBazelModulePrefersBazelisk(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "bazelisk"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "module"))
+	pwd := filepath.Join(root, "app")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindBazel(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "bazelisk")},
+		{"RootDir", cmd.rootdir, root},
+		{"WorkspaceFile", cmd.workspaceFile, filepath.Join(root, "MODULE.bazel")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestBazelWithoutExecutables(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "workspace"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindBazel(context, &args)
+
+	// then:
+	if cmd != nil {
+		t.Error("Expected a nil command but got something")
+	}
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BldCommand defines an executable bld command
+type BldCommand struct {
+	context    Context
+	config     *Config
+	rootdir    string
+	executable string
+	args       *ParsedArgs
+}
+
+// Execute executes the given command
+func (c BldCommand) Execute() int {
+	c.doConfigureBld()
+	return ExitCode(c.doExecuteBld())
+}
+
+func (c *BldCommand) doConfigureBld() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using bld at '"+c.executable+"'")
+	banner = append(banner, "to run project at '"+c.rootdir+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "bld"))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugBld(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *BldCommand) doExecuteBld() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *BldCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *BldCommand) invocation() invocation {
+	return invocation{
+		tool:       "bld",
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		workDir:    c.rootdir,
+		args:       c.args}
+}
+
+func (c *BldCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *BldCommand) debugBld(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindBld finds and executes bld
+func FindBld(context Context, args *ParsedArgs) *BldCommand {
+	pwd := context.GetWorkingDir()
+
+	rootdir, noRootDir := findBldRootDir(context, pwd)
+	if noRootDir != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No bld project found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	bldw := filepath.Join(rootdir, resolveBldWrapperExec(context))
+	bld, noBld := findBldExec(context)
+
+	var executable string
+	if isRegularFile(bldw) {
+		executable = bldw
+	} else if noBld == nil {
+		executable = bld
+	} else {
+		warnNoBld(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &BldCommand{
+		context:    context,
+		config:     config,
+		rootdir:    rootdir,
+		executable: executable,
+		args:       args}
+}
+
+func warnNoBld(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s set up for this project nor found in path.", resolveBldWrapperExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://rife2.com/bld)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the nearest directory with either a bld wrapper or the src/bld/java convention
+func findBldRootDir(context Context, dir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	if parentdir == dir {
+		return "", errors.New("Did not find a bld project")
+	}
+
+	if isRegularFile(filepath.Join(dir, resolveBldWrapperExec(context))) ||
+		context.FileExists(filepath.Join(dir, "src", "bld", "java")) {
+		return filepath.Abs(dir)
+	}
+
+	if isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find a bld project")
+	}
+
+	return findBldRootDir(context, parentdir)
+}
+
+// Finds the bld executable
+func findBldExec(context Context) (string, error) {
+	bld := resolveBldWrapperExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], bld)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(bld + " not found")
+}
+
+// Resolves the bld wrapper (OS dependent)
+func resolveBldWrapperExec(context Context) string {
+	if context.IsWindows() {
+		return "bld.bat"
+	}
+	return "bld"
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BootCommand defines an executable Boot command
+type BootCommand struct {
+	context    Context
+	config     *Config
+	rootdir    string
+	executable string
+	args       *ParsedArgs
+	buildFile  string
+}
+
+// Execute executes the given command
+func (c BootCommand) Execute() int {
+	c.doConfigureBoot()
+	return ExitCode(c.doExecuteBoot())
+}
+
+func (c *BootCommand) doConfigureBoot() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using boot at '"+c.executable+"'")
+	banner = append(banner, "to run buildFile '"+c.buildFile+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "boot"))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugBoot(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *BootCommand) doExecuteBoot() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the invocation of the configured command.
+// Resolves the config of the command
+func (c *BootCommand) configuration() *Config {
+	return c.config
+}
+
+// Boot reads build.boot from the current dir thus it's launched from there
+func (c *BootCommand) invocation() invocation {
+	return invocation{
+		tool:       "boot",
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		buildFile:  c.buildFile,
+		workDir:    c.rootdir,
+		args:       c.args}
+}
+
+func (c *BootCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *BootCommand) debugBoot(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindBoot finds and executes boot
+func FindBoot(context Context, args *ParsedArgs) *BootCommand {
+	pwd := context.GetWorkingDir()
+
+	buildFile, noBuildFile := findBootBuildFile(context, pwd)
+	if noBuildFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No Boot project found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	rootdir := filepath.Dir(buildFile)
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	bootw, noWrapper := findBootWrapperExec(context, rootdir)
+	boot, noBoot := findBootExec(context)
+
+	var executable string
+	if noWrapper == nil {
+		executable = bootw
+	} else if noBoot == nil {
+		executable = boot
+	} else {
+		warnNoBoot(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &BootCommand{
+		context:    context,
+		config:     config,
+		rootdir:    rootdir,
+		executable: executable,
+		args:       args,
+		buildFile:  buildFile}
+}
+
+func warnNoBoot(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s found in path. Please install Boot.", resolveBootExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://github.com/boot-clj/boot#install)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the nearest build.boot file
+func findBootBuildFile(context Context, dir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	path := filepath.Join(dir, "build.boot")
+	if context.FileExists(path) {
+		return filepath.Abs(path)
+	}
+
+	if parentdir == dir || isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find build.boot")
+	}
+
+	return findBootBuildFile(context, parentdir)
+}
+
+// Finds a boot launcher script checked in next to build.boot
+func findBootWrapperExec(context Context, dir string) (string, error) {
+	wrapper := filepath.Join(dir, resolveBootExec(context))
+	if isRegularFile(wrapper) {
+		return filepath.Abs(wrapper)
+	}
+	return "", errors.New(wrapper + " not found")
+}
+
+// Finds the boot executable
+func findBootExec(context Context) (string, error) {
+	boot := resolveBootExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], boot)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(boot + " not found")
+}
+
+// Resolves the boot executable (OS dependent)
+func resolveBootExec(context Context) string {
+	if context.IsWindows() {
+		return "boot.exe"
+	}
+	return "boot"
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBoot(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "boot", "bin"))
+	simple, _ := filepath.Abs(filepath.Join("..", "tests", "boot", "simple"))
+	launcher, _ := filepath.Abs(filepath.Join("..", "tests", "boot", "with-launcher"))
+
+	var checks = []struct {
+		title, pwd, buildFile, executable string
+	}{
+		{"FromPath", filepath.Join(simple, "src"), filepath.Join(simple, "build.boot"), filepath.Join(bin, "boot")},
+		{"Launcher", launcher, filepath.Join(launcher, "build.boot"), filepath.Join(launcher, "boot")},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: check.pwd,
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs([]string{"-gq", "build"})
+		cmd := FindBoot(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+		if cmd.buildFile != check.buildFile {
+			t.Errorf("%s: buildFile got %s, want %s", check.title, cmd.buildFile, check.buildFile)
+		}
+		if cmd.executable != check.executable {
+			t.Errorf("%s: executable got %s, want %s", check.title, cmd.executable, check.executable)
+		}
+		if cmd.invocation().workDir != filepath.Dir(check.buildFile) {
+			t.Errorf("%s: workDir got %s, want %s", check.title, cmd.invocation().workDir, filepath.Dir(check.buildFile))
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// branchDefaults defines default args applied when the current
+// git branch matches a pattern such as "release/*"
+type branchDefaults struct {
+	pattern string
+	args    map[string][]string
+}
+
+// Resolves the default args for the given tool that match the current git branch
+func resolveBranchArgs(context Context, config *Config, tool string) []string {
+	args := make([]string, 0)
+	if len(config.branches) == 0 {
+		return args
+	}
+
+	branch := resolveGitBranch(context, context.GetWorkingDir())
+	if len(branch) == 0 {
+		return args
+	}
+
+	for _, b := range config.branches {
+		if matched, _ := path.Match(b.pattern, branch); matched {
+			args = append(args, b.args[tool]...)
+		}
+	}
+
+	return args
+}
+
+// Resolves the current git branch by reading .git/HEAD.
+// Returns an empty string if dir is not inside a git repository or HEAD is detached
+func resolveGitBranch(context Context, dir string) string {
+	parentdir := filepath.Join(dir, "..")
+
+	if parentdir == dir {
+		return ""
+	}
+
+	gitdir := filepath.Join(dir, ".git")
+	if !context.FileExists(gitdir) {
+		return resolveGitBranch(context, parentdir)
+	}
+
+	head := filepath.Join(gitdir, "HEAD")
+	if !context.FileExists(head) {
+		// worktrees and submodules use a file pointing to the actual git dir
+		data, err := ioutil.ReadFile(gitdir)
+		if err != nil {
+			return ""
+		}
+		line := strings.TrimSpace(string(data))
+		if !strings.HasPrefix(line, "gitdir:") {
+			return ""
+		}
+		target := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(dir, target)
+		}
+		head = filepath.Join(target, "HEAD")
+	}
+
+	data, err := ioutil.ReadFile(head)
+	if err != nil {
+		return ""
+	}
+
+	ref := strings.TrimSpace(string(data))
+	if strings.HasPrefix(ref, "ref: refs/heads/") {
+		return strings.TrimPrefix(ref, "ref: refs/heads/")
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+)
+
+func TestBranchArgs(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+	os.MkdirAll(filepath.Join(dir, "child"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/release/1.0\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, ".gm.toml"), []byte(`
+[branch."release/*".gradle]
+defaults = ["-Prelease"]
+
+[branch."main".gradle]
+defaults = ["-Psnapshot"]
+
+[branch."*".maven]
+defaults = ["-Pany"]
+`), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: filepath.Join(dir, "child")}
+
+	// when:
+	config := ReadConfigFile(context, filepath.Join(dir, ".gm.toml"))
+	branch := resolveGitBranch(context, context.workingDir)
+	gradle := resolveBranchArgs(context, config, "gradle")
+	maven := resolveBranchArgs(context, config, "maven")
+
+	// then:
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Branch", branch, "release/1.0"},
+		{"Gradle", strings.Join(gradle, " "), "-Prelease"},
+		{"Maven", strings.Join(maven, " "), ""},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestResolveSectionBranchWithInvalidTables(t *testing.T) {
+	var checks = []struct {
+		title, doc string
+	}{
+		{"Branch", "branch = 1\n"},
+		{"Pattern", "[branch]\n\"release/*\" = \"-Prelease\"\n"},
+	}
+
+	for _, check := range checks {
+		// given:
+		tree, _ := toml.Load(check.doc)
+		config := newConfig()
+
+		// when:
+		resolveSectionBranch(tree, config)
+
+		// then:
+		if len(config.branches) != 0 {
+			t.Errorf("%s: got %d branches, want 0", check.title, len(config.branches))
+		}
+	}
+}
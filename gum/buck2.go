@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// buck2BuildFiles lists the names of Buck2 package files
+var buck2BuildFiles = []string{"BUCK.v2", "BUCK"}
+
+// Buck2Command defines an executable Buck2 command
+type Buck2Command struct {
+	context     Context
+	config      *Config
+	rootdir     string
+	projectRoot string
+	executable  string
+	args        *ParsedArgs
+	buildFile   string
+}
+
+// Execute executes the given command
+func (c Buck2Command) Execute() int {
+	c.doConfigureBuck2()
+	return ExitCode(c.doExecuteBuck2())
+}
+
+func (c *Buck2Command) doConfigureBuck2() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using Buck2 at '"+c.executable+"'")
+	banner = append(banner, "to run cell '"+c.rootdir+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "buck2"))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugBuck2(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *Buck2Command) doExecuteBuck2() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the invocation of the configured command.
+// Resolves the config of the command
+func (c *Buck2Command) configuration() *Config {
+	return c.config
+}
+
+// Target patterns are relative to the current dir thus Buck2 is launched from there
+func (c *Buck2Command) invocation() invocation {
+	return invocation{
+		tool:       "buck2",
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		buildFile:  c.buildFile,
+		args:       c.args}
+}
+
+func (c *Buck2Command) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *Buck2Command) debugBuck2(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "projectRoot        = ", c.projectRoot)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindBuck2 finds and executes Buck2
+func FindBuck2(context Context, args *ParsedArgs) *Buck2Command {
+	pwd := context.GetWorkingDir()
+
+	rootdir, noRootDir := findBuck2CellRoot(context, pwd)
+	if noRootDir != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No Buck2 project found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	projectRoot := findBuck2ProjectRoot(context, rootdir)
+	buildFile, _ := findBuck2BuildFile(context, pwd, rootdir)
+	config := ReadConfig(context, projectRoot)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	buck2w, noWrapper := findBuck2WrapperExec(context, projectRoot)
+	buck2, noBuck2 := findBuck2Exec(context)
+
+	var executable string
+	if noWrapper == nil {
+		executable = buck2w
+	} else if noBuck2 == nil {
+		executable = buck2
+	} else {
+		warnNoBuck2(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &Buck2Command{
+		context:     context,
+		config:      config,
+		rootdir:     rootdir,
+		projectRoot: projectRoot,
+		executable:  executable,
+		args:        args,
+		buildFile:   buildFile}
+}
+
+func warnNoBuck2(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s found in path. Please install Buck2.", resolveBuck2Exec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://buck2.build/docs/getting_started/)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the cell root, the nearest dir with a .buckconfig file
+func findBuck2CellRoot(context Context, dir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	if isBuck2Cell(context, dir) {
+		return filepath.Abs(dir)
+	}
+
+	if parentdir == dir || isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find .buckconfig")
+	}
+
+	return findBuck2CellRoot(context, parentdir)
+}
+
+// Finds the project root, marked with .buckroot or else the topmost dir with a .buckconfig file
+func findBuck2ProjectRoot(context Context, cellRoot string) string {
+	root := cellRoot
+	dir := cellRoot
+
+	for {
+		if context.FileExists(filepath.Join(dir, ".buckroot")) {
+			return dir
+		}
+		if isBuck2Cell(context, dir) {
+			root = dir
+		}
+
+		parentdir := filepath.Dir(dir)
+		if parentdir == dir {
+			return root
+		}
+		dir = parentdir
+	}
+}
+
+// Checks if dir defines a .buckconfig file. $HOME/.buckconfig holds user settings thus it's not a cell
+func isBuck2Cell(context Context, dir string) bool {
+	abs, _ := filepath.Abs(dir)
+	home, _ := filepath.Abs(context.GetHomeDir())
+	return abs != home && context.FileExists(filepath.Join(dir, ".buckconfig"))
+}
+
+// Finds the nearest package file, stopping at the cell root
+func findBuck2BuildFile(context Context, dir string, rootdir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	for _, name := range buck2BuildFiles {
+		path := filepath.Join(dir, name)
+		if isRegularFile(path) {
+			return filepath.Abs(path)
+		}
+	}
+
+	abs, _ := filepath.Abs(dir)
+	if abs == rootdir || parentdir == dir {
+		return "", errors.New("Did not find BUCK file")
+	}
+
+	return findBuck2BuildFile(context, parentdir, rootdir)
+}
+
+// Finds a project local buck2 launcher, such as a DotSlash file
+func findBuck2WrapperExec(context Context, projectRoot string) (string, error) {
+	wrapper := filepath.Join(projectRoot, resolveBuck2Exec(context))
+	if isRegularFile(wrapper) {
+		return wrapper, nil
+	}
+	return "", errors.New(wrapper + " not found")
+}
+
+// Finds the buck2 executable
+func findBuck2Exec(context Context) (string, error) {
+	buck2 := resolveBuck2Exec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], buck2)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(buck2 + " not found")
+}
+
+// Resolves the buck2 executable (OS dependent)
+func resolveBuck2Exec(context Context) string {
+	if context.IsWindows() {
+		return "buck2.exe"
+	}
+	return "buck2"
+}
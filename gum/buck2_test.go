@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBuck2Cell(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "buck2", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "buck2", "monorepo"))
+	cell := filepath.Join(root, "third-party")
+
+	var checks = []struct {
+		title, pwd, rootdir, buildFile, executable string
+	}{
+		{"Root", filepath.Join(root, "app", "src"), root, filepath.Join(root, "app", "BUCK"), filepath.Join(bin, "buck2")},
+		{"Cell", filepath.Join(cell, "zlib"), cell, filepath.Join(cell, "zlib", "BUCK"), filepath.Join(bin, "buck2")},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: check.pwd,
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs([]string{"-gq", "build", "//..."})
+		cmd := FindBuck2(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+		if cmd.rootdir != check.rootdir {
+			t.Errorf("%s: rootdir got %s, want %s", check.title, cmd.rootdir, check.rootdir)
+		}
+		if cmd.projectRoot != root {
+			t.Errorf("%s: projectRoot got %s, want %s", check.title, cmd.projectRoot, root)
+		}
+		if cmd.buildFile != check.buildFile {
+			t.Errorf("%s: buildFile got %s, want %s", check.title, cmd.buildFile, check.buildFile)
+		}
+		if cmd.executable != check.executable {
+			t.Errorf("%s: executable got %s, want %s", check.title, cmd.executable, check.executable)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Build metadata, injected at build time with
+// -ldflags "-X 'github.com/kordamp/gm/gum.BuildVersion=...'"
+var (
+	BuildVersion   string
+	BuildCommit    string
+	BuildTimestamp string
+)
+
+// Version returns the version of this build of gum
+func Version() string {
+	return orUndefined(BuildVersion)
+}
+
+// VersionInfo returns the lines describing this build of gum: version, commit, build date, and Go version
+func VersionInfo() []string {
+	return []string{
+		"------------------------------------------------------------",
+		"gm " + Version(),
+		"------------------------------------------------------------",
+		"Build time: " + orUndefined(BuildTimestamp),
+		"Revision:   " + orUndefined(BuildCommit),
+		"Go:         " + runtime.Version() + " " + runtime.GOOS + "/" + runtime.GOARCH,
+		"------------------------------------------------------------"}
+}
+
+// Handles "gm gum version", printing the build metadata
+func runVersionCommand(context Context, args *ParsedArgs, params []string) int {
+	for _, line := range VersionInfo() {
+		fmt.Println(line)
+	}
+	return 0
+}
+
+func orUndefined(s string) string {
+	if len(s) > 0 {
+		return s
+	}
+	return "undefined"
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestVersionInfo(t *testing.T) {
+	// given:
+	defer func(version string, commit string) {
+		BuildVersion = version
+		BuildCommit = commit
+	}(BuildVersion, BuildCommit)
+
+	var checks = []struct {
+		version  string
+		commit   string
+		expected string
+		revision string
+	}{
+		{"", "", "undefined", "Revision:   undefined"},
+		{"1.2.3", "abc123", "1.2.3", "Revision:   abc123"},
+	}
+
+	for _, check := range checks {
+		// when:
+		BuildVersion = check.version
+		BuildCommit = check.commit
+		lines := VersionInfo()
+
+		// then:
+		if Version() != check.expected {
+			t.Errorf("Version: got %s, want %s", Version(), check.expected)
+		}
+		if lines[1] != "gm "+check.expected {
+			t.Errorf("Header: got %s, want gm %s", lines[1], check.expected)
+		}
+		if lines[4] != check.revision {
+			t.Errorf("Revision: got %s, want %s", lines[4], check.revision)
+		}
+		if lines[5] != "Go:         "+runtime.Version()+" "+runtime.GOOS+"/"+runtime.GOARCH {
+			t.Errorf("Go: got %s", lines[5])
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ClojureCommand defines an executable Clojure CLI command
+type ClojureCommand struct {
+	context       Context
+	config        *Config
+	executable    string
+	args          *ParsedArgs
+	rootBuildFile string
+	buildFile     string
+}
+
+// Execute executes the given command
+func (c ClojureCommand) Execute() int {
+	c.doConfigureClojure()
+	return ExitCode(c.doExecuteClojure())
+}
+
+func (c *ClojureCommand) doConfigureClojure() {
+	args := make([]string, 0)
+
+	buildFile := c.resolveBuildFile()
+	banner := make([]string, 0)
+	banner = append(banner, "Using Clojure CLI at '"+c.executable+"'")
+	banner = append(banner, "to run buildFile '"+buildFile+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "clojure"))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugClojure(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *ClojureCommand) doExecuteClojure() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the invocation of the configured command.
+// Resolves the config of the command
+func (c *ClojureCommand) configuration() *Config {
+	return c.config
+}
+
+// The Clojure CLI reads deps.edn from the current directory thus it's launched from there
+func (c *ClojureCommand) invocation() invocation {
+	buildFile := c.resolveBuildFile()
+	return invocation{
+		tool:       "clojure",
+		executable: c.executable,
+		rootDir:    filepath.Dir(buildFile),
+		buildFile:  buildFile,
+		workDir:    filepath.Dir(buildFile),
+		args:       c.args}
+}
+
+// Resolves the build file used by the invocation
+func (c *ClojureCommand) resolveBuildFile() string {
+	if c.args.HasGumFlag("gn") && len(c.buildFile) > 0 {
+		return c.buildFile
+	}
+	return c.rootBuildFile
+}
+
+func (c *ClojureCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *ClojureCommand) debugClojure(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "nearest            = ", c.args.HasGumFlag("gn"))
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "rootBuildFile      = ", c.rootBuildFile)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindClojure finds and executes the Clojure CLI
+func FindClojure(context Context, args *ParsedArgs) *ClojureCommand {
+	pwd := context.GetWorkingDir()
+
+	clojure, noClojure := findClojureExec(context)
+	rootBuildFile, noRootBuildFile := findClojureRootFile(context, pwd)
+	buildFile, noBuildFile := findClojureBuildFile(context, pwd)
+
+	if noBuildFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No deps.edn project found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	if noRootBuildFile != nil {
+		rootBuildFile = buildFile
+	}
+
+	config := ReadConfig(context, filepath.Dir(rootBuildFile))
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	if noClojure != nil {
+		warnNoClojure(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &ClojureCommand{
+		context:       context,
+		config:        config,
+		executable:    clojure,
+		args:          args,
+		rootBuildFile: rootBuildFile,
+		buildFile:     buildFile}
+}
+
+func warnNoClojure(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s nor %s found in path. Please install the Clojure CLI.", resolveClojureExec(context), resolveCljExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://clojure.org/guides/install_clojure)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the clojure executable, falling back to clj
+func findClojureExec(context Context) (string, error) {
+	paths := context.GetPaths()
+
+	for _, clojure := range []string{resolveClojureExec(context), resolveCljExec(context)} {
+		for i := range paths {
+			name := filepath.Join(paths[i], clojure)
+			if context.FileExists(name) {
+				return filepath.Abs(name)
+			}
+		}
+	}
+
+	return "", errors.New(resolveClojureExec(context) + " not found")
+}
+
+// Finds the nearest deps.edn
+func findClojureBuildFile(context Context, dir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	if parentdir == dir {
+		return "", errors.New("Did not find deps.edn")
+	}
+
+	path := filepath.Join(dir, "deps.edn")
+	if context.FileExists(path) {
+		return filepath.Abs(path)
+	}
+
+	if isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find deps.edn")
+	}
+
+	return findClojureBuildFile(context, parentdir)
+}
+
+// Finds the topmost deps.edn, where aliases shared by all projects are defined
+func findClojureRootFile(context Context, dir string) (string, error) {
+	root, err := findClojureBuildFile(context, dir)
+	if err != nil {
+		return "", errors.New("Did not find root deps.edn")
+	}
+
+	for {
+		parent, err := findClojureBuildFile(context, filepath.Join(filepath.Dir(root), ".."))
+		if err != nil {
+			return root, nil
+		}
+		root = parent
+	}
+}
+
+// Resolves the clojure executable (OS dependent)
+func resolveClojureExec(context Context) string {
+	if context.IsWindows() {
+		return "clojure.exe"
+	}
+	return "clojure"
+}
+
+// Resolves the clj executable (OS dependent)
+func resolveCljExec(context Context) string {
+	if context.IsWindows() {
+		return "clj.exe"
+	}
+	return "clj"
+}
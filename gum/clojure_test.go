@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestClojureFromSubdirectory(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "mono"))
+	module := filepath.Join(root, "modules", "api")
+	pwd := filepath.Join(module, "src")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "-M:test"})
+	cmd := FindClojure(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureClojure()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "clojure")},
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(root, "deps.edn")},
+		{"BuildFile", cmd.buildFile, filepath.Join(module, "deps.edn")},
+		{"WorkDir", cmd.invocation().workDir, root},
+		{"Args", cmd.args.Args[0], "-M:test"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestClojureNearestWithClj(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "clj"))
+	module, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "mono", "modules", "api"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: module,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "-gn"})
+	cmd := FindClojure(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "clj")},
+		{"WorkDir", cmd.invocation().workDir, module},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CMakeCommand defines an executable CMake command
+type CMakeCommand struct {
+	context    Context
+	config     *Config
+	rootdir    string
+	executable string
+	args       *ParsedArgs
+	buildFile  string
+}
+
+// Execute executes the given command
+func (c CMakeCommand) Execute() int {
+	c.doConfigureCMake()
+	return ExitCode(c.doExecuteCMake())
+}
+
+func (c *CMakeCommand) doConfigureCMake() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using CMake at '"+c.executable+"'")
+	banner = append(banner, "to run buildFile '"+c.buildFile+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "cmake"))
+	args = appendSafe(args, c.args.Tool)
+	if len(args) == 0 && len(oargs) == 0 {
+		// configure the build dir first, then build it
+		if c.context.FileExists(filepath.Join(c.rootdir, "build", "CMakeCache.txt")) {
+			args = append(args, "--build", "build")
+		} else {
+			args = append(args, "-S", ".", "-B", "build")
+		}
+	}
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugCMake(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *CMakeCommand) doExecuteCMake() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *CMakeCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *CMakeCommand) invocation() invocation {
+	return invocation{
+		tool:       "cmake",
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		buildFile:  c.buildFile,
+		workDir:    c.rootdir,
+		args:       c.args}
+}
+
+func (c *CMakeCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *CMakeCommand) debugCMake(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindCMake finds and executes CMake
+func FindCMake(context Context, args *ParsedArgs) *CMakeCommand {
+	pwd := context.GetWorkingDir()
+
+	buildFile, noBuildFile := findCMakeRootFile(context, pwd, resolveSearchBoundary(context, pwd))
+	if noBuildFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No CMake project found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	rootdir := filepath.Dir(buildFile)
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	executable, noCMake := findCMakeExec(context)
+	if noCMake != nil {
+		warnNoCMake(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &CMakeCommand{
+		context:    context,
+		config:     config,
+		rootdir:    rootdir,
+		executable: executable,
+		args:       args,
+		buildFile:  buildFile}
+}
+
+func warnNoCMake(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s found in path. Please install CMake.", resolveCMakeExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://cmake.org/download/)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the topmost CMakeLists.txt, as subdirectories are added by their parent,
+// stopping at the given boundary dir
+func findCMakeRootFile(context Context, dir string, boundary string) (string, error) {
+	root := ""
+
+	for {
+		path := filepath.Join(dir, "CMakeLists.txt")
+		if context.FileExists(path) {
+			root = path
+		}
+
+		parentdir := filepath.Join(dir, "..")
+		abs, _ := filepath.Abs(dir)
+		if abs == boundary || parentdir == dir || isSearchBoundary(context, dir) {
+			break
+		}
+		dir = parentdir
+	}
+
+	if len(root) == 0 {
+		return "", errors.New("Did not find CMakeLists.txt")
+	}
+	return filepath.Abs(root)
+}
+
+// Finds the cmake executable
+func findCMakeExec(context Context) (string, error) {
+	cmake := resolveCMakeExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], cmake)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(cmake + " not found")
+}
+
+// Resolves the cmake executable (OS dependent)
+func resolveCMakeExec(context Context) string {
+	if context.IsWindows() {
+		return "cmake.exe"
+	}
+	return "cmake"
+}
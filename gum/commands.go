@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// gumCommand defines one of Gum's own commands, invoked as "gm gum <command>"
+type gumCommand func(context Context, args *ParsedArgs, params []string) int
+
+var gumCommands = map[string]gumCommand{
+	"alias":         runAliasCommand,
+	"cache":         runCacheCommand,
+	"config":        runConfigCommand,
+	"daemon":        runDaemonCommand,
+	"discover":      runDiscoverCommand,
+	"doctor":        runDoctorCommand,
+	"export-script": runExportScriptCommand,
+	"init":          runInitCommand,
+	"last":          runLastCommand,
+	"lock":          runLockCommand,
+	"run":           runRunCommand,
+	"prompt":        runPromptCommand,
+	"release":       runReleaseCommand,
+	"stats":         runStatsCommand,
+	"version":       runVersionCommand,
+}
+
+// IsGumCommand checks if the given args invoke one of Gum's own commands
+func IsGumCommand(args *ParsedArgs) bool {
+	return len(args.Args) > 0 && args.Args[0] == "gum"
+}
+
+// ExecuteGumCommand executes one of Gum's own commands and returns its exit code
+func ExecuteGumCommand(context Context, args *ParsedArgs) int {
+	if len(args.Args) < 2 {
+		fmt.Fprintln(gumOutput, "Usage: gm gum <command> [args]")
+		fmt.Fprintln(gumOutput, "Available commands: ", gumCommandNames())
+		return -1
+	}
+
+	name := args.Args[1]
+	cmd, ok := gumCommands[name]
+	if !ok {
+		fmt.Fprintln(gumOutput, "Unsupported command: "+name)
+		fmt.Fprintln(gumOutput, "Available commands: ", gumCommandNames())
+		return -1
+	}
+
+	return cmd(context, args, args.Args[2:])
+}
+
+func gumCommandNames() []string {
+	names := make([]string, 0)
+	for name := range gumCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolves the root directory of the project found at the working dir.
+// Returns the working dir if no project is found
+func resolveProjectRootDir(context Context) string {
+	args := ParseArgs([]string{})
+
+	if gradle := FindGradle(context, &args); gradle != nil {
+		return gradle.rootDir
+	}
+	if maven := FindMaven(context, &args); maven != nil {
+		return resolveMavenRootDir(context, maven.explicitBuildFile, maven.buildFile, maven.rootBuildFile)
+	}
+	if ant := FindAnt(context, &args); ant != nil {
+		return ant.rootdir
+	}
+	if bach := FindBach(context, &args); bach != nil {
+		return bach.rootdir
+	}
+
+	dir, _ := filepath.Abs(context.GetWorkingDir())
+	return dir
+}
+
+// Finds a boolean option such as --project and removes it from params
+func hasOption(option string, params []string) (bool, []string) {
+	for i, p := range params {
+		if p == option {
+			return true, shrinkSlice(params, i, 1)
+		}
+	}
+	return false, params
+}
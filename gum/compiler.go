@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gookit/color"
+)
+
+// Maximum number of messages per severity displayed by the summary
+const compilerSummaryLimit = 20
+
+// Patterns matching compiler messages. Each captures severity, file, line, and message
+var compilerPatterns = []*regexp.Regexp{
+	// javac: /src/Foo.java:12: error: cannot find symbol
+	regexp.MustCompile(`^(\S+\.java):(\d+): (error|warning): (.+)$`),
+	// Maven: [ERROR] /src/Foo.java:[12,5] cannot find symbol
+	regexp.MustCompile(`^\[(ERROR|WARNING)\] (\S+\.(?:java|kt|groovy|scala)):\[(\d+)(?:,\d+)?\] (.+)$`),
+	// kotlinc: e: file:///src/Foo.kt:12:5 Unresolved reference: foo
+	regexp.MustCompile(`^([ew]): (?:file://)?(\S+\.kts?):(\d+):\d+ (.+)$`),
+	// kotlinc (before 1.9): e: /src/Foo.kt: (12, 5): Unresolved reference: foo
+	regexp.MustCompile(`^([ew]): (\S+\.kts?): \((\d+), \d+\): (.+)$`),
+}
+
+// Matches ANSI escape sequences, such as colors emitted by a build running on a terminal
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// compilerMessage is an error or warning reported by a compiler
+type compilerMessage struct {
+	severity string
+	file     string
+	line     int
+	message  string
+}
+
+// Parses a single line of build output into a compiler message
+func parseCompilerMessage(line string) (compilerMessage, bool) {
+	line = strings.TrimRight(ansiEscape.ReplaceAllString(line, ""), "\r")
+	for i, pattern := range compilerPatterns {
+		m := pattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		severity, file, number, message := m[3], m[1], m[2], m[4]
+		if i > 0 {
+			severity, file, number, message = m[1], m[2], m[3], m[4]
+		}
+		n, _ := strconv.Atoi(number)
+		return compilerMessage{
+			severity: normalizeSeverity(severity),
+			file:     file,
+			line:     n,
+			message:  strings.TrimSpace(message)}, true
+	}
+	return compilerMessage{}, false
+}
+
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "error", "e":
+		return "error"
+	}
+	return "warning"
+}
+
+// compilerWatcher collects compiler errors and warnings from the output of a build
+type compilerWatcher struct {
+	mutex    sync.Mutex
+	messages []compilerMessage
+	seen     map[compilerMessage]bool
+}
+
+func newCompilerWatcher() *compilerWatcher {
+	return &compilerWatcher{seen: make(map[compilerMessage]bool)}
+}
+
+// Returns a writer for a single output stream of the build, such as stdout or stderr
+func (w *compilerWatcher) stream() io.Writer {
+	return &compilerStream{watcher: w}
+}
+
+// Records a message unless already seen, as Maven repeats errors once the build fails
+func (w *compilerWatcher) add(message compilerMessage) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.seen[message] {
+		w.seen[message] = true
+		w.messages = append(w.messages, message)
+	}
+}
+
+type compilerStream struct {
+	watcher *compilerWatcher
+	line    string
+}
+
+func (s *compilerStream) Write(p []byte) (int, error) {
+	lines := strings.Split(s.line+string(p), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		if message, ok := parseCompilerMessage(line); ok {
+			s.watcher.add(message)
+		}
+	}
+	s.line = lines[len(lines)-1]
+	return len(p), nil
+}
+
+// Prints the collected errors followed by warnings. Files are shown relative to rootDir
+func printCompilerSummary(config *Config, w *compilerWatcher, rootDir string) {
+	if w == nil || len(w.messages) == 0 {
+		return
+	}
+
+	colored := isInstanceOf(config.theme.t, (*ColoredTheme)(nil))
+	for _, severity := range []string{"error", "warning"} {
+		lines := make([]string, 0)
+		for _, m := range w.messages {
+			if m.severity == severity {
+				lines = append(lines, relativeTo(rootDir, m.file)+":"+strconv.Itoa(m.line)+": "+m.message)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		title := "Compiler " + severity + "s (" + strconv.Itoa(len(lines)) + "):"
+		if colored && severity == "error" {
+			title = color.Red.Sprint(title)
+		} else if colored {
+			title = color.Yellow.Sprint(title)
+		}
+		fmt.Fprintln(gumOutput, title)
+		for i, line := range lines {
+			if i == compilerSummaryLimit {
+				fmt.Fprintln(gumOutput, "  ... and "+strconv.Itoa(len(lines)-i)+" more")
+				break
+			}
+			fmt.Fprintln(gumOutput, "  "+line)
+		}
+	}
+}
+
+// Resolves file relative to dir, if it's inside dir
+func relativeTo(dir string, file string) string {
+	if len(dir) == 0 || !filepath.IsAbs(file) {
+		return file
+	}
+	if rel, err := filepath.Rel(dir, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return file
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"testing"
+)
+
+func TestParseCompilerMessage(t *testing.T) {
+	var checks = []struct {
+		title, line string
+		expected    compilerMessage
+		found       bool
+	}{
+		{"Javac", "/p/src/Foo.java:12: error: cannot find symbol",
+			compilerMessage{"error", "/p/src/Foo.java", 12, "cannot find symbol"}, true},
+		{"JavacWarning", "/p/src/Foo.java:3: warning: [deprecation] bar() has been deprecated",
+			compilerMessage{"warning", "/p/src/Foo.java", 3, "[deprecation] bar() has been deprecated"}, true},
+		{"Maven", "[ERROR] /p/src/Foo.java:[12,5] cannot find symbol",
+			compilerMessage{"error", "/p/src/Foo.java", 12, "cannot find symbol"}, true},
+		{"MavenKotlin", "[WARNING] /p/src/Foo.kt:[7,1] Variable 'x' is never used",
+			compilerMessage{"warning", "/p/src/Foo.kt", 7, "Variable 'x' is never used"}, true},
+		{"Kotlin", "e: file:///p/src/Foo.kt:12:5 Unresolved reference: foo",
+			compilerMessage{"error", "/p/src/Foo.kt", 12, "Unresolved reference: foo"}, true},
+		{"KotlinLegacy", "w: /p/src/Foo.kt: (4, 9): Parameter 'y' is never used",
+			compilerMessage{"warning", "/p/src/Foo.kt", 4, "Parameter 'y' is never used"}, true},
+		{"Colored", "\x1b[31m/p/src/Foo.java:12: error: cannot find symbol\x1b[0m\r",
+			compilerMessage{"error", "/p/src/Foo.java", 12, "cannot find symbol"}, true},
+		{"Other", "[ERROR] Failed to execute goal org.apache.maven.plugins:maven-compiler-plugin",
+			compilerMessage{}, false},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual, found := parseCompilerMessage(check.line)
+
+		// then:
+		if found != check.found || actual != check.expected {
+			t.Errorf("%s: got %v (%t), want %v (%t)", check.title, actual, found, check.expected, check.found)
+		}
+	}
+}
+
+func TestCompilerWatcher(t *testing.T) {
+	// given:
+	watcher := newCompilerWatcher()
+	stdout := watcher.stream()
+	stderr := watcher.stream()
+
+	// when:
+	stdout.Write([]byte("[INFO] Compiling 2 source files\n[ERROR] /p/src/Foo.java:[12,5] cannot "))
+	stderr.Write([]byte("e: file:///p/src/Bar.kt:3:1 Unresolved reference: bar\n"))
+	stdout.Write([]byte("find symbol\n[ERROR] /p/src/Foo.java:[12,5] cannot find symbol\n"))
+
+	// then:
+	if len(watcher.messages) != 2 {
+		t.Fatalf("got %d messages, want 2: %v", len(watcher.messages), watcher.messages)
+	}
+	if watcher.messages[0].file != "/p/src/Bar.kt" || watcher.messages[1].file != "/p/src/Foo.java" {
+		t.Errorf("unexpected messages %v", watcher.messages)
+	}
+}
+
+func TestRelativeTo(t *testing.T) {
+	var checks = []struct {
+		dir, file, expected string
+	}{
+		{"/p", "/p/src/Foo.java", "src/Foo.java"},
+		{"/p", "/q/src/Foo.java", "/q/src/Foo.java"},
+		{"", "/p/src/Foo.java", "/p/src/Foo.java"},
+		{"/p", "src/Foo.java", "src/Foo.java"},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := relativeTo(check.dir, check.file)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s in %s: got %s, want %s", check.file, check.dir, actual, check.expected)
+		}
+	}
+}
@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/gookit/color"
 	"github.com/grignaak/tribool"
@@ -29,12 +31,20 @@ import (
 
 // Config defines configuration settings for Gum
 type Config struct {
-	theme   theme
-	general general
-	gradle  gradle
-	maven   maven
-	jbang   jbang
-	bach    bach
+	theme     theme
+	general   general
+	gradle    gradle
+	maven     maven
+	jbang     jbang
+	bach      bach
+	notify    notify
+	hooks     hooks
+	discovery discovery
+	policy    policy
+	aliases   map[string]string
+	branches  []branchDefaults
+	tools     map[string]*customTool
+	profiles  map[string]*profile
 }
 
 type theme struct {
@@ -49,30 +59,58 @@ type theme struct {
 }
 
 type general struct {
-	quiet     bool
-	debug     bool
-	discovery []string
+	quiet           bool
+	debug           bool
+	discovery       []string
+	confirm         []string
+	timeout         string
+	interactive     bool
+	retries         int
+	retryDelay      string
+	summarizeErrors bool
+	annotations     bool
+	notify          string
+	output          string
+	console         string
+	remoteIncludes  bool
+	telemetry       bool
 
 	q tribool.Tribool
 	d tribool.Tribool
+	i tribool.Tribool
+	e tribool.Tribool
+	a tribool.Tribool
 }
 
 type gradle struct {
-	replace  bool
-	defaults bool
-	mappings map[string]string
+	replace      bool
+	defaults     bool
+	mappings     map[string]string
+	minVersion   string
+	projectPaths bool
+	validation   string
+	initScripts  []string
+	buildScan    string
 
 	r tribool.Tribool
 	d tribool.Tribool
+	p tribool.Tribool
 }
 
 type maven struct {
-	replace  bool
-	defaults bool
-	mappings map[string]string
+	replace     bool
+	defaults    bool
+	mappings    map[string]string
+	minVersion  string
+	daemon      bool
+	projectList bool
+	alsoMake    bool
 
-	r tribool.Tribool
-	d tribool.Tribool
+	r  tribool.Tribool
+	d  tribool.Tribool
+	dm tribool.Tribool
+	pl tribool.Tribool
+	am tribool.Tribool
 }
 
 type jbang struct {
@@ -83,6 +121,36 @@ type bach struct {
 	version string
 }
 
+type notify struct {
+	url       string
+	threshold string
+}
+
+type hooks struct {
+	before []string
+	after  []string
+}
+
+// discovery configures upward searches for build files and wrappers
+type discovery struct {
+	vcsBoundary     bool
+	boundaryMarkers []string
+	maxDepth        int
+	priority        []string
+	resolveSymlinks bool
+
+	v tribool.Tribool
+	s tribool.Tribool
+}
+
+// customTool defines a build tool declared in config, i.e, a company internal launcher
+type customTool struct {
+	markers    []string
+	executable string
+	wrapper    string
+	args       []string
+}
+
 func (c *Config) print() {
 	c.theme.t.PrintSection("theme")
 	c.theme.t.PrintKeyValueLiteral("name", c.theme.name)
@@ -97,9 +165,40 @@ func (c *Config) print() {
 	c.theme.t.PrintKeyValueBoolean("quiet", c.general.quiet)
 	c.theme.t.PrintKeyValueBoolean("debug", c.general.debug)
 	c.theme.t.PrintKeyValueArrayS("discovery", c.general.discovery)
+	c.theme.t.PrintKeyValueArrayS("confirm", c.general.confirm)
+	c.theme.t.PrintKeyValueBoolean("interactive", c.general.interactive)
+	c.theme.t.PrintKeyValueBoolean("summarizeErrors", c.general.summarizeErrors)
+	c.theme.t.PrintKeyValueBoolean("annotations", c.general.annotations)
+	if len(c.general.timeout) > 0 {
+		c.theme.t.PrintKeyValueLiteral("timeout", c.general.timeout)
+	}
+	if len(c.general.notify) > 0 {
+		c.theme.t.PrintKeyValueLiteral("notify", c.general.notify)
+	}
+	if len(c.general.output) > 0 {
+		c.theme.t.PrintKeyValueLiteral("output", c.general.output)
+	}
+	c.theme.t.PrintKeyValueLiteral("console", c.general.console)
+	if c.general.retries > 0 {
+		c.theme.t.PrintKeyValueInt("retries", c.general.retries)
+	}
+	if len(c.general.retryDelay) > 0 {
+		c.theme.t.PrintKeyValueLiteral("retryDelay", c.general.retryDelay)
+	}
 	c.theme.t.PrintSection("gradle")
 	c.theme.t.PrintKeyValueBoolean("replace", c.gradle.replace)
 	c.theme.t.PrintKeyValueBoolean("defaults", c.gradle.defaults)
+	c.theme.t.PrintKeyValueBoolean("projectPaths", c.gradle.projectPaths)
+	if len(c.gradle.minVersion) > 0 {
+		c.theme.t.PrintKeyValueLiteral("minVersion", c.gradle.minVersion)
+	}
+	c.theme.t.PrintKeyValueLiteral("wrapperValidation", c.gradle.validation)
+	if len(c.gradle.initScripts) > 0 {
+		c.theme.t.PrintKeyValueArrayS("initScripts", c.gradle.initScripts)
+	}
+	if len(c.gradle.buildScan) > 0 {
+		c.theme.t.PrintKeyValueLiteral("buildScan", c.gradle.buildScan)
+	}
 	if len(c.gradle.mappings) > 0 {
 		c.theme.t.PrintSection("gradle.mappings")
 		c.theme.t.PrintMap(c.gradle.mappings)
@@ -107,6 +206,12 @@ func (c *Config) print() {
 	c.theme.t.PrintSection("maven")
 	c.theme.t.PrintKeyValueBoolean("replace", c.maven.replace)
 	c.theme.t.PrintKeyValueBoolean("defaults", c.maven.defaults)
+	c.theme.t.PrintKeyValueBoolean("daemon", c.maven.daemon)
+	c.theme.t.PrintKeyValueBoolean("projectList", c.maven.projectList)
+	c.theme.t.PrintKeyValueBoolean("alsoMake", c.maven.alsoMake)
+	if len(c.maven.minVersion) > 0 {
+		c.theme.t.PrintKeyValueLiteral("minVersion", c.maven.minVersion)
+	}
 	if len(c.maven.mappings) > 0 {
 		c.theme.t.PrintSection("maven.mappings")
 		c.theme.t.PrintMap(c.maven.mappings)
@@ -115,6 +220,64 @@ func (c *Config) print() {
 	c.theme.t.PrintKeyValueArrayS("discovery", c.jbang.discovery)
 	c.theme.t.PrintSection("bach")
 	c.theme.t.PrintKeyValueLiteral("version", c.bach.version)
+	if len(c.notify.url) > 0 || len(c.notify.threshold) > 0 {
+		c.theme.t.PrintSection("notify")
+		if len(c.notify.url) > 0 {
+			c.theme.t.PrintKeyValueLiteral("url", c.notify.url)
+		}
+		if len(c.notify.threshold) > 0 {
+			c.theme.t.PrintKeyValueLiteral("threshold", c.notify.threshold)
+		}
+	}
+	if len(c.hooks.before) > 0 || len(c.hooks.after) > 0 {
+		c.theme.t.PrintSection("hooks")
+		c.theme.t.PrintKeyValueArrayS("before", c.hooks.before)
+		c.theme.t.PrintKeyValueArrayS("after", c.hooks.after)
+	}
+	c.theme.t.PrintSection("discovery")
+	c.theme.t.PrintKeyValueBoolean("vcsBoundary", c.discovery.vcsBoundary)
+	if len(c.discovery.boundaryMarkers) > 0 {
+		c.theme.t.PrintKeyValueArrayS("boundaryMarkers", c.discovery.boundaryMarkers)
+	}
+	if c.discovery.maxDepth > 0 {
+		c.theme.t.PrintKeyValueInt("maxDepth", c.discovery.maxDepth)
+	}
+	if len(c.discovery.priority) > 0 {
+		c.theme.t.PrintKeyValueArrayS("priority", c.discovery.priority)
+	}
+	c.theme.t.PrintKeyValueBoolean("resolveSymlinks", c.discovery.resolveSymlinks)
+	if len(c.aliases) > 0 {
+		c.theme.t.PrintSection("aliases")
+		c.theme.t.PrintMap(c.aliases)
+	}
+	for _, b := range c.branches {
+		for _, tool := range sortedKeys(b.args) {
+			c.theme.t.PrintSection("branch.\"" + b.pattern + "\"." + tool)
+			c.theme.t.PrintKeyValueArrayS("defaults", b.args[tool])
+		}
+	}
+	for _, name := range sortedProfiles(c.profiles) {
+		p := c.profiles[name]
+		for _, tool := range sortedKeys(p.args) {
+			c.theme.t.PrintSection("profiles." + name + "." + tool)
+			c.theme.t.PrintKeyValueArrayS("args", p.args[tool])
+		}
+		if len(p.env) > 0 {
+			c.theme.t.PrintSection("profiles." + name + ".env")
+			c.theme.t.PrintMap(p.env)
+		}
+	}
+	for _, name := range sortedCustomTools(c.tools) {
+		t := c.tools[name]
+		c.theme.t.PrintSection("tools." + name)
+		c.theme.t.PrintKeyValueArrayS("markers", t.markers)
+		c.theme.t.PrintKeyValueLiteral("executable", t.executable)
+		if len(t.wrapper) > 0 {
+			c.theme.t.PrintKeyValueLiteral("wrapper", t.wrapper)
+		}
+		c.theme.t.PrintKeyValueArrayS("args", t.args)
+	}
+	c.policy.print(c.theme.t)
 }
 
 func newConfig() *Config {
@@ -130,19 +293,36 @@ func newConfig() *Config {
 		general: general{
 			q:         tribool.Maybe,
 			d:         tribool.Maybe,
-			discovery: make([]string, 0)},
+			i:         tribool.Maybe,
+			e:         tribool.Maybe,
+			a:         tribool.Maybe,
+			discovery: make([]string, 0),
+			confirm:   make([]string, 0)},
 		gradle: gradle{
 			r:        tribool.Maybe,
 			d:        tribool.Maybe,
+			p:        tribool.Maybe,
 			mappings: make(map[string]string)},
 		maven: maven{
 			r:        tribool.Maybe,
 			d:        tribool.Maybe,
+			dm:       tribool.Maybe,
+			pl:       tribool.Maybe,
+			am:       tribool.Maybe,
 			mappings: make(map[string]string)},
 		jbang: jbang{
 			discovery: make([]string, 0)},
 		bach: bach{
-			version: ""}}
+			version: ""},
+		discovery: discovery{
+			v:               tribool.Maybe,
+			s:               tribool.Maybe,
+			boundaryMarkers: make([]string, 0),
+			priority:        make([]string, 0)},
+		aliases:  make(map[string]string),
+		branches: make([]branchDefaults, 0),
+		tools:    make(map[string]*customTool),
+		profiles: make(map[string]*profile)}
 }
 
 func (c *Config) setQuiet(b bool) {
@@ -168,12 +348,37 @@ func (c *Config) merge(other *Config) {
 		c.maven.merge(nil)
 		c.jbang.merge(nil)
 		c.bach.merge(nil)
+		c.notify.merge(nil)
+		c.discovery.merge(nil)
 	} else {
 		c.general.merge(&other.general)
 		c.gradle.merge(&other.gradle)
 		c.maven.merge(&other.maven)
 		c.jbang.merge(&other.jbang)
 		c.bach.merge(&other.bach)
+		c.notify.merge(&other.notify)
+		c.hooks.merge(&other.hooks)
+		c.discovery.merge(&other.discovery)
+
+		for k, v := range other.aliases {
+			if _, ok := c.aliases[k]; !ok {
+				c.aliases[k] = v
+			}
+		}
+
+		c.branches = append(other.branches, c.branches...)
+
+		for k, v := range other.tools {
+			if _, ok := c.tools[k]; !ok {
+				c.tools[k] = v
+			}
+		}
+
+		for k, v := range other.profiles {
+			if _, ok := c.profiles[k]; !ok {
+				c.profiles[k] = v
+			}
+		}
 	}
 }
 
@@ -190,9 +395,58 @@ func (g *general) merge(other *general) {
 		g.debug = other.d.WithMaybeAsFalse()
 	}
 
-	if len(g.discovery) != 5 && other != nil {
+	if g.i != tribool.Maybe || other == nil {
+		g.interactive = g.i.WithMaybeAsFalse()
+	} else {
+		g.interactive = other.i.WithMaybeAsFalse()
+	}
+
+	if g.e != tribool.Maybe || other == nil {
+		g.summarizeErrors = g.e.WithMaybeAsFalse()
+	} else {
+		g.summarizeErrors = other.e.WithMaybeAsFalse()
+	}
+
+	if g.a != tribool.Maybe || other == nil {
+		g.annotations = g.a.WithMaybeAsFalse()
+	} else {
+		g.annotations = other.a.WithMaybeAsFalse()
+	}
+
+	if len(g.discovery) == 0 && other != nil {
 		g.discovery = other.discovery
 	}
+
+	if len(g.confirm) == 0 && other != nil {
+		g.confirm = other.confirm
+	}
+
+	if len(g.timeout) == 0 && other != nil {
+		g.timeout = other.timeout
+	}
+
+	if g.retries == 0 && other != nil {
+		g.retries = other.retries
+	}
+
+	if len(g.retryDelay) == 0 && other != nil {
+		g.retryDelay = other.retryDelay
+	}
+
+	if len(g.notify) == 0 && other != nil {
+		g.notify = other.notify
+	}
+
+	if len(g.output) == 0 && other != nil {
+		g.output = other.output
+	}
+
+	if len(g.console) == 0 && other != nil {
+		g.console = other.console
+	}
+	if len(g.console) == 0 {
+		g.console = "off"
+	}
 }
 
 func (g *gradle) merge(other *gradle) {
@@ -227,6 +481,32 @@ func (g *gradle) merge(other *gradle) {
 		mp[k] = v
 	}
 	g.mappings = mp
+
+	if len(g.minVersion) == 0 && other != nil {
+		g.minVersion = other.minVersion
+	}
+
+	if g.p != tribool.Maybe || other == nil {
+		g.projectPaths = g.p.WithMaybeAsTrue()
+	} else {
+		g.projectPaths = other.p.WithMaybeAsTrue()
+	}
+
+	if len(g.validation) == 0 && other != nil {
+		g.validation = other.validation
+	}
+	if len(g.validation) == 0 {
+		g.validation = "off"
+	}
+
+	if len(g.buildScan) == 0 && other != nil {
+		g.buildScan = other.buildScan
+	}
+
+	// init scripts of the user config run before those of the project
+	if other != nil {
+		g.initScripts = append(append(make([]string, 0), other.initScripts...), g.initScripts...)
+	}
 }
 
 func (m *maven) merge(other *maven) {
@@ -264,6 +544,28 @@ func (m *maven) merge(other *maven) {
 		mp[k] = v
 	}
 	m.mappings = mp
+
+	if m.dm != tribool.Maybe || other == nil {
+		m.daemon = m.dm.WithMaybeAsFalse()
+	} else {
+		m.daemon = other.dm.WithMaybeAsFalse()
+	}
+
+	if m.pl != tribool.Maybe || other == nil {
+		m.projectList = m.pl.WithMaybeAsTrue()
+	} else {
+		m.projectList = other.pl.WithMaybeAsTrue()
+	}
+
+	if m.am != tribool.Maybe || other == nil {
+		m.alsoMake = m.am.WithMaybeAsFalse()
+	} else {
+		m.alsoMake = other.am.WithMaybeAsFalse()
+	}
+
+	if len(m.minVersion) == 0 && other != nil {
+		m.minVersion = other.minVersion
+	}
 }
 
 func (j *jbang) merge(other *jbang) {
@@ -281,29 +583,227 @@ func (b *bach) merge(other *bach) {
 	}
 }
 
+func (n *notify) merge(other *notify) {
+	if other == nil {
+		return
+	}
+	if len(n.url) == 0 {
+		n.url = other.url
+	}
+	if len(n.threshold) == 0 {
+		n.threshold = other.threshold
+	}
+}
+
+func (h *hooks) merge(other *hooks) {
+	if len(h.before) == 0 {
+		h.before = other.before
+	}
+	if len(h.after) == 0 {
+		h.after = other.after
+	}
+}
+
+func (d *discovery) merge(other *discovery) {
+	if d.v != tribool.Maybe || other == nil {
+		d.vcsBoundary = d.v.WithMaybeAsTrue()
+	} else {
+		d.vcsBoundary = other.v.WithMaybeAsTrue()
+	}
+
+	if d.s != tribool.Maybe || other == nil {
+		d.resolveSymlinks = d.s.WithMaybeAsTrue()
+	} else {
+		d.resolveSymlinks = other.s.WithMaybeAsTrue()
+	}
+
+	if len(d.boundaryMarkers) == 0 && other != nil {
+		d.boundaryMarkers = other.boundaryMarkers
+	}
+
+	if d.maxDepth == 0 && other != nil {
+		d.maxDepth = other.maxDepth
+	}
+
+	if len(d.priority) == 0 && other != nil {
+		d.priority = other.priority
+	}
+}
+
+// Project config file names, in order of precedence
+var projectConfigFiles = []string{".gm.toml", ".gum.yml", ".gum.yaml", "gum.yml", "gum.yaml"}
+
+// User config file names, in order of precedence
+var userConfigFiles = []string{"gm.toml", "config.toml", "gm.yml", "gm.yaml", "config.yml", "config.yaml"}
+
 // ReadUserConfig reads user config
 func ReadUserConfig(context Context) *Config {
-	homedir := context.GetHomeDir()
-	tomlfile := filepath.Join(homedir, ".gm.toml")
-	if context.IsWindows() {
-		tomlfile = filepath.Join(homedir, "Gum", "gm.toml")
+	// the user config is trusted to fetch remote includes
+	return readConfigFile(context, findUserConfigFile(context), true, nil)
+}
+
+// Finds the user config file to read, which may be written in any supported format
+func findUserConfigFile(context Context) string {
+	path := resolveUserConfigFile(context)
+	if dir := resolveConfigDir(context); filepath.Dir(path) == dir {
+		path = resolveConfigFile(context, dir, userConfigFiles)
+	}
+	return path
+}
+
+// Resolves the user TOML config file (OS dependent). Falls back to the legacy
+// location at $HOME/.gm.toml if it exists and no file is found at the config directory
+func resolveUserConfigFile(context Context) string {
+	dir := resolveConfigDir(context)
+	found := false
+	for _, name := range userConfigFiles {
+		path := filepath.Join(dir, name)
+		if context.FileExists(path) {
+			if !isYamlFile(path) {
+				return path
+			}
+			found = true
+		}
 	}
 
-	return ReadConfigFile(context, tomlfile)
+	path := filepath.Join(dir, userConfigFiles[0])
+	if found || len(resolveGumHome(context)) > 0 || context.IsWindows() {
+		return path
+	}
+
+	legacy := filepath.Join(context.GetHomeDir(), ".gm.toml")
+	if context.FileExists(legacy) {
+		return legacy
+	}
+	return path
 }
 
 // ReadConfig reads and merges project & user config
 func ReadConfig(context Context, rootdir string) *Config {
-	uconfig := ReadUserConfig(context)
-	pconfig := ReadConfigFile(context, filepath.Join(rootdir, ".gm.toml"))
+	if c, ok := context.(configContext); ok {
+		return c.configs.read(c.Context, rootdir)
+	}
+
+	config := readConfig(context, ReadPolicy(context), ReadUserConfig(context), rootdir)
+	setGumOutput(config)
+	return config
+}
+
+func readConfig(context Context, p policy, uconfig *Config, rootdir string) *Config {
+	pconfig := readProjectConfigFile(context, p, resolveConfigFile(context, rootdir, projectConfigFiles), uconfig.general.remoteIncludes)
 
 	pconfig.merge(uconfig)
+	pconfig.general.remoteIncludes = uconfig.general.remoteIncludes
+	pconfig.general.telemetry = uconfig.general.telemetry
+	pconfig.policy = p
 
 	return pconfig
 }
 
-// ReadConfigFile reads the given TOML config file
+// configCache reads the config of each project at most once per invocation, as detectors
+// run concurrently and would otherwise read, validate, and warn about the same files
+type configCache struct {
+	mutex   sync.Mutex
+	policy  policy
+	user    *Config
+	configs map[string]*Config
+}
+
+func newConfigCache(context Context) *configCache {
+	return &configCache{
+		policy:  ReadPolicy(context),
+		user:    ReadUserConfig(context),
+		configs: make(map[string]*Config)}
+}
+
+// Reads the config of the project at rootdir, see ReadConfig. Each caller gets its own copy
+// as commands update their config with flags such as -gq
+func (c *configCache) read(context Context, rootdir string) *Config {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	dir, _ := filepath.Abs(rootdir)
+	config, ok := c.configs[dir]
+	if !ok {
+		config = readConfig(context, c.policy, c.user, dir)
+		c.configs[dir] = config
+	}
+
+	copied := *config
+	return &copied
+}
+
+// Returns a copy of the user config with defaults applied
+func (c *configCache) userConfig() *Config {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	copied := *c.user
+	copied.merge(nil)
+	return &copied
+}
+
+// configContext reads config through the given cache, see ReadConfig
+type configContext struct {
+	Context
+	configs *configCache
+}
+
+// Reads config through a new cache unless context already has one
+func withConfigCache(context Context) Context {
+	if _, ok := context.(configContext); ok {
+		return context
+	}
+	return configContext{context, newConfigCache(context)}
+}
+
+// Resolves the first config file found at dir following the order of names. Other
+// matching files are ignored with a warning. Returns the first name if none exists
+func resolveConfigFile(context Context, dir string, names []string) string {
+	path := ""
+	for _, name := range names {
+		candidate := filepath.Join(dir, name)
+		if !context.FileExists(candidate) {
+			continue
+		}
+		if len(path) == 0 {
+			path = candidate
+		} else {
+			fmt.Fprintln(gumOutput, "WARNING: ignoring "+candidate+" in favor of "+path)
+		}
+	}
+
+	if len(path) == 0 {
+		return filepath.Join(dir, names[0])
+	}
+	return path
+}
+
+// Reads the project config, verifying its signature if required by policy
+func readProjectConfigFile(context Context, p policy, path string, remoteIncludes bool) *Config {
+	if (p.configSignatures != "warn" && p.configSignatures != "strict") || !context.FileExists(path) {
+		return readConfigFile(context, path, remoteIncludes, nil)
+	}
+
+	if !verifyConfigSignature(path, p.configKeys) {
+		if p.configSignatures == "strict" {
+			context.Exit(-1)
+			return newConfig()
+		}
+		fmt.Fprintln(gumOutput, "WARNING: using unverified config "+path)
+	}
+
+	return readConfigFile(context, path, remoteIncludes, &p)
+}
+
+// ReadConfigFile reads the given config file. The format (TOML or YAML) is detected by extension
 func ReadConfigFile(context Context, path string) *Config {
+	return readConfigFile(context, path, false, nil)
+}
+
+// Reads the given config file along with its includes. Remote includes are fetched only if allowed.
+// Local includes are verified against the signature policy p, if not nil
+func readConfigFile(context Context, path string, remoteIncludes bool, p *policy) *Config {
 	config := newConfig()
 
 	if !context.FileExists(path) {
@@ -311,14 +811,22 @@ func ReadConfigFile(context Context, path string) *Config {
 	}
 
 	doc, err := ioutil.ReadFile(path)
-	if err == nil {
-		toml.Unmarshal(doc, &config)
-	} else {
-		fmt.Println(err)
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
 	}
 
-	t, err := toml.LoadBytes(doc)
+	if !isYamlFile(path) {
+		toml.Unmarshal(doc, &config)
+	}
+	t, err := parseConfigTree(path, doc)
 	if err != nil {
+		fmt.Fprintln(gumOutput, "ERROR: "+path+": "+err.Error())
+		context.Exit(-1)
+		return config
+	}
+	if !reportConfigProblems(validateConfigTree(path, t)) ||
+		!resolveIncludes(context, t, path, remoteIncludes, p, map[string]bool{path: true}) {
+		context.Exit(-1)
 		return config
 	}
 
@@ -328,10 +836,25 @@ func ReadConfigFile(context Context, path string) *Config {
 	resolveSectionMaven(t, config)
 	resolveSectionJbang(t, config)
 	resolveSectionBach(t, config)
+	resolveSectionNotify(t, config)
+	resolveSectionHooks(t, config)
+	resolveSectionDiscovery(t, config)
+	resolveSectionAliases(t, config)
+	resolveSectionBranch(t, config)
+	resolveSectionTools(t, config)
+	resolveSectionProfiles(t, config)
 
 	return config
 }
 
+// Parses a config document, its format (TOML or YAML) is detected by the extension of path
+func parseConfigTree(path string, doc []byte) (*toml.Tree, error) {
+	if isYamlFile(path) {
+		return loadYamlTree(doc)
+	}
+	return toml.LoadBytes(doc)
+}
+
 func resolveSectionTheme(t *toml.Tree, config *Config) {
 	tt := t.Get("theme")
 	if tt != nil {
@@ -422,6 +945,58 @@ func resolveSectionGeneral(t *toml.Tree, config *Config) {
 				config.general.discovery[i] = e.(string)
 			}
 		}
+		v = table.Get("confirm")
+		if v != nil {
+			data := v.([]interface{})
+			config.general.confirm = make([]string, len(data))
+			for i, e := range data {
+				config.general.confirm[i] = e.(string)
+			}
+		}
+		v = table.Get("interactive")
+		if v != nil {
+			config.general.i = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("summarizeErrors")
+		if v != nil {
+			config.general.e = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("annotations")
+		if v != nil {
+			config.general.a = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("timeout")
+		if v != nil {
+			config.general.timeout = v.(string)
+		}
+		v = table.Get("retries")
+		if v != nil {
+			config.general.retries = int(v.(int64))
+		}
+		v = table.Get("retryDelay")
+		if v != nil {
+			config.general.retryDelay = v.(string)
+		}
+		v = table.Get("notify")
+		if v != nil {
+			config.general.notify = v.(string)
+		}
+		v = table.Get("output")
+		if v != nil {
+			config.general.output = v.(string)
+		}
+		v = table.Get("console")
+		if v != nil {
+			config.general.console = strings.ToLower(v.(string))
+		}
+		v = table.Get("remoteIncludes")
+		if v != nil {
+			config.general.remoteIncludes = v.(bool)
+		}
+		v = table.Get("telemetry")
+		if v != nil {
+			config.general.telemetry = v.(bool)
+		}
 	}
 }
 
@@ -440,11 +1015,27 @@ func resolveSectionGradle(t *toml.Tree, config *Config) {
 		v = table.Get("mappings")
 		if v != nil {
 			m := v.(*toml.Tree)
-			for i := range m.Keys() {
-				key := m.Keys()[i]
-				config.gradle.mappings[key] = m.Get(key).(string)
+			for _, key := range m.Keys() {
+				config.gradle.mappings[key] = m.GetPath([]string{key}).(string)
 			}
 		}
+		v = table.Get("minVersion")
+		if v != nil {
+			config.gradle.minVersion = v.(string)
+		}
+		v = table.Get("projectPaths")
+		if v != nil {
+			config.gradle.p = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("wrapperValidation")
+		if v != nil {
+			config.gradle.validation = strings.ToLower(v.(string))
+		}
+		config.gradle.initScripts = toStringSlice(table.Get("initScripts"))
+		v = table.Get("buildScan")
+		if v != nil {
+			config.gradle.buildScan = strings.ToLower(v.(string))
+		}
 	}
 }
 func resolveSectionMaven(t *toml.Tree, config *Config) {
@@ -462,11 +1053,26 @@ func resolveSectionMaven(t *toml.Tree, config *Config) {
 		v = table.Get("mappings")
 		if v != nil {
 			m := v.(*toml.Tree)
-			for i := range m.Keys() {
-				key := m.Keys()[i]
-				config.maven.mappings[key] = m.Get(key).(string)
+			for _, key := range m.Keys() {
+				config.maven.mappings[key] = m.GetPath([]string{key}).(string)
 			}
 		}
+		v = table.Get("minVersion")
+		if v != nil {
+			config.maven.minVersion = v.(string)
+		}
+		v = table.Get("daemon")
+		if v != nil {
+			config.maven.dm = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("projectList")
+		if v != nil {
+			config.maven.pl = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("alsoMake")
+		if v != nil {
+			config.maven.am = tribool.FromBool(v.(bool))
+		}
 	}
 }
 
@@ -495,3 +1101,163 @@ func resolveSectionBach(t *toml.Tree, config *Config) {
 		}
 	}
 }
+
+func resolveSectionNotify(t *toml.Tree, config *Config) {
+	tt := t.Get("notify")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("url")
+		if v != nil {
+			config.notify.url = v.(string)
+		}
+		v = table.Get("threshold")
+		if v != nil {
+			config.notify.threshold = v.(string)
+		}
+	}
+}
+
+func resolveSectionHooks(t *toml.Tree, config *Config) {
+	tt := t.Get("hooks")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		config.hooks.before = toStringSlice(table.Get("before"))
+		config.hooks.after = toStringSlice(table.Get("after"))
+	}
+}
+
+func resolveSectionDiscovery(t *toml.Tree, config *Config) {
+	tt := t.Get("discovery")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("vcsBoundary")
+		if v != nil {
+			config.discovery.v = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("boundaryMarkers")
+		if v != nil {
+			config.discovery.boundaryMarkers = toStringSlice(v)
+		}
+		v = table.Get("maxDepth")
+		if v != nil {
+			config.discovery.maxDepth = int(v.(int64))
+		}
+		v = table.Get("priority")
+		if v != nil {
+			config.discovery.priority = toStringSlice(v)
+		}
+		v = table.Get("resolveSymlinks")
+		if v != nil {
+			config.discovery.s = tribool.FromBool(v.(bool))
+		}
+	}
+}
+
+func resolveSectionAliases(t *toml.Tree, config *Config) {
+	tt := t.Get("aliases")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		for _, key := range table.Keys() {
+			config.aliases[key] = table.GetPath([]string{key}).(string)
+		}
+	}
+}
+
+func resolveSectionBranch(t *toml.Tree, config *Config) {
+	tt := t.Get("branch")
+	if tt == nil {
+		return
+	}
+
+	table, ok := tt.(*toml.Tree)
+	if !ok {
+		fmt.Fprintln(gumOutput, "ERROR: branch must be a table")
+		return
+	}
+	patterns := table.Keys()
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		tools, ok := table.GetPath([]string{pattern}).(*toml.Tree)
+		if !ok {
+			fmt.Fprintln(gumOutput, "ERROR: "+formatConfigKey([]string{"branch", pattern})+" must be a table")
+			continue
+		}
+
+		b := branchDefaults{
+			pattern: pattern,
+			args:    make(map[string][]string)}
+		for _, tool := range tools.Keys() {
+			if v := tools.GetPath([]string{tool, "defaults"}); v != nil {
+				b.args[tool] = toStringSlice(v)
+			}
+		}
+		config.branches = append(config.branches, b)
+	}
+}
+
+func resolveSectionTools(t *toml.Tree, config *Config) {
+	tt := t.Get("tools")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		for _, name := range table.Keys() {
+			section, ok := table.Get(name).(*toml.Tree)
+			if !ok {
+				continue
+			}
+
+			tool := &customTool{
+				markers: toStringSlice(section.Get("markers")),
+				args:    toStringSlice(section.Get("args"))}
+			if v, ok := section.Get("executable").(string); ok {
+				tool.executable = v
+			}
+			if v, ok := section.Get("wrapper").(string); ok {
+				tool.wrapper = v
+			}
+			config.tools[strings.ToLower(name)] = tool
+		}
+	}
+}
+
+func resolveSectionProfiles(t *toml.Tree, config *Config) {
+	tt := t.Get("profiles")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		for _, name := range table.Keys() {
+			section, ok := table.Get(name).(*toml.Tree)
+			if !ok {
+				continue
+			}
+
+			p := &profile{
+				args: make(map[string][]string),
+				env:  make(map[string]string)}
+			for _, key := range section.Keys() {
+				entries, ok := section.Get(key).(*toml.Tree)
+				if !ok {
+					continue
+				}
+				if key == "env" {
+					for _, k := range entries.Keys() {
+						if v, ok := entries.Get(k).(string); ok {
+							p.env[k] = v
+						}
+					}
+				} else {
+					p.args[key] = toStringSlice(entries.Get("args"))
+				}
+			}
+			config.profiles[name] = p
+		}
+	}
+}
+
+func toStringSlice(v interface{}) []string {
+	result := make([]string, 0)
+	if data, ok := v.([]interface{}); ok {
+		for _, e := range data {
+			result = append(result, e.(string))
+		}
+	}
+	return result
+}
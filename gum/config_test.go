@@ -17,7 +17,12 @@
 package gum
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -71,3 +76,171 @@ func TestLoadConfig(t *testing.T) {
 		t.Errorf("maven.mappings.compile: got %s, want %s", config.gradle.mappings["compileJava"], "compile")
 	}
 }
+
+func TestLoadGeneralConfig(t *testing.T) {
+	var checks = []struct {
+		fixture, title, expected string
+		actual                   func(config *Config) string
+	}{
+		{"interactive", "general.interactive", "true", func(c *Config) string { return fmt.Sprint(c.general.i.WithMaybeAsFalse()) }},
+		{"interactive", "general.timeout", "30m", func(c *Config) string { return c.general.timeout }},
+		{"retries", "general.retries", "2", func(c *Config) string { return fmt.Sprint(c.general.retries) }},
+		{"retries", "general.retryDelay", "5s", func(c *Config) string { return c.general.retryDelay }},
+		{"summarize", "general.summarizeErrors", "true", func(c *Config) string { return fmt.Sprint(c.general.e.WithMaybeAsFalse()) }},
+	}
+
+	for _, check := range checks {
+		// given:
+		path, _ := filepath.Abs(filepath.Join("..", "tests", "general", check.fixture, ".gm.toml"))
+
+		// when:
+		config := ReadConfigFile(testContext{}, path)
+
+		// then:
+		if actual := check.actual(config); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestLoadRegexMappings(t *testing.T) {
+	// given:
+	path, _ := filepath.Abs(filepath.Join("..", "tests", "mappings", ".gm.toml"))
+
+	// when:
+	config := ReadConfigFile(testContext{}, path)
+
+	// then:
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"gradle.mappings", config.gradle.mappings["^it(.*)$"], "integrationTest$1"},
+		{"maven.mappings", config.maven.mappings[`^run\.(.*)$`], "exec:$1"},
+		{"aliases", config.aliases["ci.full"], "clean build"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestLoadYamlConfig(t *testing.T) {
+	// given:
+	home, _ := filepath.Abs(filepath.Join("..", "tests", "home"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "yaml", "project"))
+
+	context := testContext{
+		explicit:   true,
+		windows:    false,
+		workingDir: root,
+		homeDir:    home,
+		paths:      []string{home, root}}
+
+	// when:
+	config := ReadConfig(context, root)
+
+	// then:
+	var checks = []struct {
+		title            string
+		actual, expected string
+	}{
+		{"general.debug", fmt.Sprint(config.general.debug), "true"},
+		{"general.discovery", strings.Join(config.general.discovery, ","), "maven,gradle"},
+		{"general.retries", fmt.Sprint(config.general.retries), "3"},
+		{"theme.name", config.theme.name, "custom"},
+		{"theme.literal", fmt.Sprint(config.theme.literal), "[23 0]"},
+		{"gradle.defaults", fmt.Sprint(config.gradle.defaults), "true"},
+		{"gradle.mappings.compile", config.gradle.mappings["compile"], "compileJava"},
+		{"maven.mappings.compileJava", config.maven.mappings["compileJava"], "compile"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestResolveConfigFilePrecedence(t *testing.T) {
+	// given:
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "yaml", "both"))
+	context := testContext{workingDir: root}
+
+	// when:
+	path := resolveConfigFile(context, root, projectConfigFiles)
+	config := ReadConfigFile(context, path)
+
+	// then:
+	if filepath.Base(path) != ".gm.toml" {
+		t.Errorf("path: got %s, want .gm.toml", filepath.Base(path))
+	}
+
+	if !config.general.d.WithMaybeAsFalse() {
+		t.Error("general.debug: got false, want true")
+	}
+}
+
+func TestConfigCache(t *testing.T) {
+	defer func() { gumOutput = os.Stderr }()
+
+	// given:
+	var output bytes.Buffer
+	gumOutput = &output
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "yaml", "both"))
+	relative := filepath.Join("..", "tests", "yaml", "both")
+	context := configContext{testContext{workingDir: root}, newConfigCache(testContext{workingDir: root})}
+
+	// when:
+	first := ReadConfig(context, root)
+	second := ReadConfig(context, relative)
+	second.setQuiet(true)
+
+	// then:
+	if !first.general.debug || !second.general.debug {
+		t.Error("general.debug: got false, want true")
+	}
+	if first.general.quiet {
+		t.Error("general.quiet: got true, want false as each caller gets its own copy")
+	}
+	if n := strings.Count(output.String(), "WARNING: ignoring"); n != 1 {
+		t.Errorf("warnings: got %d, want 1", n)
+	}
+}
+
+func TestMergeGlobalUserConfig(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+	os.MkdirAll(filepath.Join(home, ".config", "gum"), 0755)
+	ioutil.WriteFile(filepath.Join(home, ".config", "gum", "config.toml"),
+		[]byte("[general]\ntimeout = \"10m\"\ndebug = false\n\n[gradle.mappings]\nbuild = \"check\"\n"), 0644)
+	ioutil.WriteFile(filepath.Join(home, ".gm.toml"), []byte("[general]\ntimeout = \"1m\"\n"), 0644)
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "toml"))
+
+	context := testContext{
+		workingDir: root,
+		homeDir:    home}
+
+	// when:
+	config := ReadConfig(context, root)
+
+	// then:
+	var checks = []struct {
+		title            string
+		actual, expected string
+	}{
+		{"userConfigFile", resolveUserConfigFile(context), filepath.Join(home, ".config", "gum", "config.toml")},
+		{"general.timeout", config.general.timeout, "10m"},
+		{"general.debug", fmt.Sprint(config.general.debug), "true"},
+		{"gradle.mappings.build", config.gradle.mappings["build"], "check"},
+		{"gradle.mappings.compile", config.gradle.mappings["compile"], "compileJava"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
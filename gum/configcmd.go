@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// configEntry is a single setting of the effective configuration
+type configEntry struct {
+	path  []string
+	value string
+}
+
+// Handles "gm gum config [--validate]", printing the effective configuration along with the source
+// of each value, or validating the config files
+func runConfigCommand(context Context, args *ParsedArgs, params []string) int {
+	rootDir := resolveProjectRootDir(context)
+	if validate, _ := hasOption("--validate", params); validate {
+		userFile := findUserConfigFile(context)
+		remote := readConfigFile(context, userFile, true, nil).general.remoteIncludes
+		problems := validateConfigFile(context, userFile, true, map[string]bool{userFile: true})
+		projectFile := resolveConfigFile(context, rootDir, projectConfigFiles)
+		problems += validateConfigFile(context, projectFile, remote, map[string]bool{projectFile: true})
+		if problems > 0 {
+			return -1
+		}
+		return 0
+	}
+
+	config := ReadConfig(context, rootDir)
+
+	sources := resolveConfigSources(context, config, rootDir, args)
+	entries := effectiveConfig(config)
+	if name := resolveProfileName(context, args); len(name) > 0 {
+		entries = append(entries, configEntry{[]string{"profile"}, strconv.Quote(name)})
+		if args.HasGumFlag("gp") {
+			sources["profile"] = "flag -gp"
+		} else {
+			sources["profile"] = "env GUM_PROFILE"
+		}
+	}
+	if isTruthy(context.Getenv("GUM_PRINT_ONLY")) {
+		entries = append(entries, configEntry{[]string{"printOnly"}, "true"})
+		sources["printOnly"] = "env GUM_PRINT_ONLY"
+	}
+
+	printConfigEntries(entries, sources)
+	return 0
+}
+
+// Resolves the source of each setting: a flag, the project or user config file, or one of their includes
+func resolveConfigSources(context Context, config *Config, rootDir string, args *ParsedArgs) map[string]string {
+	sources := make(map[string]string)
+	applyConfigFlags(config, args, sources)
+	projectFile := resolveConfigFile(context, rootDir, projectConfigFiles)
+	collectConfigSources(context, projectFile, config.general.remoteIncludes, map[string]bool{projectFile: true}, sources)
+	userFile := findUserConfigFile(context)
+	collectConfigSources(context, userFile, true, map[string]bool{userFile: true}, sources)
+	return sources
+}
+
+// Applies the Gum flags that override configuration settings, recording them as sources
+func applyConfigFlags(config *Config, args *ParsedArgs, sources map[string]string) {
+	if args.HasGumFlag("gq") {
+		config.setQuiet(true)
+		sources["general.quiet"] = "flag -gq"
+	}
+	if args.HasGumFlag("gd") {
+		config.setDebug(true)
+		sources["general.debug"] = "flag -gd"
+	}
+	if args.HasGumFlag("gi") {
+		config.general.interactive = true
+		sources["general.interactive"] = "flag -gi"
+	}
+	if args.HasGumFlag("gr") {
+		config.gradle.setReplace(false)
+		config.maven.setReplace(false)
+		sources["gradle.replace"] = "flag -gr"
+		sources["maven.replace"] = "flag -gr"
+	}
+	if v := args.GumFlagValue("gt"); len(v) > 0 {
+		config.general.timeout = v
+		sources["general.timeout"] = "flag -gt"
+	}
+	if v, err := strconv.Atoi(args.GumFlagValue("gR")); err == nil {
+		config.general.retries = v
+		sources["general.retries"] = "flag -gR"
+	}
+}
+
+// Records the file that defines each setting found at location and its includes, following
+// the same precedence as resolveIncludes. Settings recorded earlier are kept
+func collectConfigSources(context Context, location string, remote bool, visited map[string]bool, sources map[string]string) {
+	t, err := loadInclude(context, location, remote)
+	if err != nil {
+		return
+	}
+	for path := range flattenTree(t, nil) {
+		if _, ok := sources[path]; !ok && path != "include" {
+			sources[path] = location
+		}
+	}
+
+	includes := toStringSlice(t.GetPath([]string{"include"}))
+	for i := len(includes) - 1; i >= 0; i-- {
+		target := resolveIncludeLocation(location, includes[i])
+		if !visited[target] {
+			visited[target] = true
+			collectConfigSources(context, target, remote, visited, sources)
+		}
+	}
+}
+
+// Validates the config file at location along with its includes, printing every problem found.
+// Returns the number of problems, warnings included
+func validateConfigFile(context Context, location string, remote bool, visited map[string]bool) int {
+	if !isRemoteInclude(location) && !context.FileExists(location) {
+		return 0
+	}
+
+	t, err := loadInclude(context, location, remote)
+	if err != nil {
+		fmt.Fprintln(gumOutput, "ERROR: "+location+": "+err.Error())
+		return 1
+	}
+
+	problems := validateConfigTree(location, t)
+	reportConfigProblems(problems)
+	count := len(problems)
+	if !isStringArray(t.GetPath([]string{"include"})) {
+		return count
+	}
+
+	for _, include := range toStringSlice(t.GetPath([]string{"include"})) {
+		target := resolveIncludeLocation(location, include)
+		if !visited[target] {
+			visited[target] = true
+			if !isRemoteInclude(target) && !context.FileExists(target) {
+				fmt.Fprintln(gumOutput, "ERROR: "+location+": include "+target+" not found")
+				count++
+				continue
+			}
+			count += validateConfigFile(context, target, remote, visited)
+		}
+	}
+
+	return count
+}
+
+// Lists the effective configuration, in the same order as Config.print
+func effectiveConfig(c *Config) []configEntry {
+	entries := make([]configEntry, 0)
+	add := func(value string, path ...string) {
+		entries = append(entries, configEntry{path, value})
+	}
+	addString := func(value string, path ...string) {
+		if len(value) > 0 {
+			add(strconv.Quote(value), path...)
+		}
+	}
+	addStrings := func(values []string, path ...string) {
+		if len(values) > 0 {
+			add(formatStringArray(values), path...)
+		}
+	}
+	addMap := func(m map[string]string, path ...string) {
+		for _, k := range sortedEnvKeys(m) {
+			add(strconv.Quote(m[k]), append(path, k)...)
+		}
+	}
+
+	addString(c.theme.name, "theme", "name")
+	add(strconv.FormatBool(c.general.quiet), "general", "quiet")
+	add(strconv.FormatBool(c.general.debug), "general", "debug")
+	addStrings(c.general.discovery, "general", "discovery")
+	addStrings(c.general.confirm, "general", "confirm")
+	add(strconv.FormatBool(c.general.interactive), "general", "interactive")
+	add(strconv.FormatBool(c.general.summarizeErrors), "general", "summarizeErrors")
+	add(strconv.FormatBool(c.general.annotations), "general", "annotations")
+	addString(c.general.timeout, "general", "timeout")
+	add(strconv.Itoa(c.general.retries), "general", "retries")
+	addString(c.general.retryDelay, "general", "retryDelay")
+	addString(c.general.notify, "general", "notify")
+	addString(c.general.output, "general", "output")
+	addString(c.general.console, "general", "console")
+	add(strconv.FormatBool(c.general.remoteIncludes), "general", "remoteIncludes")
+	add(strconv.FormatBool(c.general.telemetry), "general", "telemetry")
+	add(strconv.FormatBool(c.gradle.replace), "gradle", "replace")
+	add(strconv.FormatBool(c.gradle.defaults), "gradle", "defaults")
+	add(strconv.FormatBool(c.gradle.projectPaths), "gradle", "projectPaths")
+	addString(c.gradle.minVersion, "gradle", "minVersion")
+	addString(c.gradle.validation, "gradle", "wrapperValidation")
+	addStrings(c.gradle.initScripts, "gradle", "initScripts")
+	addString(c.gradle.buildScan, "gradle", "buildScan")
+	addMap(c.gradle.mappings, "gradle", "mappings")
+	add(strconv.FormatBool(c.maven.replace), "maven", "replace")
+	add(strconv.FormatBool(c.maven.defaults), "maven", "defaults")
+	add(strconv.FormatBool(c.maven.daemon), "maven", "daemon")
+	add(strconv.FormatBool(c.maven.projectList), "maven", "projectList")
+	add(strconv.FormatBool(c.maven.alsoMake), "maven", "alsoMake")
+	addString(c.maven.minVersion, "maven", "minVersion")
+	addMap(c.maven.mappings, "maven", "mappings")
+	addStrings(c.jbang.discovery, "jbang", "discovery")
+	addString(c.bach.version, "bach", "version")
+	addString(c.notify.url, "notify", "url")
+	addString(c.notify.threshold, "notify", "threshold")
+	addStrings(c.hooks.before, "hooks", "before")
+	addStrings(c.hooks.after, "hooks", "after")
+	add(strconv.FormatBool(c.discovery.vcsBoundary), "discovery", "vcsBoundary")
+	addStrings(c.discovery.boundaryMarkers, "discovery", "boundaryMarkers")
+	add(strconv.Itoa(c.discovery.maxDepth), "discovery", "maxDepth")
+	addStrings(c.discovery.priority, "discovery", "priority")
+	add(strconv.FormatBool(c.discovery.resolveSymlinks), "discovery", "resolveSymlinks")
+	addMap(c.aliases, "aliases")
+	for _, b := range c.branches {
+		for _, tool := range sortedKeys(b.args) {
+			addStrings(b.args[tool], "branch", b.pattern, tool, "defaults")
+		}
+	}
+	for _, name := range sortedProfiles(c.profiles) {
+		p := c.profiles[name]
+		for _, tool := range sortedKeys(p.args) {
+			addStrings(p.args[tool], "profiles", name, tool, "args")
+		}
+		addMap(p.env, "profiles", name, "env")
+	}
+	for _, name := range sortedCustomTools(c.tools) {
+		t := c.tools[name]
+		addStrings(t.markers, "tools", name, "markers")
+		addString(t.executable, "tools", name, "executable")
+		addString(t.wrapper, "tools", name, "wrapper")
+		addStrings(t.args, "tools", name, "args")
+	}
+
+	return entries
+}
+
+func printConfigEntries(entries []configEntry, sources map[string]string) {
+	lines := make([]string, len(entries))
+	width := 0
+	for i, e := range entries {
+		lines[i] = formatConfigKey(e.path) + " = " + e.value
+		if len(lines[i]) > width {
+			width = len(lines[i])
+		}
+	}
+
+	for i, e := range entries {
+		source, ok := sources[strings.Join(e.path, ".")]
+		if !ok {
+			source = "default"
+		}
+		fmt.Println(lines[i] + strings.Repeat(" ", width-len(lines[i])) + "  # " + source)
+	}
+}
+
+var bareConfigKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Formats a dotted key, quoting segments as TOML requires
+func formatConfigKey(path []string) string {
+	segments := make([]string, len(path))
+	for i, s := range path {
+		if bareConfigKey.MatchString(s) {
+			segments[i] = s
+		} else {
+			segments[i] = strconv.Quote(s)
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+func formatStringArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// Lists the dotted paths of the values held by a tree, tables excluded
+func flattenTree(t *toml.Tree, prefix []string) map[string]struct{} {
+	paths := make(map[string]struct{})
+	for _, key := range t.Keys() {
+		path := append(append(make([]string, 0), prefix...), key)
+		if sub, ok := t.GetPath([]string{key}).(*toml.Tree); ok {
+			for p := range flattenTree(sub, path) {
+				paths[p] = struct{}{}
+			}
+		} else {
+			paths[strings.Join(path, ".")] = struct{}{}
+		}
+	}
+	return paths
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveConfigSources(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+	os.MkdirAll(filepath.Join(home, ".config", "gum"), 0755)
+	userFile := filepath.Join(home, ".config", "gum", "gm.toml")
+	ioutil.WriteFile(userFile, []byte("[general]\ntimeout = \"10m\"\n\n[gradle]\nreplace = false\n"), 0644)
+	root := filepath.Join(home, "project")
+	os.MkdirAll(root, 0755)
+	commonFile := filepath.Join(home, "common.toml")
+	ioutil.WriteFile(commonFile, []byte("[gradle.mappings]\n\"^it(.*)$\" = \"integrationTest$1\"\n"), 0644)
+	projectFile := filepath.Join(root, ".gm.toml")
+	ioutil.WriteFile(projectFile, []byte("include = [\"../common.toml\"]\n\n[general]\ntimeout = \"20m\"\n"), 0644)
+
+	context := testContext{workingDir: root, homeDir: home}
+	config := ReadConfig(context, root)
+	args := ParseArgs([]string{"-gq", "gum", "config"})
+
+	// when:
+	sources := resolveConfigSources(context, config, root, &args)
+	entries := make(map[string]string)
+	for _, e := range effectiveConfig(config) {
+		entries[formatConfigKey(e.path)] = e.value
+	}
+
+	// then:
+	var checks = []struct {
+		path          []string
+		value, source string
+	}{
+		{[]string{"general", "quiet"}, "true", "flag -gq"},
+		{[]string{"general", "timeout"}, "\"20m\"", projectFile},
+		{[]string{"gradle", "replace"}, "false", userFile},
+		{[]string{"gradle", "mappings", "^it(.*)$"}, "\"integrationTest$1\"", commonFile},
+		{[]string{"gradle", "mappings", "compile"}, "\"classes\"", ""},
+	}
+
+	for _, check := range checks {
+		key := formatConfigKey(check.path)
+		if entries[key] != check.value {
+			t.Errorf("%s: got %s, want %s", key, entries[key], check.value)
+		}
+		if source := sources[strings.Join(check.path, ".")]; source != check.source {
+			t.Errorf("%s: got source %s, want %s", key, source, check.source)
+		}
+	}
+}
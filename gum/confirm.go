@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Asks for confirmation before running any of the tasks listed in general.confirm.
+// Passing -gconfirm answers yes; non interactive sessions must pass -gconfirm to proceed.
+// -gy does not apply, as trusting a wrapper should not confirm a deploy too
+func confirmTasks(context Context, config *Config, inv invocation) bool {
+	tasks := findConfirmableTasks(config, inv)
+	if len(tasks) == 0 || inv.args.HasGumFlag("gconfirm") {
+		return true
+	}
+
+	question := "About to run " + strings.Join(tasks, ", ") + ". Continue?"
+	if !isInteractive() {
+		fmt.Fprintln(gumOutput, question)
+		fmt.Fprintln(gumOutput, "Refusing to run without confirmation. Run again with -gconfirm to proceed")
+		return false
+	}
+
+	return confirm(question)
+}
+
+// Finds requested tasks that match an entry in general.confirm
+func findConfirmableTasks(config *Config, inv invocation) []string {
+	tasks := make([]string, 0)
+	if len(config.general.confirm) == 0 {
+		return tasks
+	}
+
+	for _, i := range findTaskArgs(inv.tool, inv.args.Args) {
+		task := inv.args.Args[i]
+		for _, c := range config.general.confirm {
+			if task == c || taskName(task) == c {
+				tasks = append(tasks, task)
+				break
+			}
+		}
+	}
+
+	return tasks
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindConfirmableTasks(t *testing.T) {
+	// given:
+	config := newConfig()
+	config.general.confirm = []string{"deploy", "publish"}
+	gradle := ParseArgs([]string{"build", ":core:publish", "-x", "publish"})
+	maven := ParseArgs([]string{"clean", "deploy", "deploy:deploy-file"})
+	confirmed := ParseArgs([]string{"-gconfirm", "deploy"})
+	trusted := ParseArgs([]string{"-gy", "deploy"})
+
+	// when:
+	gradleTasks := findConfirmableTasks(config, invocation{tool: "gradle", args: &gradle})
+	mavenTasks := findConfirmableTasks(config, invocation{tool: "maven", args: &maven})
+
+	// then:
+	if fmt.Sprint(gradleTasks) != "[:core:publish]" {
+		t.Errorf("Expected [:core:publish], got %v", gradleTasks)
+	}
+	if fmt.Sprint(mavenTasks) != "[deploy]" {
+		t.Errorf("Expected [deploy], got %v", mavenTasks)
+	}
+	if !confirmTasks(testContext{}, config, invocation{tool: "maven", args: &confirmed}) {
+		t.Error("Expected -gconfirm to confirm tasks")
+	}
+	if !isInteractive() && confirmTasks(testContext{}, config, invocation{tool: "maven", args: &trusted}) {
+		t.Error("Expected -gy not to confirm tasks")
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"os"
+	"strings"
+)
+
+// Environment variables set by CI servers
+var ciEnvVars = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "JENKINS_URL", "TEAMCITY_VERSION", "BUILDKITE", "CIRCLECI", "TF_BUILD"}
+
+// Checks if the build runs on a CI server
+func isCIEnvironment(context Context) bool {
+	for _, name := range ciEnvVars {
+		if len(context.Getenv(name)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if stdout is attached to a terminal
+func isTerminalOutput() bool {
+	stat, err := os.Stdout.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// Resolves the console mode set by general.console, either plain or rich. With auto, plain
+// is picked on CI servers or when stdout is not a terminal. Returns an empty string when off
+func resolveConsoleMode(context Context, config *Config) string {
+	switch config.general.console {
+	case "plain", "rich":
+		return config.general.console
+	case "auto":
+		if isCIEnvironment(context) || !isTerminalOutput() {
+			return "plain"
+		}
+		return "rich"
+	}
+	return ""
+}
+
+// Prepends the args matching the console mode for Gradle and Maven, unless args
+// already choose how the console looks
+func applyConsoleMode(tool string, mode string, args []string) []string {
+	var console []string
+	switch tool {
+	case "gradle":
+		if hasArgPrefix(args, "--console") {
+			return args
+		}
+		console = []string{"--console=" + mode}
+	case "maven":
+		if hasArgPrefix(args, "-B", "--batch-mode", "-Dstyle.color") {
+			return args
+		}
+		if mode == "plain" {
+			console = []string{"--batch-mode", "-Dstyle.color=never"}
+		} else {
+			console = []string{"-Dstyle.color=always"}
+		}
+	}
+
+	if len(mode) == 0 || len(console) == 0 {
+		return args
+	}
+	return append(console, args...)
+}
+
+func hasArgPrefix(args []string, prefixes ...string) bool {
+	for _, arg := range args {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(arg, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveConsoleMode(t *testing.T) {
+	var checks = []struct {
+		console  string
+		env      map[string]string
+		expected string
+	}{
+		{"off", map[string]string{"CI": "true"}, ""},
+		{"plain", map[string]string{}, "plain"},
+		{"rich", map[string]string{"CI": "true"}, "rich"},
+		{"auto", map[string]string{"CI": "true"}, "plain"},
+		{"auto", map[string]string{"GITHUB_ACTIONS": "true"}, "plain"},
+	}
+
+	for _, check := range checks {
+		// given:
+		context := testContext{env: check.env}
+		config := newConfig()
+		config.general.console = check.console
+
+		// when:
+		mode := resolveConsoleMode(context, config)
+
+		// then:
+		if mode != check.expected {
+			t.Errorf("%s %v: got %q, want %q", check.console, check.env, mode, check.expected)
+		}
+	}
+}
+
+func TestApplyConsoleMode(t *testing.T) {
+	var checks = []struct {
+		tool     string
+		mode     string
+		args     string
+		expected string
+	}{
+		{"gradle", "", "build", "build"},
+		{"gradle", "plain", "build", "--console=plain build"},
+		{"gradle", "rich", "build", "--console=rich build"},
+		{"gradle", "plain", "--console=verbose build", "--console=verbose build"},
+		{"maven", "plain", "verify", "--batch-mode -Dstyle.color=never verify"},
+		{"maven", "rich", "verify", "-Dstyle.color=always verify"},
+		{"maven", "plain", "-B verify", "-B verify"},
+		{"sbt", "plain", "compile", "compile"},
+	}
+
+	for _, check := range checks {
+		// when:
+		args := applyConsoleMode(check.tool, check.mode, strings.Fields(check.args))
+
+		// then:
+		if actual := strings.Join(args, " "); actual != check.expected {
+			t.Errorf("%s %s %s: got %s, want %s", check.tool, check.mode, check.args, actual, check.expected)
+		}
+	}
+}
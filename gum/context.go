@@ -18,8 +18,10 @@ package gum
 
 import (
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 // DefaultContext is the Context used by default
@@ -66,6 +68,11 @@ func (c DefaultContext) getPathFromEnv() string {
 	return os.Getenv("PATH")
 }
 
+// Getenv retrieves the value of the environment variable named by the key
+func (c DefaultContext) Getenv(key string) string {
+	return os.Getenv(key)
+}
+
 // GetHomeDir gets the home directory from environment
 func (c DefaultContext) GetHomeDir() string {
 	if c.IsWindows() {
@@ -86,6 +93,69 @@ func (c DefaultContext) Exit(code int) {
 	os.Exit(code)
 }
 
+// memoContext remembers the files checked by the wrapped Context while tools are detected,
+// as finders check several identical paths. Files are checked as usual once released
+type memoContext struct {
+	Context
+	mutex    sync.Mutex
+	exists   map[string]bool
+	calls    int
+	saved    int
+	released bool
+}
+
+func newMemoContext(context Context) *memoContext {
+	return &memoContext{Context: context, exists: make(map[string]bool)}
+}
+
+// FileExists checks if a file exists, at most once per path until released
+func (c *memoContext) FileExists(name string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.released {
+		return c.Context.FileExists(name)
+	}
+
+	c.calls++
+	if exists, ok := c.exists[name]; ok {
+		c.saved++
+		return exists
+	}
+
+	exists := c.Context.FileExists(name)
+	c.exists[name] = exists
+	return exists
+}
+
+// Stops remembering files, such as once the build is about to run
+func (c *memoContext) release() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.released = true
+	c.exists = nil
+}
+
+// realDirContext reports the working dir with its symlinks resolved
+type realDirContext struct {
+	Context
+	workingDir string
+}
+
+// Resolves the working dir of context to its real path, see resolveRealPath
+func newRealDirContext(context Context) Context {
+	pwd, _ := filepath.Abs(context.GetWorkingDir())
+	if real := resolveRealPath(context, pwd); real != pwd {
+		return realDirContext{Context: context, workingDir: real}
+	}
+	return context
+}
+
+// GetWorkingDir returns the real path of the working dir
+func (c realDirContext) GetWorkingDir() string {
+	return c.workingDir
+}
+
 // -----------------------------------------------
 
 type testContext struct {
@@ -95,6 +165,7 @@ type testContext struct {
 	workingDir string
 	homeDir    string
 	paths      []string
+	env        map[string]string
 	exitCode   int
 }
 
@@ -122,6 +193,10 @@ func (c testContext) GetPaths() []string {
 	return c.paths
 }
 
+func (c testContext) Getenv(key string) string {
+	return c.env[key]
+}
+
 func (c testContext) FileExists(name string) bool {
 	_, err := os.Stat(name)
 	return !os.IsNotExist(err)
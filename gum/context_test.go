@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoContext(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	existing := filepath.Join(dir, "pom.xml")
+	missing := filepath.Join(dir, "build.gradle")
+	ioutil.WriteFile(existing, []byte(""), 0644)
+	memo := newMemoContext(testContext{workingDir: dir})
+
+	// when:
+	first := memo.FileExists(existing)
+	second := memo.FileExists(existing)
+	before := memo.FileExists(missing)
+	ioutil.WriteFile(missing, []byte(""), 0644)
+	cached := memo.FileExists(missing)
+	memo.release()
+	after := memo.FileExists(missing)
+
+	// then:
+	if !first || !second {
+		t.Errorf("Existing: got %t and %t, want true", first, second)
+	}
+	if before || cached {
+		t.Errorf("Missing: got %t and %t, want false until released", before, cached)
+	}
+	if !after {
+		t.Errorf("Released: got false, want true")
+	}
+	if memo.calls != 4 || memo.saved != 2 {
+		t.Errorf("Counters: got %d calls and %d saved, want 4 and 2", memo.calls, memo.saved)
+	}
+}
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CustomCommand defines an executable command for a tool declared in config
+type CustomCommand struct {
+	context    Context
+	config     *Config
+	name       string
+	tool       *customTool
+	rootdir    string
+	executable string
+	args       *ParsedArgs
+}
+
+// Execute executes the given command
+func (c CustomCommand) Execute() int {
+	c.doConfigureCustom()
+	return ExitCode(c.doExecuteCustom())
+}
+
+func (c *CustomCommand) doConfigureCustom() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using "+c.name+" at '"+c.executable+"'")
+	banner = append(banner, "in '"+c.rootdir+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, c.tool.args)
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, c.name))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugCustom(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *CustomCommand) doExecuteCustom() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *CustomCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *CustomCommand) invocation() invocation {
+	return invocation{
+		tool:       c.name,
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		workDir:    c.rootdir,
+		args:       c.args}
+}
+
+func (c *CustomCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *CustomCommand) debugCustom(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "tool               = ", c.name)
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindCustom finds and executes a tool declared in the [tools] section of the config
+func FindCustom(context Context, args *ParsedArgs, name string, tool *customTool) *CustomCommand {
+	pwd := context.GetWorkingDir()
+
+	rootdir, noRootDir := findCustomRootDir(context, pwd, tool.markers)
+	if noRootDir != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No "+name+" project found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	executable, noExec := findCustomExec(context, rootdir, tool)
+	if noExec != nil {
+		warnNoCustom(context, config, name, tool)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &CustomCommand{
+		context:    context,
+		config:     config,
+		name:       name,
+		tool:       tool,
+		rootdir:    rootdir,
+		executable: executable,
+		args:       args}
+}
+
+func warnNoCustom(context Context, config *Config, name string, tool *customTool) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s found in path. Please install %s.", tool.executable, name)
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the nearest dir that contains any of the given marker files
+func findCustomRootDir(context Context, dir string, markers []string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	for _, marker := range markers {
+		if context.FileExists(filepath.Join(dir, marker)) {
+			return filepath.Abs(dir)
+		}
+	}
+
+	if parentdir == dir || isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find any of " + strings.Join(markers, ", "))
+	}
+
+	return findCustomRootDir(context, parentdir, markers)
+}
+
+// Finds the executable of a custom tool. A wrapper in rootdir is preferred
+// over the executable, which may be an absolute path or found in PATH
+func findCustomExec(context Context, rootdir string, tool *customTool) (string, error) {
+	if len(tool.wrapper) > 0 {
+		wrapper := filepath.Join(rootdir, tool.wrapper)
+		if isRegularFile(wrapper) {
+			return filepath.Abs(wrapper)
+		}
+	}
+
+	if len(tool.executable) == 0 {
+		return "", errors.New("No executable defined")
+	}
+
+	if filepath.IsAbs(tool.executable) {
+		if context.FileExists(tool.executable) {
+			return tool.executable, nil
+		}
+		return "", errors.New(tool.executable + " not found")
+	}
+
+	paths := context.GetPaths()
+	for _, name := range resolveCustomExecs(context, tool.executable) {
+		for i := range paths {
+			exec := filepath.Join(paths[i], name)
+			if context.FileExists(exec) {
+				return filepath.Abs(exec)
+			}
+		}
+	}
+
+	return "", errors.New(tool.executable + " not found")
+}
+
+// Resolves candidate executable names (OS dependent)
+func resolveCustomExecs(context Context, executable string) []string {
+	if context.IsWindows() && filepath.Ext(executable) == "" {
+		return []string{executable + ".exe", executable + ".cmd", executable + ".bat"}
+	}
+	return []string{executable}
+}
+
+// Returns the names of custom tools that are not shadowed by a built-in tool, sorted
+func sortedCustomTools(tools map[string]*customTool) []string {
+	names := make([]string, 0)
+	for name := range tools {
+		if !isBuiltinTool(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isBuiltinTool(name string) bool {
+	for _, tool := range defaultDiscovery {
+		if tool == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const customToolsConfig = `
+[tools.acme]
+markers = ["acme.yml"]
+executable = "acme"
+wrapper = "acmew"
+args = ["--no-daemon"]
+`
+
+func TestCustomTool(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	home := filepath.Join(dir, "home")
+	plain := filepath.Join(dir, "plain")
+	wrapped := filepath.Join(dir, "wrapped")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(home, 0755)
+	os.MkdirAll(filepath.Join(plain, "module"), 0755)
+	os.MkdirAll(wrapped, 0755)
+	ioutil.WriteFile(filepath.Join(home, "gm.toml"), []byte(customToolsConfig), 0644)
+	ioutil.WriteFile(filepath.Join(bin, "acme"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(plain, "acme.yml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(wrapped, "acme.yml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(wrapped, "acmew"), []byte(""), 0755)
+
+	var checks = []struct {
+		title, pwd, rootdir, executable string
+	}{
+		{"FromPath", filepath.Join(plain, "module"), plain, filepath.Join(bin, "acme")},
+		{"Wrapper", wrapped, wrapped, filepath.Join(wrapped, "acmew")},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: check.pwd,
+			env:        map[string]string{"GUM_HOME": home},
+			paths:      []string{bin}}
+
+		// when:
+		config := ReadUserConfig(context)
+		tool := config.tools["acme"]
+		args := ParseArgs([]string{"-gq", "build"})
+		cmd := FindCustom(context, &args, "acme", tool)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: custom tool not found", check.title)
+			continue
+		}
+		if cmd.rootdir != check.rootdir {
+			t.Errorf("%s: rootdir got %s, want %s", check.title, cmd.rootdir, check.rootdir)
+		}
+		if cmd.executable != check.executable {
+			t.Errorf("%s: executable got %s, want %s", check.title, cmd.executable, check.executable)
+		}
+
+		cmd.doConfigureCustom()
+		actual := strings.Join(cmd.args.Args, " ")
+		if actual != "--no-daemon build" {
+			t.Errorf("%s: args got %s, want %s", check.title, actual, "--no-daemon build")
+		}
+	}
+}
+
+func TestCustomToolDiscovery(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	home := filepath.Join(dir, "home")
+	project := filepath.Join(dir, "project")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(home, 0755)
+	os.MkdirAll(project, 0755)
+	ioutil.WriteFile(filepath.Join(home, "gm.toml"), []byte(customToolsConfig), 0644)
+	ioutil.WriteFile(filepath.Join(bin, "acme"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "gradle"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(project, "acme.yml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(project, "build.gradle"), []byte(""), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: project,
+		env:        map[string]string{"GUM_HOME": home},
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"build"})
+	tool := resolveRunTool(context, &args)
+
+	// then:
+	if tool != "acme" {
+		t.Errorf("got %s, want %s", tool, "acme")
+	}
+}
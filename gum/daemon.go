@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// Handles "gm gum daemon status|stop|stop-all". Status and stop act on the daemons of the
+// project at the current dir: Gradle daemons of the project's Gradle version, or mvnd for
+// Maven projects. Stop-all stops the Gradle daemons of every version along with mvnd
+func runDaemonCommand(context Context, args *ParsedArgs, params []string) int {
+	if len(params) != 1 || (params[0] != "status" && params[0] != "stop" && params[0] != "stop-all") {
+		fmt.Fprintln(gumOutput, "Usage: gm gum daemon status|stop|stop-all")
+		return -1
+	}
+
+	if params[0] == "stop-all" {
+		return stopAllDaemons(context)
+	}
+
+	flag := "--" + params[0]
+	switch tool := resolveRunTool(context, args); tool {
+	case "gradle":
+		dargs := ParseArgs([]string{flag})
+		for f := range args.Gum {
+			dargs.Gum[f] = struct{}{}
+		}
+		config, inv, err := configureTool(context, tool, &dargs)
+		if err != nil {
+			fmt.Fprintln(gumOutput, err)
+			return -1
+		}
+		if executeCommand(context, config, inv) != nil {
+			return -1
+		}
+		return 0
+	case "maven":
+		mvnd, err := findMavenDaemonExec(context)
+		if err != nil {
+			fmt.Fprintln(gumOutput, "Maven projects only run daemons when built with "+resolveMavenDaemonExec(context))
+			fmt.Fprintln(gumOutput, err)
+			return -1
+		}
+		return runDaemonExec(mvnd, flag)
+	default:
+		fmt.Fprintln(gumOutput, "Daemons are only managed for Gradle and Maven (mvnd) projects")
+		return -1
+	}
+}
+
+// Stops the daemons of every Gradle version that has a daemon registry, using the matching
+// distribution downloaded by the wrapper, and mvnd if found in path
+func stopAllDaemons(context Context) int {
+	status := 0
+	distributions := findGradleDaemonDistributions(context)
+
+	versions := make([]string, 0, len(distributions))
+	for version := range distributions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	for _, version := range versions {
+		executable := distributions[version]
+		if len(executable) == 0 {
+			fmt.Fprintln(gumOutput, "Skipping Gradle "+version+" daemons as no distribution was found")
+			continue
+		}
+		fmt.Fprintln(gumOutput, "Stopping Gradle "+version+" daemons with '"+executable+"'")
+		if runDaemonExec(executable, "--stop") != 0 {
+			status = -1
+		}
+	}
+
+	if mvnd, err := findMavenDaemonExec(context); err == nil {
+		fmt.Fprintln(gumOutput, "Stopping mvnd daemons with '"+mvnd+"'")
+		if runDaemonExec(mvnd, "--stop") != 0 {
+			status = -1
+		}
+	}
+
+	if len(versions) == 0 && status == 0 {
+		fmt.Fprintln(gumOutput, "No Gradle daemons found")
+	}
+	return status
+}
+
+// Finds the Gradle versions with a daemon registry in the Gradle user home, along with
+// the executable of a matching wrapper distribution (empty if there's none)
+func findGradleDaemonDistributions(context Context) map[string]string {
+	home := resolveGradleUserHome(context)
+	distributions := make(map[string]string)
+
+	entries, err := ioutil.ReadDir(filepath.Join(home, "daemon"))
+	if err != nil {
+		return distributions
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		version := entry.Name()
+		distributions[version] = ""
+		for _, kind := range []string{"bin", "all"} {
+			pattern := filepath.Join(home, "wrapper", "dists", "gradle-"+version+"-"+kind, "*", "gradle-"+version, "bin", resolveGradleExec(context))
+			if matches, _ := filepath.Glob(pattern); len(matches) > 0 {
+				distributions[version] = matches[0]
+				break
+			}
+		}
+	}
+
+	return distributions
+}
+
+// Resolves the Gradle user home, $GRADLE_USER_HOME or ~/.gradle
+func resolveGradleUserHome(context Context) string {
+	if home := context.Getenv("GRADLE_USER_HOME"); len(home) > 0 {
+		return home
+	}
+	return filepath.Join(context.GetHomeDir(), ".gradle")
+}
+
+// Runs a daemon management command, such as mvnd --status, and returns its exit code
+func runDaemonExec(executable string, flag string) int {
+	cmd := exec.Command(executable, flag)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			return exit.ExitCode()
+		}
+		fmt.Fprintln(gumOutput, err)
+		return -1
+	}
+	return 0
+}
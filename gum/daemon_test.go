@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindGradleDaemonDistributions(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	for _, version := range []string{"7.6", "8.7"} {
+		os.MkdirAll(filepath.Join(dir, "daemon", version), 0755)
+	}
+	gradle := filepath.Join(dir, "wrapper", "dists", "gradle-8.7-bin", "abc123", "gradle-8.7", "bin", "gradle")
+	os.MkdirAll(filepath.Dir(gradle), 0755)
+	ioutil.WriteFile(gradle, []byte(""), 0755)
+
+	context := testContext{
+		quiet:    true,
+		explicit: false,
+		windows:  false,
+		env:      map[string]string{"GRADLE_USER_HOME": dir}}
+
+	// when:
+	distributions := findGradleDaemonDistributions(context)
+
+	// then:
+	if len(distributions) != 2 {
+		t.Fatalf("Distributions: got %v", distributions)
+	}
+	if distributions["8.7"] != gradle {
+		t.Errorf("8.7: got %s, want %s", distributions["8.7"], gradle)
+	}
+	if distributions["7.6"] != "" {
+		t.Errorf("7.6: got %s, want none", distributions["7.6"])
+	}
+}
+
+func TestDaemonCommandUsage(t *testing.T) {
+	// given:
+	context := testContext{quiet: true}
+	args := ParseArgs([]string{"gum", "daemon"})
+
+	var checks = []struct {
+		params []string
+	}{
+		{[]string{}},
+		{[]string{"restart"}},
+		{[]string{"stop", "now"}},
+	}
+
+	for _, check := range checks {
+		// when:
+		code := runDaemonCommand(context, &args, check.params)
+
+		// then:
+		if code != -1 {
+			t.Errorf("%v: got %d, want -1", check.params, code)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Detection weights. A project found closer to the working dir beats one further up,
+// a project local wrapper adds confidence, and explicit flags beat everything else
+const (
+	scoreBase     = 100
+	scoreDistance = 10
+	scoreWrapper  = 5
+	scoreExplicit = 1000
+)
+
+// detectable is implemented by every tool command
+type detectable interface {
+	Command
+	invocation() invocation
+	configuration() *Config
+}
+
+// detection is a candidate tool for the current project
+type detection struct {
+	tool       string
+	command    detectable
+	dir        string
+	buildFiles []string
+	explicit   bool
+	score      int
+}
+
+// detector finds a candidate for a given tool, returning nil if there is none
+type detector func(context Context, args *ParsedArgs) *detection
+
+// detectors registers a detector per built-in tool
+var detectors = map[string]detector{
+	"ant": func(context Context, args *ParsedArgs) *detection {
+		if c := FindAnt(context, args); c != nil {
+			return newFileDetection("ant", c, c.explicitBuildFile, c.buildFile, c.rootBuildFile)
+		}
+		return nil
+	},
+	"bach": func(context Context, args *ParsedArgs) *detection {
+		if c := FindBach(context, args); c != nil {
+			return newDetection("bach", c, c.rootdir)
+		}
+		return nil
+	},
+	"bazel": func(context Context, args *ParsedArgs) *detection {
+		if c := FindBazel(context, args); c != nil {
+			return newFileDetection("bazel", c, c.workspaceFile)
+		}
+		return nil
+	},
+	"bld": func(context Context, args *ParsedArgs) *detection {
+		if c := FindBld(context, args); c != nil {
+			return newDetection("bld", c, c.rootdir)
+		}
+		return nil
+	},
+	"boot": func(context Context, args *ParsedArgs) *detection {
+		if c := FindBoot(context, args); c != nil {
+			return newFileDetection("boot", c, c.buildFile)
+		}
+		return nil
+	},
+	"buck2": func(context Context, args *ParsedArgs) *detection {
+		if c := FindBuck2(context, args); c != nil {
+			return newFileDetection("buck2", c, c.buildFile)
+		}
+		return nil
+	},
+	"clojure": func(context Context, args *ParsedArgs) *detection {
+		if c := FindClojure(context, args); c != nil {
+			return newFileDetection("clojure", c, c.buildFile, c.rootBuildFile)
+		}
+		return nil
+	},
+	"cmake": func(context Context, args *ParsedArgs) *detection {
+		if c := FindCMake(context, args); c != nil {
+			return newFileDetection("cmake", c, c.buildFile)
+		}
+		return nil
+	},
+	"gradle": func(context Context, args *ParsedArgs) *detection {
+		if c := FindGradle(context, args); c != nil {
+			return newFileDetection("gradle", c, c.explicitBuildFile, c.buildFile, c.settingsFile, c.rootBuildFile)
+		}
+		return nil
+	},
+	"grails": func(context Context, args *ParsedArgs) *detection {
+		if c := FindGrails(context, args); c != nil {
+			return newDetection("grails", c, c.rootdir)
+		}
+		return nil
+	},
+	"jbang": func(context Context, args *ParsedArgs) *detection {
+		if c := FindJbang(context, args); c != nil {
+			d := newFileDetection("jbang", c, c.explicitSourceFile, c.sourceFile)
+			d.explicit = isJbangScript(context, args)
+			return d
+		}
+		return nil
+	},
+	"lein": func(context Context, args *ParsedArgs) *detection {
+		if c := FindLein(context, args); c != nil {
+			return newFileDetection("lein", c, c.buildFile, c.rootBuildFile)
+		}
+		return nil
+	},
+	"make": func(context Context, args *ParsedArgs) *detection {
+		if c := FindMake(context, args); c != nil {
+			return newFileDetection("make", c, c.buildFile)
+		}
+		return nil
+	},
+	"maven": func(context Context, args *ParsedArgs) *detection {
+		if c := FindMaven(context, args); c != nil {
+			return newFileDetection("maven", c, c.explicitBuildFile, c.buildFile, c.rootBuildFile)
+		}
+		return nil
+	},
+	"node": func(context Context, args *ParsedArgs) *detection {
+		if c := FindNode(context, args); c != nil {
+			return newFileDetection("node", c, c.buildFile)
+		}
+		return nil
+	},
+	"sbt": func(context Context, args *ParsedArgs) *detection {
+		if c := FindSbt(context, args); c != nil {
+			return newFileDetection("sbt", c, c.buildFile)
+		}
+		return nil
+	},
+}
+
+// Creates a detection for a project located at dir
+func newDetection(tool string, command detectable, dir string) *detection {
+	if len(dir) == 0 {
+		dir = command.invocation().rootDir
+	}
+	abs, _ := filepath.Abs(dir)
+	return &detection{tool: tool, command: command, dir: abs}
+}
+
+// Creates a detection for a project located at the dir of the first non empty build file
+func newFileDetection(tool string, command detectable, files ...string) *detection {
+	d := newDetection(tool, command, dirOf(files...))
+	for _, file := range files {
+		if len(file) > 0 && !contains(d.buildFiles, file) {
+			d.buildFiles = append(d.buildFiles, file)
+		}
+	}
+	return d
+}
+
+// Resolves the dir of the first non empty file
+func dirOf(files ...string) string {
+	for _, file := range files {
+		if len(file) > 0 {
+			return filepath.Dir(file)
+		}
+	}
+	return ""
+}
+
+// Resolves a detector for the given tool, either built-in or declared in config
+func resolveDetector(config *Config, tool string) detector {
+	if d, ok := detectors[tool]; ok {
+		return d
+	}
+	if custom, ok := config.tools[tool]; ok {
+		return func(context Context, args *ParsedArgs) *detection {
+			if c := FindCustom(context, args, tool, custom); c != nil {
+				return newDetection(tool, c, c.rootdir)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// Resolves the order in which tools are scored. Configured priority and discovery come first,
+// followed by custom tools and the default discovery order. Ties are broken by this order
+func resolveDetectionOrder(config *Config) []string {
+	order := make([]string, 0)
+	seen := make(map[string]bool)
+
+	candidates := append(make([]string, 0), config.discovery.priority...)
+	candidates = append(candidates, config.general.discovery...)
+	candidates = append(candidates, sortedCustomTools(config.tools)...)
+	candidates = append(candidates, defaultDiscovery...)
+	for _, tool := range candidates {
+		tool = strings.TrimSpace(strings.ToLower(tool))
+		if !seen[tool] {
+			seen[tool] = true
+			order = append(order, tool)
+		}
+	}
+
+	return order
+}
+
+// Runs every detector and returns the candidate with the highest score, or nil if none was found
+// Results are cached per working dir until any of the paths they depend on changes
+func detectTool(context Context, config *Config, args *ParsedArgs) *detection {
+	key := detectionCacheKey(context, config, args)
+	if d := detectCachedTool(context, config, args, key); d != nil {
+		pwd, _ := filepath.Abs(context.GetWorkingDir())
+		emitEvent(args, "detectionStarted", map[string]interface{}{"pwd": pwd, "cached": true})
+		return d
+	}
+
+	best := selectDetection(context, config, detectCandidates(context, config, args))
+	if best != nil {
+		writeDetectionCache(context, key, best)
+	}
+	return best
+}
+
+// Selects the candidate with the highest score. Candidates found in the same dir, such as a
+// dir with both pom.xml and build.gradle, are ranked by discovery.priority instead when both
+// tools are listed and neither was forced with a flag
+func selectDetection(context Context, config *Config, candidates []*detection) *detection {
+	var best *detection
+	priorities := make(map[string][]string)
+
+	for _, d := range candidates {
+		if best != nil && d.dir == best.dir && !d.explicit && !best.explicit {
+			priority, ok := priorities[d.dir]
+			if !ok {
+				priority = resolveDiscoveryPriority(context, config, d.dir)
+				priorities[d.dir] = priority
+			}
+			if rank, bestRank := indexOf(priority, d.tool), indexOf(priority, best.tool); rank >= 0 && bestRank >= 0 {
+				if rank < bestRank {
+					best = d
+				}
+				continue
+			}
+		}
+		if best == nil || d.score > best.score {
+			best = d
+		}
+	}
+
+	return best
+}
+
+// Resolves discovery.priority for a project at dir, its project config takes precedence
+// over the user config
+func resolveDiscoveryPriority(context Context, config *Config, dir string) []string {
+	if priority := ReadConfig(context, dir).discovery.priority; len(priority) > 0 {
+		return priority
+	}
+	return config.discovery.priority
+}
+
+func indexOf(s []string, e string) int {
+	for i, a := range s {
+		if strings.TrimSpace(strings.ToLower(a)) == e {
+			return i
+		}
+	}
+	return -1
+}
+
+// Runs every detector and returns the scored candidates, in detection order
+func detectCandidates(context Context, config *Config, args *ParsedArgs) []*detection {
+	candidates := make([]*detection, 0)
+	order := resolveDetectionOrder(config)
+	context = withConfigCache(context)
+	pwd, _ := filepath.Abs(context.GetWorkingDir())
+	emitEvent(args, "detectionStarted", map[string]interface{}{"pwd": pwd, "order": order})
+
+	// Detectors probe the file system independently, thus they run concurrently. Debug reports
+	// are printed as each detector finishes, thus they run in discovery order instead
+	found := make([]*detection, len(order))
+	if args.HasGumFlag("gd") {
+		for i, tool := range order {
+			found[i] = runDetector(context, config, args, tool)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for i, tool := range order {
+			wg.Add(1)
+			go func(i int, tool string) {
+				defer wg.Done()
+				found[i] = runDetector(context, config, args, tool)
+			}(i, tool)
+		}
+		wg.Wait()
+	}
+
+	for _, d := range found {
+		if d != nil {
+			candidates = append(candidates, d)
+		}
+	}
+
+	return candidates
+}
+
+// Runs the detector of the given tool, scoring its candidate. Returns nil if there is none
+func runDetector(context Context, config *Config, args *ParsedArgs, tool string) *detection {
+	detect := resolveDetector(config, tool)
+	if detect == nil {
+		return nil
+	}
+
+	// Each detector gets its own args as tools strip the flags they consume
+	args = args.clone()
+	d := detect(context, args)
+	if d == nil {
+		return nil
+	}
+
+	d.score = scoreDetection(context, args, d)
+	return d
+}
+
+// Computes the confidence of a detection
+func scoreDetection(context Context, args *ParsedArgs, d *detection) int {
+	pwd, _ := filepath.Abs(context.GetWorkingDir())
+	score := scoreBase - scoreDistance*pathDistance(d.dir, pwd)
+
+	if isProjectWrapper(d.command.invocation().executable, pwd) {
+		score += scoreWrapper
+	}
+
+	if resolveForcedTool(args) == d.tool {
+		d.explicit = true
+	}
+	if d.explicit {
+		score += scoreExplicit
+	}
+
+	return score
+}
+
+// Counts the number of dirs between dir and pwd. Dirs that are not
+// parents of pwd count the full path of dir relative to pwd
+func pathDistance(dir string, pwd string) int {
+	rel, err := filepath.Rel(dir, pwd)
+	if err != nil {
+		return scoreBase / scoreDistance
+	}
+	if rel == "." {
+		return 0
+	}
+	if strings.HasPrefix(rel, "..") {
+		rel, _ = filepath.Rel(pwd, dir)
+	}
+	return len(strings.Split(rel, string(filepath.Separator)))
+}
+
+// Checks if dir is pwd or one of its parents
+func isParentDir(dir string, pwd string) bool {
+	rel, err := filepath.Rel(dir, pwd)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Checks if executable belongs to the project at pwd or one of its parents, such as a wrapper
+func isProjectWrapper(executable string, pwd string) bool {
+	return filepath.IsAbs(executable) && isParentDir(filepath.Dir(executable), pwd)
+}
@@ -0,0 +1,320 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectToolScoring(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	mixed := filepath.Join(dir, "mixed")
+	wrapped := filepath.Join(dir, "wrapped")
+	nested := filepath.Join(dir, "nested")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(mixed, 0755)
+	os.MkdirAll(wrapped, 0755)
+	os.MkdirAll(filepath.Join(nested, "module", "src"), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "gradle"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "mvn"), []byte(""), 0755)
+	for _, d := range []string{mixed, wrapped} {
+		ioutil.WriteFile(filepath.Join(d, "build.gradle"), []byte(""), 0644)
+		ioutil.WriteFile(filepath.Join(d, "pom.xml"), []byte(""), 0644)
+	}
+	ioutil.WriteFile(filepath.Join(wrapped, "mvnw"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(nested, "build.gradle"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(nested, "module", "pom.xml"), []byte(""), 0644)
+
+	var checks = []struct {
+		title, pwd string
+		flags      []string
+		expected   string
+	}{
+		{"DiscoveryOrderBreaksTies", mixed, []string{}, "gradle"},
+		{"WrapperWins", wrapped, []string{}, "maven"},
+		{"NearestWins", filepath.Join(nested, "module", "src"), []string{}, "maven"},
+		{"ExplicitFlagWins", filepath.Join(nested, "module"), []string{"-gg"}, "gradle"},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: check.pwd,
+			homeDir:    dir,
+			paths:      []string{bin}}
+		config := newConfig()
+		config.merge(nil)
+
+		// when:
+		args := ParseArgs(append(check.flags, "build"))
+		d := detectTool(context, config, &args)
+
+		// then:
+		if d == nil {
+			t.Errorf("%s: no tool detected", check.title)
+		} else if d.tool != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, d.tool, check.expected)
+		}
+	}
+}
+
+func TestPathDistance(t *testing.T) {
+	var checks = []struct {
+		dir, pwd string
+		expected int
+	}{
+		{"/a/b", "/a/b", 0},
+		{"/a", "/a/b/c", 2},
+		{"/a/b/c", "/a", 2},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := pathDistance(filepath.FromSlash(check.dir), filepath.FromSlash(check.pwd))
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s -> %s: got %d, want %d", check.dir, check.pwd, actual, check.expected)
+		}
+	}
+}
+
+func TestDetectToolPriority(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	os.MkdirAll(bin, 0755)
+	ioutil.WriteFile(filepath.Join(bin, "gradle"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "mvn"), []byte(""), 0755)
+
+	var checks = []struct {
+		title    string
+		wrapper  bool
+		user     []string
+		project  string
+		expected string
+	}{
+		{"NoPriority", true, []string{}, "", "maven"},
+		{"UserPriority", false, []string{"maven", "gradle"}, "", "maven"},
+		{"PriorityBeatsWrapper", true, []string{"gradle", "maven"}, "", "gradle"},
+		{"ProjectPriorityWins", true, []string{"maven"}, "[discovery]\npriority = [\"gradle\", \"maven\"]\n", "gradle"},
+		{"UnlistedToolIsScored", true, []string{"gradle"}, "", "maven"},
+	}
+
+	for _, check := range checks {
+		project := filepath.Join(dir, check.title)
+		os.MkdirAll(project, 0755)
+		ioutil.WriteFile(filepath.Join(project, "build.gradle"), []byte(""), 0644)
+		ioutil.WriteFile(filepath.Join(project, "pom.xml"), []byte(""), 0644)
+		if check.wrapper {
+			ioutil.WriteFile(filepath.Join(project, "mvnw"), []byte(""), 0755)
+		}
+		if len(check.project) > 0 {
+			ioutil.WriteFile(filepath.Join(project, ".gm.toml"), []byte(check.project), 0644)
+		}
+		context := testContext{
+			quiet:      true,
+			workingDir: project,
+			homeDir:    dir,
+			paths:      []string{bin}}
+		config := newConfig()
+		config.discovery.priority = check.user
+		config.merge(nil)
+
+		// when:
+		args := ParseArgs([]string{"build"})
+		d := selectDetection(context, config, detectCandidates(context, config, &args))
+
+		// then:
+		if d == nil {
+			t.Errorf("%s: no tool detected", check.title)
+		} else if d.tool != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, d.tool, check.expected)
+		}
+	}
+}
+
+func TestFindToolForced(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	home := filepath.Join(dir, "home")
+	project := filepath.Join(dir, "project")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(home, 0755)
+	os.MkdirAll(project, 0755)
+	ioutil.WriteFile(filepath.Join(home, "gm.toml"), []byte(customToolsConfig), 0644)
+	ioutil.WriteFile(filepath.Join(bin, "acme"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "gradle"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "mvn"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(project, "acme.yml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(project, "build.gradle"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(project, "pom.xml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(project, "mvnw"), []byte(""), 0755)
+
+	var checks = []struct {
+		title    string
+		input    []string
+		expected string
+	}{
+		{"Detected", []string{"build"}, "*gum.MavenCommand"},
+		{"Gradle", []string{"--gum-tool", "gradle", "build"}, "*gum.GradleCommand"},
+		{"GradleWithEquals", []string{"--gum-tool=Gradle", "build"}, "*gum.GradleCommand"},
+		{"Maven", []string{"--gum-tool=maven", "build"}, "*gum.MavenCommand"},
+		{"Custom", []string{"--gum-tool", "acme", "build"}, "*gum.CustomCommand"},
+		{"ShortFlag", []string{"-gg", "build"}, "*gum.GradleCommand"},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			workingDir: project,
+			homeDir:    home,
+			env:        map[string]string{"GUM_HOME": home},
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs(check.input)
+		cmd := FindTool(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: no tool found", check.title)
+		} else if actual := fmt.Sprintf("%T", cmd); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestResolveForcedTool(t *testing.T) {
+	var checks = []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"build"}, ""},
+		{[]string{"--gum-tool", "Maven", "build"}, "maven"},
+		{[]string{"--gum-tool=acme", "build"}, "acme"},
+		{[]string{"-gm", "build"}, "maven"},
+	}
+
+	for _, check := range checks {
+		// given:
+		args := ParseArgs(check.input)
+
+		// when:
+		actual := resolveForcedTool(&args)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%v: got %q, want %q", check.input, actual, check.expected)
+		}
+	}
+}
+
+func TestDetectCandidatesInOrder(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	project := filepath.Join(dir, "project")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(project, 0755)
+	for _, name := range []string{"ant", "gradle", "make", "mvn"} {
+		ioutil.WriteFile(filepath.Join(bin, name), []byte(""), 0755)
+	}
+	for _, name := range []string{"build.gradle", "build.xml", "Makefile", "pom.xml"} {
+		ioutil.WriteFile(filepath.Join(project, name), []byte(""), 0644)
+	}
+	context := testContext{
+		quiet:      true,
+		workingDir: project,
+		homeDir:    dir,
+		paths:      []string{bin}}
+	config := newConfig()
+	config.merge(nil)
+
+	var checks = []struct {
+		title string
+		input []string
+	}{
+		{"Concurrent", []string{"build"}},
+		{"Sequential", []string{"-gd", "build"}},
+	}
+
+	for _, check := range checks {
+		// when:
+		args := ParseArgs(check.input)
+		candidates := detectCandidates(context, config, &args)
+
+		// then:
+		tools := make([]string, 0)
+		for _, d := range candidates {
+			tools = append(tools, d.tool)
+		}
+		if actual := strings.Join(tools, ","); actual != "gradle,maven,ant,make" {
+			t.Errorf("%s: got %s, want gradle,maven,ant,make", check.title, actual)
+		}
+	}
+}
+
+func TestFindToolSelectsOutput(t *testing.T) {
+	defer func() { gumOutput = os.Stderr }()
+
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	home := filepath.Join(dir, "home")
+	project := filepath.Join(dir, "project")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(home, 0755)
+	os.MkdirAll(project, 0755)
+	ioutil.WriteFile(filepath.Join(bin, "mvn"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(project, "pom.xml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(project, ".gm.toml"), []byte("[general]\noutput = \"stdout\"\n"), 0644)
+
+	context := testContext{
+		quiet:      true,
+		workingDir: project,
+		homeDir:    home,
+		env:        map[string]string{"GUM_HOME": home},
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"build"})
+	cmd := FindTool(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Fatal("no tool found")
+	}
+	if gumOutput != os.Stdout {
+		t.Errorf("output: got %v, want %v", gumOutput, os.Stdout)
+	}
+}
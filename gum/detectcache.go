@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// detectionCacheEntry records the tool detected for a working dir along with the modification
+// times of the paths the detection depends on: the dirs walked from the working dir up to the
+// project's root, its build files, and the executable
+type detectionCacheEntry struct {
+	Key        string           `json:"key"`
+	Tool       string           `json:"tool"`
+	Executable string           `json:"executable"`
+	RootDir    string           `json:"rootDir"`
+	BuildFiles []string         `json:"buildFiles"`
+	Mtimes     map[string]int64 `json:"mtimes"`
+}
+
+// Resolves the key of a detection. Anything that changes the outcome of detectTool besides
+// files on disk is part of the key. Returns an empty key when the detection must not be cached,
+// as with JBang scripts given as args
+func detectionCacheKey(context Context, config *Config, args *ParsedArgs) string {
+	if isJbangScript(context, args) {
+		return ""
+	}
+
+	pwd, _ := filepath.Abs(context.GetWorkingDir())
+	flags := make([]string, 0)
+	for flag := range args.Gum {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+
+	return strings.Join([]string{
+		pwd,
+		strings.Join(resolveDetectionOrder(config), ","),
+		strings.Join(flags, ","),
+		strings.Join(args.Tool, " ")}, "\n")
+}
+
+// Runs the detector of the cached tool only, provided no path recorded by the entry changed and the
+// detector resolves the same project. Returns nil otherwise
+func detectCachedTool(context Context, config *Config, args *ParsedArgs, key string) *detection {
+	if len(key) == 0 {
+		return nil
+	}
+	entry := readDetectionCache(context, key)
+	if entry == nil || !entry.isFresh() {
+		return nil
+	}
+
+	detect := resolveDetector(config, entry.Tool)
+	if detect == nil {
+		return nil
+	}
+	d := detect(context, args)
+	if d == nil {
+		return nil
+	}
+
+	inv := d.command.invocation()
+	if inv.executable != entry.Executable || inv.rootDir != entry.RootDir {
+		return nil
+	}
+	d.score = scoreDetection(context, args, d)
+	return d
+}
+
+func (e *detectionCacheEntry) isFresh() bool {
+	for path, mtime := range e.Mtimes {
+		if resolveMtime(path) != mtime {
+			return false
+		}
+	}
+	return true
+}
+
+// Records the given detection, unless the project's root is not the working dir or one of its parents
+func writeDetectionCache(context Context, key string, d *detection) {
+	if len(key) == 0 {
+		return
+	}
+
+	pwd, _ := filepath.Abs(context.GetWorkingDir())
+	inv := d.command.invocation()
+	top := d.dir
+	if len(inv.rootDir) > 0 && isParentDir(inv.rootDir, top) {
+		top = inv.rootDir
+	}
+	if !isParentDir(top, pwd) {
+		return
+	}
+
+	entry := detectionCacheEntry{
+		Key:        key,
+		Tool:       d.tool,
+		Executable: inv.executable,
+		RootDir:    inv.rootDir,
+		BuildFiles: d.buildFiles,
+		Mtimes:     make(map[string]int64)}
+	for dir := pwd; ; dir = filepath.Dir(dir) {
+		entry.Mtimes[dir] = resolveMtime(dir)
+		if dir == top || filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	for _, file := range append([]string{inv.executable}, d.buildFiles...) {
+		entry.Mtimes[file] = resolveMtime(file)
+	}
+	// the project config may set discovery.priority
+	for _, name := range projectConfigFiles {
+		file := filepath.Join(d.dir, name)
+		entry.Mtimes[file] = resolveMtime(file)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	cache := resolveDetectionCacheFile(context, key)
+	if err := os.MkdirAll(filepath.Dir(cache), 0755); err == nil {
+		ioutil.WriteFile(cache, data, 0644)
+	}
+}
+
+func readDetectionCache(context Context, key string) *detectionCacheEntry {
+	data, err := ioutil.ReadFile(resolveDetectionCacheFile(context, key))
+	if err != nil {
+		return nil
+	}
+
+	var entry detectionCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil
+	}
+	return &entry
+}
+
+func resolveDetectionCacheFile(context Context, key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(resolveCacheDir(context), "detections", hex.EncodeToString(hash[:8])+".json")
+}
+
+// Resolves the modification time of path in nanoseconds, or -1 if it does not exist
+func resolveMtime(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return -1
+	}
+	return info.ModTime().UnixNano()
+}
+
+// Handles "gm gum cache clean", deleting every cache kept by Gum
+func runCacheCommand(context Context, args *ParsedArgs, params []string) int {
+	if len(params) != 1 || params[0] != "clean" {
+		fmt.Fprintln(gumOutput, "Usage: gm gum cache clean")
+		return -1
+	}
+
+	dir := resolveCacheDir(context)
+	if err := os.RemoveAll(dir); err != nil {
+		fmt.Fprintln(gumOutput, err)
+		return -1
+	}
+	fmt.Fprintln(gumOutput, "Deleted "+dir)
+	return 0
+}
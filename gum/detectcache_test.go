@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDetectionCache(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	project := filepath.Join(dir, "project")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(filepath.Join(project, "src"), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "gradle"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "mvn"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(project, "build.gradle"), []byte(""), 0644)
+	context := testContext{
+		quiet:      true,
+		workingDir: filepath.Join(project, "src"),
+		homeDir:    dir,
+		paths:      []string{bin}}
+	config := newConfig()
+	config.merge(nil)
+	args := ParseArgs([]string{"build"})
+	key := detectionCacheKey(context, config, &args)
+
+	// when:
+	d := detectTool(context, config, &args)
+
+	// then:
+	if d == nil || d.tool != "gradle" {
+		t.Fatalf("got %v, want gradle", d)
+	}
+	entry := readDetectionCache(context, key)
+	if entry == nil || entry.Tool != "gradle" || !entry.isFresh() {
+		t.Fatalf("got cache entry %v, want a fresh gradle entry", entry)
+	}
+	if _, ok := entry.Mtimes[project]; !ok {
+		t.Errorf("missing mtime of %s", project)
+	}
+	if d := detectCachedTool(context, config, &args, key); d == nil || d.tool != "gradle" {
+		t.Errorf("got %v, want cached gradle", d)
+	}
+
+	// when:
+	ioutil.WriteFile(filepath.Join(project, "pom.xml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(project, "mvnw"), []byte(""), 0755)
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(project, later, later)
+	d = detectTool(context, config, &args)
+
+	// then:
+	if d == nil || d.tool != "maven" {
+		t.Fatalf("got %v, want maven", d)
+	}
+	if entry := readDetectionCache(context, key); entry == nil || entry.Tool != "maven" {
+		t.Errorf("got cache entry %v, want maven", entry)
+	}
+}
+
+func TestDetectionCacheSkipsJbangScripts(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "hello.java"), []byte("///usr/bin/env jbang \"$0\" \"$@\" ; exit $?\n"), 0644)
+	context := testContext{workingDir: dir, homeDir: dir}
+	config := newConfig()
+	args := ParseArgs([]string{"hello.java"})
+
+	// when:
+	key := detectionCacheKey(context, config, &args)
+
+	// then:
+	if len(key) > 0 {
+		t.Errorf("got key %q, want none", key)
+	}
+}
+
+func TestCacheClean(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	context := testContext{homeDir: dir, env: map[string]string{"GUM_HOME": dir}}
+	cache := resolveDetectionCacheFile(context, "key")
+	os.MkdirAll(filepath.Dir(cache), 0755)
+	ioutil.WriteFile(cache, []byte("{}"), 0644)
+	args := ParseArgs([]string{"gum", "cache", "clean"})
+
+	var checks = []struct {
+		title    string
+		params   []string
+		expected int
+	}{
+		{"Usage", []string{}, -1},
+		{"Clean", []string{"clean"}, 0},
+	}
+
+	for _, check := range checks {
+		// when:
+		code := runCacheCommand(context, &args, check.params)
+
+		// then:
+		if code != check.expected {
+			t.Errorf("%s: got %d, want %d", check.title, code, check.expected)
+		}
+	}
+	if _, err := os.Stat(cache); !os.IsNotExist(err) {
+		t.Errorf("cache %s was not deleted", cache)
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	gocontext "context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Scripts that configure Develocity (formerly Gradle Enterprise) next to the settings file
+var develocityFiles = []string{
+	"develocity.gradle",
+	"develocity.gradle.kts",
+	"gradle-enterprise.gradle",
+	"gradle-enterprise.gradle.kts"}
+
+// Plugin ids and extension blocks that configure Develocity in a settings file
+var develocityMarkers = []string{
+	"com.gradle.develocity",
+	"com.gradle.enterprise",
+	"develocity {",
+	"gradleEnterprise {"}
+
+// Matches the URL printed by Gradle once a build scan has been published
+var buildScanURL = regexp.MustCompile(`^\s*(https?://\S+/s/[A-Za-z0-9]+)\s*$`)
+
+// Checks if the build given by its settings file publishes build scans to Develocity
+func hasDevelocity(context Context, settingsFile string) bool {
+	if len(settingsFile) == 0 {
+		return false
+	}
+
+	dir := filepath.Dir(settingsFile)
+	for _, name := range develocityFiles {
+		if context.FileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+
+	content, err := ioutil.ReadFile(settingsFile)
+	if err != nil {
+		return false
+	}
+
+	settings := string(content)
+	for _, marker := range develocityMarkers {
+		if strings.Contains(settings, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolves --scan or --no-scan when forced with -gscan or -gnoscan, or when gradle.buildScan
+// is set to always or never, unless given explicitly as a Gradle arg
+func resolveScanArgs(config *Config, args *ParsedArgs) []string {
+	if hasScanArg(args) {
+		return []string{}
+	}
+	if args.HasGumFlag("gscan") || (!args.HasGumFlag("gnoscan") && config.gradle.buildScan == "always") {
+		return []string{"--scan"}
+	} else if args.HasGumFlag("gnoscan") || config.gradle.buildScan == "never" {
+		return []string{"--no-scan"}
+	}
+	return []string{}
+}
+
+// Checks if a failed build should run again with --scan, as set by gradle.buildScan = on-failure.
+// Flags and args that choose whether to publish a build scan take precedence
+func isScanOnFailure(config *Config, args *ParsedArgs) bool {
+	return config.gradle.buildScan == "on-failure" &&
+		!args.HasGumFlag("gscan") &&
+		!args.HasGumFlag("gnoscan") &&
+		!hasScanArg(args)
+}
+
+func hasScanArg(args *ParsedArgs) bool {
+	for _, arg := range appendSafe(appendSafe(make([]string, 0), args.Tool), args.Args) {
+		if arg == "--scan" || arg == "--no-scan" {
+			return true
+		}
+	}
+	return false
+}
+
+// scanWatcher captures the build scan URL from the output of a Gradle build
+type scanWatcher struct {
+	line string
+	url  string
+}
+
+func (w *scanWatcher) Write(p []byte) (int, error) {
+	lines := strings.Split(w.line+string(p), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		if m := buildScanURL.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			w.url = m[1]
+		}
+	}
+	w.line = lines[len(lines)-1]
+	return len(p), nil
+}
+
+// Runs a failed build once more with --scan, capturing the URL of the published build scan
+func rerunWithScan(ctx gocontext.Context, config *Config, inv invocation, timeout time.Duration) (*outputWatchers, error) {
+	if !config.general.quiet {
+		fmt.Fprintln(gumOutput, "Build failed, running it again with --scan")
+	}
+
+	args := *inv.args
+	args.Args = append(append(make([]string, 0), inv.args.Args...), "--scan")
+	inv.args = &args
+	inv.watchScan = true
+
+	cmd, pty, watchers := newBuildCommand(ctx, config, inv)
+	_, err := runForwardingSignals(ctx, cmd, timeout, pty, lowPriorityHook(config, inv))
+	return watchers, err
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHasDevelocity(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+
+	var checks = []struct {
+		title, script, settings string
+		expected                bool
+	}{
+		{"Plugin", "", "plugins {\n  id(\"com.gradle.develocity\") version \"3.17\"\n}\n", true},
+		{"Legacy", "", "plugins {\n  id 'com.gradle.enterprise' version '3.16'\n}\n", true},
+		{"Script", "gradle-enterprise.gradle", "apply from: 'gradle-enterprise.gradle'\n", true},
+		{"None", "", "rootProject.name = 'app'\n", false},
+	}
+
+	for _, check := range checks {
+		project := filepath.Join(dir, check.title)
+		os.MkdirAll(project, 0755)
+		settingsFile := filepath.Join(project, "settings.gradle")
+		ioutil.WriteFile(settingsFile, []byte(check.settings), 0644)
+		if len(check.script) > 0 {
+			ioutil.WriteFile(filepath.Join(project, check.script), []byte(""), 0644)
+		}
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: project}
+
+		// when:
+		actual := hasDevelocity(context, settingsFile)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %t, want %t", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestResolveScanArgs(t *testing.T) {
+	var checks = []struct {
+		title     string
+		buildScan string
+		input     []string
+		expected  string
+		onFailure bool
+	}{
+		{"Scan", "", []string{"-gscan", "build"}, "--scan", false},
+		{"NoScan", "", []string{"-gnoscan", "build"}, "--no-scan", false},
+		{"Explicit", "", []string{"-gnoscan", "build", "--scan"}, "", false},
+		{"None", "", []string{"build"}, "", false},
+		{"Always", "always", []string{"build"}, "--scan", false},
+		{"AlwaysNoScan", "always", []string{"-gnoscan", "build"}, "--no-scan", false},
+		{"Never", "never", []string{"build"}, "--no-scan", false},
+		{"NeverScan", "never", []string{"-gscan", "build"}, "--scan", false},
+		{"OnFailure", "on-failure", []string{"build"}, "", true},
+		{"OnFailureExplicit", "on-failure", []string{"build", "--no-scan"}, "", false},
+	}
+
+	for _, check := range checks {
+		// given:
+		args := ParseArgs(check.input)
+		config := newConfig()
+		config.gradle.buildScan = check.buildScan
+
+		// when:
+		actual := strings.Join(resolveScanArgs(config, &args), " ")
+		onFailure := isScanOnFailure(config, &args)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+		if onFailure != check.onFailure {
+			t.Errorf("%s: got on failure %t, want %t", check.title, onFailure, check.onFailure)
+		}
+	}
+}
+
+func TestScanWatcher(t *testing.T) {
+	// given:
+	w := &scanWatcher{}
+
+	// when:
+	w.Write([]byte("BUILD SUCCESSFUL in 2s\n\nPublishing build scan...\nhttps://gradle.com/s/"))
+	w.Write([]byte("abc123xyz\r\n\n"))
+
+	// then:
+	if w.url != "https://gradle.com/s/abc123xyz" {
+		t.Errorf("got %s, want %s", w.url, "https://gradle.com/s/abc123xyz")
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// Prints original and final args side by side when either -gd or -gshow are set
+func showArgsDiff(config *Config, args *ParsedArgs, original []string) {
+	if !config.general.debug && !args.HasGumFlag("gshow") {
+		return
+	}
+
+	colored := isInstanceOf(config.theme.t, (*ColoredTheme)(nil))
+	rows := diffArgs(original, args.Args)
+
+	width := len("original")
+	for _, row := range rows {
+		if len(row[0]) > width {
+			width = len(row[0])
+		}
+	}
+
+	fmt.Fprintln(gumOutput, pad("original", width)+" | final")
+	fmt.Fprintln(gumOutput, strings.Repeat("-", width)+"-+-"+strings.Repeat("-", width))
+	for _, row := range rows {
+		left := pad(row[0], width)
+		right := row[1]
+		if colored && row[0] != row[1] {
+			left = color.Red.Sprint(left)
+			right = color.Green.Sprint(right)
+		}
+		fmt.Fprintln(gumOutput, left+" | "+right)
+	}
+	fmt.Fprintln(gumOutput, "")
+}
+
+// Aligns two lists of args using their longest common subsequence.
+// Each row holds the original and the final value; either may be empty
+func diffArgs(original []string, final []string) [][2]string {
+	n := len(original)
+	m := len(final)
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if original[i] == final[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	rows := make([][2]string, 0)
+	removed := make([]string, 0)
+	added := make([]string, 0)
+
+	// pairs removed args with added args so that replacements share a row
+	flush := func() {
+		for k := 0; k < len(removed) || k < len(added); k++ {
+			var row [2]string
+			if k < len(removed) {
+				row[0] = removed[k]
+			}
+			if k < len(added) {
+				row[1] = added[k]
+			}
+			rows = append(rows, row)
+		}
+		removed = removed[:0]
+		added = added[:0]
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && original[i] == final[j]:
+			flush()
+			rows = append(rows, [2]string{original[i], final[j]})
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, final[j])
+			j++
+		default:
+			removed = append(removed, original[i])
+			i++
+		}
+	}
+	flush()
+
+	return rows
+}
+
+func pad(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
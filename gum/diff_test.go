@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffArgs(t *testing.T) {
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Same", fmt.Sprint(diffArgs([]string{"build"}, []string{"build"})), "[[build build]]"},
+		{"Replaced", fmt.Sprint(diffArgs([]string{"compile", "-S"}, []string{"classes", "-S"})), "[[compile classes] [-S -S]]"},
+		{"Added", fmt.Sprint(diffArgs([]string{"build"}, []string{"-b", "build.gradle", "build"})), "[[ -b] [ build.gradle] [build build]]"},
+		{"Removed", fmt.Sprint(diffArgs([]string{"ci", "test"}, []string{"test"})), "[[ci ] [test test]]"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
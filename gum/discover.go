@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// discoveryResult describes every candidate tool found for the current dir, without executing any of them
+type discoveryResult struct {
+	WorkingDir string               `json:"workingDir"`
+	UserConfig string               `json:"userConfig,omitempty"`
+	Selected   string               `json:"selected,omitempty"`
+	Candidates []discoveryCandidate `json:"candidates"`
+}
+
+type discoveryCandidate struct {
+	Tool       string   `json:"tool"`
+	Executable string   `json:"executable"`
+	Wrapper    bool     `json:"wrapper"`
+	RootDir    string   `json:"rootDir"`
+	BuildFiles []string `json:"buildFiles"`
+	Config     string   `json:"config,omitempty"`
+	Score      int      `json:"score"`
+	Explicit   bool     `json:"explicit"`
+	Selected   bool     `json:"selected"`
+}
+
+// Handles "gm gum discover [--json]", running detection only and reporting every candidate
+func runDiscoverCommand(context Context, args *ParsedArgs, params []string) int {
+	asJSON, _ := hasOption("--json", params)
+	result := discover(context, args)
+
+	if asJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			fmt.Fprintln(gumOutput, err)
+			return -1
+		}
+		fmt.Println(string(data))
+	} else {
+		printDiscoveryResult(result)
+	}
+
+	if len(result.Candidates) == 0 {
+		return -1
+	}
+	return 0
+}
+
+// Runs detection for the current dir, honoring forced tool flags such as -gg
+func discover(context Context, args *ParsedArgs) discoveryResult {
+	config := ReadUserConfig(context)
+	config.merge(nil)
+	pwd, _ := filepath.Abs(context.GetWorkingDir())
+
+	result := discoveryResult{
+		WorkingDir: pwd,
+		Candidates: make([]discoveryCandidate, 0)}
+	if userFile := findUserConfigFile(context); context.FileExists(userFile) {
+		result.UserConfig = userFile
+	}
+
+	probe := ParseArgs([]string{})
+	for flag := range args.Gum {
+		probe.Gum[flag] = struct{}{}
+	}
+
+	candidates := detectCandidates(context, config, &probe)
+	best := selectDetection(context, config, candidates)
+	for _, d := range candidates {
+		inv := d.command.invocation()
+		buildFiles := d.buildFiles
+		if len(buildFiles) == 0 && len(inv.buildFile) > 0 {
+			buildFiles = []string{inv.buildFile}
+		}
+		if buildFiles == nil {
+			buildFiles = make([]string, 0)
+		}
+		rootDir := inv.rootDir
+		if len(rootDir) == 0 {
+			rootDir = d.dir
+		}
+
+		configFile := resolveConfigFile(context, rootDir, projectConfigFiles)
+		if !context.FileExists(configFile) {
+			configFile = ""
+		}
+
+		result.Candidates = append(result.Candidates, discoveryCandidate{
+			Tool:       d.tool,
+			Executable: inv.executable,
+			Wrapper:    isProjectWrapper(inv.executable, pwd),
+			RootDir:    rootDir,
+			BuildFiles: buildFiles,
+			Config:     configFile,
+			Score:      d.score,
+			Explicit:   d.explicit,
+			Selected:   d == best})
+	}
+
+	if best != nil {
+		result.Selected = best.tool
+	}
+	return result
+}
+
+func printDiscoveryResult(result discoveryResult) {
+	if len(result.Candidates) == 0 {
+		fmt.Println("Did not find a project at " + result.WorkingDir)
+		return
+	}
+
+	for _, c := range result.Candidates {
+		marker := " "
+		if c.Selected {
+			marker = "*"
+		}
+		fmt.Println(marker + " " + c.Tool + " (score " + strconv.Itoa(c.Score) + ")")
+		fmt.Println("    executable  = " + c.Executable)
+		fmt.Println("    rootDir     = " + c.RootDir)
+		for _, file := range c.BuildFiles {
+			fmt.Println("    buildFile   = " + file)
+		}
+		if len(c.Config) > 0 {
+			fmt.Println("    config      = " + c.Config)
+		}
+	}
+}
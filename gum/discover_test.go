@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	project := filepath.Join(dir, "project")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(project, 0755)
+	ioutil.WriteFile(filepath.Join(bin, "gradle"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "mvn"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(project, "build.gradle"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(project, "pom.xml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(project, "mvnw"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(project, ".gm.toml"), []byte(""), 0644)
+	context := testContext{
+		quiet:      true,
+		workingDir: project,
+		homeDir:    dir,
+		paths:      []string{bin}}
+
+	var checks = []struct {
+		title    string
+		flags    []string
+		selected string
+		wrapper  bool
+	}{
+		{"WrapperWins", []string{}, "maven", true},
+		{"ExplicitFlagWins", []string{"-gg"}, "gradle", false},
+	}
+
+	for _, check := range checks {
+		// when:
+		args := ParseArgs(append(check.flags, "gum", "discover"))
+		result := discover(context, &args)
+
+		// then:
+		if result.Selected != check.selected {
+			t.Errorf("%s: got %s, want %s", check.title, result.Selected, check.selected)
+		}
+		if len(result.Candidates) != 2 {
+			t.Errorf("%s: got %d candidates, want 2", check.title, len(result.Candidates))
+			continue
+		}
+		for _, c := range result.Candidates {
+			if c.Selected != (c.Tool == check.selected) {
+				t.Errorf("%s: %s selected = %t", check.title, c.Tool, c.Selected)
+			}
+			if c.Tool == check.selected && c.Wrapper != check.wrapper {
+				t.Errorf("%s: got wrapper %t, want %t", check.title, c.Wrapper, check.wrapper)
+			}
+			if len(c.BuildFiles) == 0 || filepath.Dir(c.BuildFiles[0]) != project {
+				t.Errorf("%s: %s unexpected build files %v", check.title, c.Tool, c.BuildFiles)
+			}
+			if c.Config != filepath.Join(project, ".gm.toml") {
+				t.Errorf("%s: %s got config %s", check.title, c.Tool, c.Config)
+			}
+		}
+	}
+}
+
+func TestDiscoverJson(t *testing.T) {
+	// given:
+	result := discoveryResult{
+		WorkingDir: "/project",
+		Selected:   "maven",
+		Candidates: []discoveryCandidate{{Tool: "maven", Executable: "/project/mvnw", Wrapper: true,
+			RootDir: "/project", BuildFiles: []string{"/project/pom.xml"}, Score: 105, Selected: true}}}
+
+	// when:
+	data, _ := json.Marshal(result)
+	var decoded map[string]interface{}
+	err := json.Unmarshal(data, &decoded)
+
+	// then:
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidate := decoded["candidates"].([]interface{})[0].(map[string]interface{})
+	for _, key := range []string{"tool", "executable", "wrapper", "rootDir", "buildFiles", "score", "explicit", "selected"} {
+		if _, ok := candidate[key]; !ok {
+			t.Errorf("missing key %s", key)
+		}
+	}
+	if _, ok := candidate["config"]; ok {
+		t.Errorf("got config, want it omitted when empty")
+	}
+}
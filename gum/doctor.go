@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Tools that need a JDK to run
+var jvmTools = map[string]bool{
+	"ant": true, "bach": true, "bld": true, "gradle": true, "jbang": true, "maven": true, "sbt": true,
+}
+
+// doctorCheck is the outcome of a single diagnostic. Failed and warning checks carry a fix
+type doctorCheck struct {
+	status  string
+	message string
+	fix     string
+}
+
+// Handles "gm gum doctor", diagnosing the environment of the current project
+func runDoctorCommand(context Context, args *ParsedArgs, params []string) int {
+	code := 0
+	for _, check := range runDoctorChecks(context, resolveRunTool(context, args)) {
+		fmt.Printf("%-7s%s\n", "["+check.status+"]", check.message)
+		if len(check.fix) > 0 {
+			fmt.Println("       fix: " + check.fix)
+		}
+		if check.status == "fail" {
+			code = -1
+		}
+	}
+	return code
+}
+
+// Runs every diagnostic for a project built with the given tool, which may be empty
+func runDoctorChecks(context Context, tool string) []doctorCheck {
+	rootDir := resolveProjectRootDir(context)
+	checks := make([]doctorCheck, 0)
+
+	if len(tool) > 0 {
+		checks = append(checks, doctorCheck{"ok", "Detected " + tool + " project at " + rootDir, ""})
+	} else {
+		checks = append(checks, doctorCheck{"warn", "No project detected at " + context.GetWorkingDir(),
+			"Run gm from a directory containing a supported build file"})
+	}
+
+	walk := walkUp(context, rootDir)
+	gradleWrapper, _ := findGradleWrapperExec(walk, rootDir)
+	mavenWrapper, _ := findMavenWrapperExec(walk, rootDir)
+	gradle, _ := findGradleExec(context)
+	maven, _ := findMavenExec(context)
+	checks = append(checks, checkToolInPath("gradle", gradle, tool == "gradle" && len(gradleWrapper) == 0,
+		"Install Gradle and add its bin directory to PATH, or add a wrapper to the project"))
+	checks = append(checks, checkToolInPath("mvn", maven, tool == "maven" && len(mavenWrapper) == 0,
+		"Install Maven and add its bin directory to PATH, or add a wrapper to the project"))
+	if len(gradleWrapper) > 0 {
+		checks = append(checks, checkWrapper(context, gradleWrapper, "gradle wrapper")...)
+	}
+	if len(mavenWrapper) > 0 {
+		checks = append(checks, checkWrapper(context, mavenWrapper, "mvn wrapper:wrapper")...)
+	}
+
+	checks = append(checks, checkConfigFiles(context, rootDir))
+	return append(checks, checkJava(context, jvmTools[tool])...)
+}
+
+func checkToolInPath(name string, executable string, required bool, fix string) doctorCheck {
+	if len(executable) > 0 {
+		return doctorCheck{"ok", name + " found at " + executable, ""}
+	}
+	if required {
+		return doctorCheck{"fail", name + " not found in PATH and the project has no wrapper", fix}
+	}
+	return doctorCheck{"warn", name + " not found in PATH", fix}
+}
+
+// Checks the wrapper is executable and configured. regenerate is the command that recreates it
+func checkWrapper(context Context, wrapper string, regenerate string) []doctorCheck {
+	checks := make([]doctorCheck, 0)
+	info, err := os.Stat(wrapper)
+	if err != nil {
+		return append(checks, doctorCheck{"fail", "Cannot read " + wrapper + ": " + err.Error(), "Check the permissions of " + filepath.Dir(wrapper)})
+	}
+	if !context.IsWindows() && info.Mode()&0111 == 0 {
+		checks = append(checks, doctorCheck{"fail", wrapper + " is not executable", "chmod +x " + wrapper})
+	} else {
+		checks = append(checks, doctorCheck{"ok", "Wrapper found at " + wrapper, ""})
+	}
+	if len(resolveWrapperProperties(context, wrapper)) == 0 {
+		checks = append(checks, doctorCheck{"fail", "Wrapper properties of " + wrapper + " are missing", "Regenerate the wrapper with '" + regenerate + "'"})
+	}
+	return checks
+}
+
+func checkConfigFiles(context Context, rootDir string) doctorCheck {
+	userFile := findUserConfigFile(context)
+	remote := readConfigFile(context, userFile, true, nil).general.remoteIncludes
+	problems := validateConfigFile(context, userFile, true, map[string]bool{userFile: true})
+	projectFile := resolveConfigFile(context, rootDir, projectConfigFiles)
+	problems += validateConfigFile(context, projectFile, remote, map[string]bool{projectFile: true})
+
+	if problems > 0 {
+		return doctorCheck{"fail", strconv.Itoa(problems) + " problem(s) found in config files", "Fix the problems reported by 'gm gum config --validate'"}
+	}
+	return doctorCheck{"ok", "Config files are valid", ""}
+}
+
+// Checks JAVA_HOME and the JDK version. Problems are failures only when the project needs a JDK
+func checkJava(context Context, required bool) []doctorCheck {
+	status := "warn"
+	if required {
+		status = "fail"
+	}
+
+	checks := make([]doctorCheck, 0)
+	java := "java"
+	if context.IsWindows() {
+		java = "java.exe"
+	}
+	home := context.Getenv("JAVA_HOME")
+	if len(home) == 0 {
+		checks = append(checks, doctorCheck{"warn", "JAVA_HOME is not set", "Set JAVA_HOME to the directory of your JDK"})
+	} else if !context.FileExists(filepath.Join(home, "bin", java)) {
+		checks = append(checks, doctorCheck{status, "JAVA_HOME points to " + home + " which has no bin/" + java,
+			"Set JAVA_HOME to the directory of your JDK"})
+	} else {
+		checks = append(checks, doctorCheck{"ok", "JAVA_HOME is " + home, ""})
+	}
+
+	if version := resolveJavaVersion(context); len(version) > 0 {
+		return append(checks, doctorCheck{"ok", "Java " + version, ""})
+	}
+	return append(checks, doctorCheck{status, "Java not found", "Install a JDK, then set JAVA_HOME or add java to PATH"})
+}
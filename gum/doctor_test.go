@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDoctorChecks(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "build.gradle"), []byte{}, 0644)
+	wrapper := filepath.Join(dir, "gradlew")
+	ioutil.WriteFile(wrapper, []byte{}, 0644)
+	context := testContext{workingDir: dir, homeDir: dir, env: map[string]string{}}
+
+	// when:
+	checks := runDoctorChecks(context, "gradle")
+
+	// then:
+	expected := map[string]string{
+		"gradle not found in PATH":                          "warn",
+		wrapper + " is not executable":                      "fail",
+		"Wrapper properties of " + wrapper + " are missing": "fail",
+		"Config files are valid":                            "ok",
+		"JAVA_HOME is not set":                              "warn",
+		"Java not found":                                    "fail",
+	}
+	assertDoctorChecks(t, checks, expected)
+
+	// given:
+	os.Chmod(wrapper, 0755)
+	os.MkdirAll(filepath.Join(dir, "gradle", "wrapper"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "gradle", "wrapper", "gradle-wrapper.properties"), []byte{}, 0644)
+
+	// when:
+	checks = runDoctorChecks(context, "gradle")
+
+	// then:
+	assertDoctorChecks(t, checks, map[string]string{"Wrapper found at " + wrapper: "ok"})
+	for _, check := range checks {
+		if check.status == "fail" && check.message != "Java not found" {
+			t.Errorf("got unexpected failure %s", check.message)
+		}
+	}
+}
+
+func TestDoctorJavaIsOptionalForNonJvmTools(t *testing.T) {
+	// given:
+	context := testContext{env: map[string]string{"JAVA_HOME": "/does/not/exist"}}
+
+	// when:
+	checks := checkJava(context, jvmTools["make"])
+
+	// then:
+	for _, check := range checks {
+		if check.status != "warn" {
+			t.Errorf("%s: got %s, want warn", check.message, check.status)
+		}
+	}
+}
+
+func assertDoctorChecks(t *testing.T, checks []doctorCheck, expected map[string]string) {
+	for message, status := range expected {
+		found := false
+		for _, check := range checks {
+			if check.message == message {
+				found = true
+				if check.status != status {
+					t.Errorf("%s: got %s, want %s", message, check.status, status)
+				}
+				if check.status != "ok" && len(check.fix) == 0 {
+					t.Errorf("%s: missing fix", message)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("missing check %s", message)
+		}
+	}
+}
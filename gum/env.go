@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables that commonly explain differences between machines
+var snapshotEnvKeys = []string{
+	"JAVA_HOME",
+	"JAVA_OPTS",
+	"JAVA_TOOL_OPTIONS",
+	"GRADLE_HOME",
+	"GRADLE_USER_HOME",
+	"GRADLE_OPTS",
+	"MAVEN_HOME",
+	"M2_HOME",
+	"MAVEN_OPTS",
+	"MAVEN_ARGS",
+	"ANT_HOME",
+	"ANT_OPTS",
+	"JBANG_HOME",
+	"PATH",
+}
+
+// The number of PATH entries included in a snapshot
+const snapshotPathEntries = 5
+
+// envVar is a single entry of an environment snapshot
+type envVar struct {
+	key   string
+	value string
+}
+
+// Takes a snapshot of the environment variables relevant to builds.
+// Unset variables are skipped and PATH is truncated
+func snapshotEnv(context Context) []envVar {
+	snapshot := make([]envVar, 0)
+	for _, key := range snapshotEnvKeys {
+		value := context.Getenv(key)
+		if len(value) == 0 {
+			continue
+		}
+		if key == "PATH" {
+			value = truncatePath(value)
+		}
+		snapshot = append(snapshot, envVar{key: key, value: value})
+	}
+	return snapshot
+}
+
+func truncatePath(path string) string {
+	entries := strings.Split(path, string(os.PathListSeparator))
+	if len(entries) <= snapshotPathEntries {
+		return path
+	}
+
+	truncated := strings.Join(entries[:snapshotPathEntries], string(os.PathListSeparator))
+	return truncated + " ... (" + strconv.Itoa(len(entries)-snapshotPathEntries) + " more)"
+}
+
+func debugEnv(context Context, config *Config) {
+	if config.general.debug {
+		for _, v := range snapshotEnv(context) {
+			fmt.Fprintln(gumOutput, v.key+strings.Repeat(" ", 21-len(v.key))+"= ", v.value)
+		}
+		fmt.Fprintln(gumOutput, "")
+	}
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotEnv(t *testing.T) {
+	// given:
+	sep := string(os.PathListSeparator)
+	context := testContext{
+		env: map[string]string{
+			"JAVA_HOME":  "/opt/java",
+			"MAVEN_OPTS": "-Xmx1g",
+			"HOME":       "/home/duke",
+			"PATH":       strings.Join([]string{"/a", "/b", "/c", "/d", "/e", "/f", "/g"}, sep)}}
+
+	// when:
+	snapshot := snapshotEnv(context)
+
+	// then:
+	expected := fmt.Sprint([]envVar{
+		{"JAVA_HOME", "/opt/java"},
+		{"MAVEN_OPTS", "-Xmx1g"},
+		{"PATH", strings.Join([]string{"/a", "/b", "/c", "/d", "/e"}, sep) + " ... (2 more)"}})
+	if fmt.Sprint(snapshot) != expected {
+		t.Errorf("got %v, want %s", snapshot, expected)
+	}
+}
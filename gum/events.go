@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Files of the descriptors given with --gum-events. They are kept open as the descriptors
+// are owned by the parent process, which would otherwise see them closed once collected
+var eventDescriptors = make(map[int]*os.File)
+
+// Writes an event as a single line of JSON to the file or file descriptor given with --gum-events, if any.
+// Events are appended, thus a file may collect the events of several invocations
+func emitEvent(args *ParsedArgs, event string, fields map[string]interface{}) {
+	if args == nil || !args.HasGumFlag("-gum-events") {
+		return
+	}
+
+	data := map[string]interface{}{
+		"event": event,
+		"time":  time.Now().Format(time.RFC3339Nano)}
+	for k, v := range fields {
+		data[k] = v
+	}
+	line, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	if err := writeEvent(args.GumFlagValue("-gum-events"), append(line, '\n')); err != nil && !args.HasGumFlag("gq") {
+		fmt.Fprintln(gumOutput, "WARNING: could not write event to "+args.GumFlagValue("-gum-events"))
+		fmt.Fprintln(gumOutput, err)
+	}
+}
+
+// Writes to a file descriptor when target is a number, otherwise appends to the file named target
+func writeEvent(target string, line []byte) error {
+	if len(target) == 0 {
+		return errors.New("Missing target of --gum-events")
+	}
+
+	if fd, err := strconv.Atoi(target); err == nil {
+		file, ok := eventDescriptors[fd]
+		if !ok {
+			file = os.NewFile(uintptr(fd), "events")
+			eventDescriptors[fd] = file
+		}
+		_, err = file.Write(line)
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(line)
+	return err
+}
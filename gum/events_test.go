@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseEventsFlag(t *testing.T) {
+	var checks = []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"--gum-events", "events.ndjson", "build"}, "events.ndjson"},
+		{[]string{"--gum-events=3", "build"}, "3"},
+	}
+
+	for _, check := range checks {
+		// when:
+		args := ParseArgs(check.input)
+
+		// then:
+		if args.GumFlagValue("-gum-events") != check.expected || len(args.Args) != 1 || args.Args[0] != "build" {
+			t.Errorf("%v: unexpected args %v", check.input, args)
+		}
+	}
+}
+
+func TestExecuteCommandEvents(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	failing := filepath.Join(dir, "failing")
+	ioutil.WriteFile(failing, []byte("#!/bin/sh\nexit 3\n"), 0755)
+	events := filepath.Join(dir, "events.ndjson")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: dir,
+		homeDir:    dir}
+	config := newConfig()
+	config.setQuiet(true)
+	args := ParseArgs([]string{"--gum-events", events, "build"})
+
+	// when:
+	executeCommand(context, config, invocation{tool: "make", executable: failing, rootDir: dir, args: &args})
+
+	// then:
+	content, err := ioutil.ReadFile(events)
+	if err != nil {
+		t.Fatalf("expected events: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	expected := []string{"toolResolved", "processStarted", "processExited"}
+	if len(lines) != len(expected) {
+		t.Fatalf("got %d events, want %d: %s", len(lines), len(expected), content)
+	}
+
+	for i, line := range lines {
+		event := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid event %s: %v", line, err)
+		}
+		if event["event"] != expected[i] {
+			t.Errorf("got %v, want %s", event["event"], expected[i])
+		}
+		if i == 0 && event["tool"] != "make" {
+			t.Errorf("got tool %v, want make", event["tool"])
+		}
+		if i == 2 && (event["code"] != float64(3) || event["status"] != "failed") {
+			t.Errorf("unexpected processExited event %s", line)
+		}
+	}
+}
@@ -0,0 +1,520 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// invocation captures a resolved tool invocation
+type invocation struct {
+	tool       string
+	executable string
+	rootDir    string
+	buildFile  string
+	workDir    string
+	watchScan  bool
+	// runs a failed build once more with --scan
+	scanOnFailure bool
+	args          *ParsedArgs
+
+	// set by executeCommand when compiler messages are summarized or annotated
+	watchCompiler bool
+	// set by executeCommand when unknown tasks may be reported with suggestions
+	watchTasks bool
+	// environment of the build, extended with the active profile
+	env []string
+	// set once prepareInvocation has been applied
+	prepared bool
+}
+
+// LaunchError reports a tool that could not be started, such as a missing or non executable file
+type LaunchError struct {
+	Executable string
+	Err        error
+}
+
+func (e *LaunchError) Error() string {
+	return "could not launch " + e.Executable + ": " + e.Err.Error()
+}
+
+// BuildError reports a tool that was started but exited with a non zero exit code
+type BuildError struct {
+	Tool     string
+	ExitCode int
+}
+
+func (e *BuildError) Error() string {
+	return e.Tool + " exited with code " + strconv.Itoa(e.ExitCode)
+}
+
+// TimeoutError reports a tool that was killed as it did not finish in time
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return "timed out after " + e.Timeout.String()
+}
+
+const timeoutExitCode = 124
+
+// Resolves the build timeout given with -gt, or else set with general.timeout.
+// Returns 0 when there's no timeout
+func resolveTimeout(config *Config, args *ParsedArgs) (time.Duration, error) {
+	value := config.general.timeout
+	if args.HasGumFlag("gt") {
+		value = args.GumFlagValue("gt")
+	}
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, errors.New("Invalid timeout '" + value + "'. Use a duration such as 90s, 10m, or 1h30m")
+	}
+	return timeout, nil
+}
+
+// Resolves how many times a failed build is retried, given with -gR or else set with general.retries,
+// and the delay before the first retry, set with general.retryDelay and doubled on each subsequent retry
+func resolveRetries(config *Config, args *ParsedArgs) (int, time.Duration, error) {
+	retries := config.general.retries
+	if args.HasGumFlag("gR") {
+		value := args.GumFlagValue("gR")
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, errors.New("Invalid retries '" + value + "'. Use a number such as 2")
+		}
+		retries = n
+	}
+	if retries < 0 {
+		return 0, 0, errors.New("Invalid retries '" + strconv.Itoa(retries) + "'. Use a number such as 2")
+	}
+	if retries == 0 || len(config.general.retryDelay) == 0 {
+		return retries, 0, nil
+	}
+
+	delay, err := time.ParseDuration(config.general.retryDelay)
+	if err != nil || delay < 0 {
+		return 0, 0, errors.New("Invalid retry delay '" + config.general.retryDelay + "'. Use a duration such as 10s")
+	}
+	return retries, delay, nil
+}
+
+// Completes a configured invocation with the args and env of the active profile, and
+// the args matching the console mode. Applied only once, thus configure-only paths such
+// as export-script may prepare an invocation that is executed later on
+func prepareInvocation(context Context, config *Config, inv *invocation) error {
+	if inv.prepared {
+		return nil
+	}
+	if err := applyProfile(context, config, inv); err != nil {
+		return err
+	}
+	inv.args.Args = applyConsoleMode(inv.tool, resolveConsoleMode(context, config), inv.args.Args)
+	inv.prepared = true
+	return nil
+}
+
+// Executes the resolved tool with the given args
+func executeCommand(context Context, config *Config, inv invocation) error {
+	return executeCommandContext(gocontext.Background(), context, config, inv)
+}
+
+// Executes the resolved tool with the given args. The tool, along with its process group,
+// is killed once ctx is done, in which case the error of ctx is returned
+func executeCommandContext(ctx gocontext.Context, context Context, config *Config, inv invocation) error {
+	if err := prepareInvocation(context, config, &inv); err != nil {
+		fmt.Fprintln(gumOutput, err)
+		context.Exit(-1)
+		return err
+	}
+
+	emitEvent(inv.args, "toolResolved", map[string]interface{}{
+		"tool":       inv.tool,
+		"executable": inv.executable,
+		"rootDir":    inv.rootDir,
+		"buildFile":  inv.buildFile,
+		"args":       inv.args.Args})
+
+	if inv.args.HasGumFlag("gx") {
+		printDryRun(context, inv)
+		return nil
+	}
+
+	if isPrintOnly(context, config) {
+		fmt.Println(formatCommandLine(inv.executable, inv.args.Args))
+		return nil
+	}
+
+	if !repairGradleWrapperJar(context, config, inv) ||
+		!verifyChecksums(context, config, inv.executable) ||
+		!verifyGradleWrapperJar(context, config, inv.executable) ||
+		!verifyDistributionHosts(context, config, inv.executable) ||
+		!verifyTrust(context, config, inv.executable, inv.args) ||
+		!verifyMinimumVersion(context, config, inv) ||
+		!confirmTasks(context, config, inv) {
+		context.Exit(-1)
+		return errors.New("refusing to run " + inv.executable)
+	}
+	probeToolVersion(context, inv)
+
+	timeout, err := resolveTimeout(config, inv.args)
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
+		context.Exit(-1)
+		return err
+	}
+
+	retries, delay, err := resolveRetries(config, inv.args)
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
+		context.Exit(-1)
+		return err
+	}
+
+	debugEnv(context, config)
+
+	if err := runHooks(ctx, context, config, inv, config.hooks.before, hookEnv(inv, false, nil)); err != nil {
+		fmt.Fprintln(gumOutput, err)
+		context.Exit(-1)
+		return err
+	}
+
+	history := resolveHistoryFile(context)
+	entry := newHistoryEntry(context, inv)
+	if !config.general.quiet {
+		printEstimatedDuration(readHistory(history), entry)
+	}
+
+	annotate := isGitHubAnnotations(context, config)
+	teamCity := isTeamCity(context)
+	inv.watchCompiler = config.general.summarizeErrors || annotate || teamCity
+	inv.watchTasks = !config.general.quiet && (inv.tool == "gradle" || inv.tool == "maven")
+
+	entry.Time = time.Now()
+	var watchers *outputWatchers
+	var interrupted bool
+	for attempt := 1; ; attempt++ {
+		var cmd *exec.Cmd
+		var pty *ptyOutput
+		cmd, pty, watchers = newBuildCommand(ctx, config, inv)
+		emitEvent(inv.args, "processStarted", map[string]interface{}{"attempt": attempt, "dir": cmd.Dir})
+		if teamCity {
+			printTeamCityMessage("blockOpened", "name", teamCityBlockName(inv, attempt))
+		}
+		start := time.Now()
+		interrupted, err = runForwardingSignals(ctx, cmd, timeout, pty, lowPriorityHook(config, inv))
+		emitProcessExited(inv, attempt, time.Since(start), err)
+		if teamCity {
+			printTeamCityMessage("blockClosed", "name", teamCityBlockName(inv, attempt))
+		}
+
+		if err == nil {
+			if attempt > 1 && !config.general.quiet {
+				fmt.Fprintln(gumOutput, "Build succeeded on attempt "+strconv.Itoa(attempt)+" of "+strconv.Itoa(retries+1))
+			}
+			break
+		}
+		if _, ok := err.(*exec.ExitError); !ok || interrupted || attempt > retries || ctx.Err() != nil {
+			break
+		}
+
+		if !config.general.quiet {
+			message := "Build failed on attempt " + strconv.Itoa(attempt) + " of " + strconv.Itoa(retries+1) + ", retrying"
+			if delay > 0 {
+				message += " in " + delay.String()
+			}
+			fmt.Fprintln(gumOutput, message)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+		delay *= 2
+	}
+	if _, ok := err.(*exec.ExitError); ok && inv.scanOnFailure && !interrupted && ctx.Err() == nil {
+		watchers, err = rerunWithScan(ctx, config, inv, timeout)
+	}
+	entry.Duration = time.Since(entry.Time).Milliseconds()
+	entry.Success = err == nil
+	_, entry.ExitCode, _ = buildOutcome(err)
+
+	if err := writeHistoryEntry(history, entry); err != nil && config.general.debug {
+		fmt.Fprintln(gumOutput, err)
+	}
+
+	var tests *testResults
+	if len(inv.rootDir) > 0 && hasTestTasks(inv) && (!config.general.quiet || annotate) {
+		tests = collectTestResults(inv.rootDir, entry.Time)
+	}
+
+	if !config.general.quiet {
+		if config.general.summarizeErrors {
+			printCompilerSummary(config, watchers.compiler, inv.rootDir)
+		}
+		printTestSummary(config, tests)
+	}
+
+	if annotate {
+		printGitHubAnnotations(context, watchers.compiler, tests)
+	}
+
+	if len(watchers.scan.url) > 0 && !config.general.quiet {
+		fmt.Fprintln(gumOutput, "Build scan: "+watchers.scan.url)
+	}
+
+	duration := time.Since(entry.Time)
+	summary := formatBuildSummary(inv, duration, err)
+	if len(summary) > 0 && !config.general.quiet {
+		fmt.Fprintln(gumOutput, summary)
+	}
+	if teamCity {
+		printTeamCityProblems(inv, watchers.compiler, summary, err)
+	}
+	// after hooks run even if the build was cancelled, as they usually tear down what before hooks set up
+	if herr := runHooks(gocontext.Background(), context, config, inv, config.hooks.after, hookEnv(inv, true, err)); herr != nil && !config.general.quiet {
+		fmt.Fprintln(gumOutput, "WARNING: "+herr.Error())
+	}
+	notifyBuild(config, inv, duration, err)
+	exportSpan(context, config, inv, entry.Time, duration, err)
+
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if timeoutErr, ok := err.(*TimeoutError); ok {
+		fmt.Fprintln(gumOutput, "Build timed out after "+timeoutErr.Timeout.String())
+		return err
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		suggestTasks(context, inv, watchers.tasks)
+		return &BuildError{Tool: inv.tool, ExitCode: exitErr.ExitCode()}
+	}
+
+	fmt.Fprintln(gumOutput, "Could not launch "+inv.executable)
+	fmt.Fprintln(gumOutput, err)
+	return &LaunchError{Executable: inv.executable, Err: err}
+}
+
+// outputWatchers inspect the output of a build while it runs
+type outputWatchers struct {
+	scan     *scanWatcher
+	compiler *compilerWatcher
+	tasks    *taskWatcher
+}
+
+// Creates the command for a single run of the build, along with the watchers of its output.
+// The returned ptyOutput is nil unless a pseudo terminal was allocated
+func newBuildCommand(ctx gocontext.Context, config *Config, inv invocation) (*exec.Cmd, *ptyOutput, *outputWatchers) {
+	cmd := exec.CommandContext(ctx, inv.executable, inv.args.Args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if len(inv.env) > 0 {
+		cmd.Env = append(os.Environ(), inv.env...)
+	}
+	if isStdinPassthrough(config, inv.args) {
+		cmd.Stdin = os.Stdin
+	}
+
+	watchers := &outputWatchers{scan: &scanWatcher{}}
+	stdout := []io.Writer{os.Stdout}
+	stderr := []io.Writer{os.Stderr}
+	if inv.watchScan {
+		stdout = append(stdout, watchers.scan)
+	}
+	if inv.watchCompiler {
+		watchers.compiler = newCompilerWatcher()
+		stdout = append(stdout, watchers.compiler.stream())
+		stderr = append(stderr, watchers.compiler.stream())
+	}
+	if inv.watchTasks {
+		watchers.tasks = &taskWatcher{}
+		stdout = append(stdout, watchers.tasks)
+		stderr = append(stderr, watchers.tasks)
+	}
+
+	var pty *ptyOutput
+	if len(stdout) > 1 {
+		output := io.MultiWriter(stdout...)
+		cmd.Stdout = output
+		cmd.Stderr = io.MultiWriter(stderr...)
+		if usePty(inv.args) {
+			var err error
+			if pty, err = attachPty(cmd, output); err != nil && config.general.debug {
+				fmt.Fprintln(gumOutput, "Could not allocate a pseudo terminal, falling back to pipes")
+				fmt.Fprintln(gumOutput, err)
+			}
+		}
+	}
+	if len(inv.workDir) > 0 {
+		cmd.Dir = inv.workDir
+	}
+	if inv.args.HasGumFlag("gbg") {
+		prepareLowPriority(cmd)
+	}
+	return cmd, pty, watchers
+}
+
+// Resolves the func that lowers the priority of the started build when requested with -gbg, nil otherwise
+func lowPriorityHook(config *Config, inv invocation) func(*exec.Cmd) {
+	if !inv.args.HasGumFlag("gbg") {
+		return nil
+	}
+
+	return func(cmd *exec.Cmd) {
+		if err := lowerPriority(cmd); err != nil && !config.general.quiet {
+			fmt.Fprintln(gumOutput, "WARNING: could not lower process priority")
+			fmt.Fprintln(gumOutput, err)
+		}
+	}
+}
+
+// ExitCode resolves the exit code matching the outcome of executeCommand.
+// Build failures keep the exit code of the tool, timeouts result in 124
+// (same as coreutils' timeout), and any other error results in -1
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if _, ok := err.(*TimeoutError); ok {
+		return timeoutExitCode
+	}
+	if buildErr, ok := err.(*BuildError); ok && buildErr.ExitCode > 0 {
+		return buildErr.ExitCode
+	}
+	return -1
+}
+
+// Prints the executable, working dir, and args that would be executed
+func printDryRun(context Context, inv invocation) {
+	dir := inv.workDir
+	if len(dir) == 0 {
+		dir, _ = filepath.Abs(context.GetWorkingDir())
+	}
+
+	fmt.Println("executable = " + shellQuote(inv.executable))
+	fmt.Println("directory  = " + shellQuote(dir))
+	fmt.Println("command    = " + formatCommandLine(inv.executable, inv.args.Args))
+}
+
+// Resolves the outcome of a finished build as a status, such as succeeded or failed,
+// and its exit code. Returns false if the tool could not be launched
+func buildOutcome(err error) (string, int, bool) {
+	if err == nil {
+		return "succeeded", 0, true
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return "failed", exitErr.ExitCode(), true
+	}
+	if _, ok := err.(*TimeoutError); ok {
+		return "timed out", timeoutExitCode, true
+	}
+	if err == gocontext.Canceled || err == gocontext.DeadlineExceeded {
+		return "cancelled", -1, true
+	}
+	return "", -1, false
+}
+
+// Emits the processExited event of a single run of the build
+func emitProcessExited(inv invocation, attempt int, duration time.Duration, err error) {
+	status, code, ok := buildOutcome(err)
+	fields := map[string]interface{}{
+		"attempt":    attempt,
+		"code":       code,
+		"durationMs": duration.Milliseconds()}
+	if ok {
+		fields["status"] = status
+	} else {
+		fields["status"] = "not launched"
+		fields["error"] = err.Error()
+	}
+	emitEvent(inv.args, "processExited", fields)
+}
+
+// Formats a one line summary of a finished build, such as
+// Build failed in 2m 13s (gradle, /project/build.gradle, exit code 1).
+// Returns an empty string if the tool could not be launched
+func formatBuildSummary(inv invocation, duration time.Duration, err error) string {
+	status, code, ok := buildOutcome(err)
+	if !ok {
+		return ""
+	}
+
+	details := []string{inv.tool}
+	if len(inv.buildFile) > 0 {
+		details = append(details, inv.buildFile)
+	}
+	details = append(details, "exit code "+strconv.Itoa(code))
+	return "Build " + status + " in " + formatDuration(duration) + " (" + strings.Join(details, ", ") + ")"
+}
+
+// Checks if stdin should be passed to the tool, required by tasks that read
+// from the terminal such as "gradle init" or Quarkus dev mode
+func isStdinPassthrough(config *Config, args *ParsedArgs) bool {
+	return config.general.interactive || args.HasGumFlag("gi")
+}
+
+// Checks if commands should be printed instead of executed,
+// either by policy or by setting $GUM_PRINT_ONLY
+func isPrintOnly(context Context, config *Config) bool {
+	return config.policy.printOnly || isTruthy(context.Getenv("GUM_PRINT_ONLY"))
+}
+
+func isTruthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// Formats a command line so that it may be pasted into a shell
+func formatCommandLine(executable string, args []string) string {
+	line := make([]string, 0)
+	line = append(line, shellQuote(executable))
+	for _, arg := range args {
+		line = append(line, shellQuote(arg))
+	}
+	return strings.Join(line, " ")
+}
+
+func shellQuote(s string) string {
+	if len(s) == 0 {
+		return "''"
+	}
+	if strings.IndexAny(s, " \t\n\"'\\$`&|;<>()*?![]{}#~") == -1 {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", "'\"'\"'", -1) + "'"
+}
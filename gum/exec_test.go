@@ -0,0 +1,375 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFormatCommandLine(t *testing.T) {
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Plain", formatCommandLine("gradlew", []string{"build", "-S"}), "gradlew build -S"},
+		{"Spaces", formatCommandLine("/opt/my tools/mvn", []string{"-Dmsg=hello world"}), "'/opt/my tools/mvn' '-Dmsg=hello world'"},
+		{"Quotes", formatCommandLine("ant", []string{"it's"}), "ant 'it'\"'\"'s'"},
+		{"Empty", formatCommandLine("jbang", []string{""}), "jbang ''"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestExecuteCommandErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	failing := filepath.Join(dir, "failing")
+	broken := filepath.Join(dir, "broken")
+	ioutil.WriteFile(failing, []byte("#!/bin/sh\nexit 3\n"), 0755)
+	ioutil.WriteFile(broken, []byte(""), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: dir,
+		homeDir:    dir}
+	config := newConfig()
+	config.setQuiet(true)
+
+	var checks = []struct {
+		title, executable string
+		expected          int
+		launched          bool
+	}{
+		{"BuildFailure", failing, 3, true},
+		{"LaunchFailure", broken, -1, false},
+	}
+
+	for _, check := range checks {
+		args := ParseArgs([]string{"build"})
+
+		// when:
+		err := executeCommand(context, config, invocation{tool: "make", executable: check.executable, args: &args})
+
+		// then:
+		_, isBuildErr := err.(*BuildError)
+		_, isLaunchErr := err.(*LaunchError)
+		if isBuildErr != check.launched || isLaunchErr == check.launched {
+			t.Errorf("%s: unexpected error %v", check.title, err)
+		}
+		if ExitCode(err) != check.expected {
+			t.Errorf("%s: got %d, want %d", check.title, ExitCode(err), check.expected)
+		}
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	var checks = []struct {
+		title    string
+		err      error
+		expected int
+	}{
+		{"Success", nil, 0},
+		{"Build", &BuildError{Tool: "gradle", ExitCode: 1}, 1},
+		{"Signaled", &BuildError{Tool: "gradle", ExitCode: -1}, -1},
+		{"Launch", &LaunchError{Executable: "gradlew", Err: errors.New("permission denied")}, -1},
+	}
+
+	for _, check := range checks {
+		if actual := ExitCode(check.err); actual != check.expected {
+			t.Errorf("%s: got %d, want %d", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestParseDryRunFlags(t *testing.T) {
+	var checks = []struct {
+		title string
+		input []string
+	}{
+		{"Short", []string{"-gx", "build"}},
+		{"Long", []string{"--gum-dry-run", "build"}},
+	}
+
+	for _, check := range checks {
+		// when:
+		args := ParseArgs(check.input)
+
+		// then:
+		if !args.HasGumFlag("gx") || len(args.Tool) != 0 || len(args.Args) != 1 {
+			t.Errorf("%s: unexpected args %v", check.title, args)
+		}
+	}
+}
+
+func TestExecuteCommandDryRun(t *testing.T) {
+	// given:
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: "."}
+	args := ParseArgs([]string{"-gx", "build"})
+
+	// when:
+	err := executeCommand(context, newConfig(), invocation{tool: "make", executable: "does-not-exist", args: &args})
+
+	// then:
+	if err != nil {
+		t.Errorf("Expected nothing to be executed, got %v", err)
+	}
+}
+
+func TestResolveTimeout(t *testing.T) {
+	var checks = []struct {
+		title, config string
+		input         []string
+		expected      string
+		valid         bool
+	}{
+		{"None", "", []string{"build"}, "0s", true},
+		{"Config", "10m", []string{"build"}, "10m0s", true},
+		{"Flag", "10m", []string{"-gt", "90s", "build"}, "1m30s", true},
+		{"FlagWithEquals", "", []string{"-gt=1h", "build"}, "1h0m0s", true},
+		{"Invalid", "", []string{"-gt", "soon", "build"}, "0s", false},
+	}
+
+	for _, check := range checks {
+		// given:
+		config := newConfig()
+		config.general.timeout = check.config
+		args := ParseArgs(check.input)
+
+		// when:
+		timeout, err := resolveTimeout(config, &args)
+
+		// then:
+		if timeout.String() != check.expected || (err == nil) != check.valid {
+			t.Errorf("%s: got %s (%v), want %s", check.title, timeout, err, check.expected)
+		}
+		if len(args.Tool) != 0 || len(args.Args) != 1 || args.Args[0] != "build" {
+			t.Errorf("%s: unexpected args %v", check.title, args)
+		}
+	}
+}
+
+func TestResolveRetries(t *testing.T) {
+	var checks = []struct {
+		title, delay string
+		retries      int
+		input        []string
+		expected     int
+		expectedWait string
+		valid        bool
+	}{
+		{"None", "", 0, []string{"build"}, 0, "0s", true},
+		{"Config", "5s", 2, []string{"build"}, 2, "5s", true},
+		{"Flag", "", 2, []string{"-gR", "3", "build"}, 3, "0s", true},
+		{"FlagWithEquals", "1m", 0, []string{"-gR=1", "build"}, 1, "1m0s", true},
+		{"InvalidFlag", "", 0, []string{"-gR", "often", "build"}, 0, "0s", false},
+		{"InvalidDelay", "later", 1, []string{"build"}, 0, "0s", false},
+	}
+
+	for _, check := range checks {
+		// given:
+		config := newConfig()
+		config.general.retries = check.retries
+		config.general.retryDelay = check.delay
+		args := ParseArgs(check.input)
+
+		// when:
+		retries, delay, err := resolveRetries(config, &args)
+
+		// then:
+		if retries != check.expected || delay.String() != check.expectedWait || (err == nil) != check.valid {
+			t.Errorf("%s: got %d after %s (%v), want %d after %s", check.title, retries, delay, err, check.expected, check.expectedWait)
+		}
+		if len(args.Tool) != 0 || len(args.Args) != 1 || args.Args[0] != "build" {
+			t.Errorf("%s: unexpected args %v", check.title, args)
+		}
+	}
+}
+
+func TestExecuteCommandRetries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	flaky := filepath.Join(dir, "flaky")
+	ioutil.WriteFile(flaky, []byte("#!/bin/sh\necho run >> \"$0.runs\"\n[ $(wc -l < \"$0.runs\") -ge 3 ]\n"), 0755)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: dir,
+		homeDir:    dir}
+	config := newConfig()
+	config.setQuiet(true)
+
+	var checks = []struct {
+		title    string
+		retries  string
+		expected int
+	}{
+		{"GivesUp", "1", 1},
+		{"Succeeds", "2", 0},
+	}
+
+	for _, check := range checks {
+		os.Remove(flaky + ".runs")
+		args := ParseArgs([]string{"-gR", check.retries, "build"})
+
+		// when:
+		err := executeCommand(context, config, invocation{tool: "make", executable: flaky, args: &args})
+
+		// then:
+		if ExitCode(err) != check.expected {
+			t.Errorf("%s: got %d, want %d", check.title, ExitCode(err), check.expected)
+		}
+	}
+}
+
+func TestExecuteCommandScanOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	failing := filepath.Join(dir, "failing")
+	ioutil.WriteFile(failing, []byte("#!/bin/sh\n[ \"$1\" = --version ] && echo \"Gradle 8.7\" && exit 0\necho \"$@\" >> \"$0.runs\"\nexit 1\n"), 0755)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: dir,
+		homeDir:    dir}
+	config := newConfig()
+	config.setQuiet(true)
+
+	var checks = []struct {
+		title     string
+		onFailure bool
+		expected  string
+	}{
+		{"Disabled", false, "build\n"},
+		{"OnFailure", true, "build\nbuild --scan\n"},
+	}
+
+	for _, check := range checks {
+		os.Remove(failing + ".runs")
+		args := ParseArgs([]string{"build"})
+
+		// when:
+		err := executeCommand(context, config, invocation{tool: "gradle", executable: failing, scanOnFailure: check.onFailure, args: &args})
+
+		// then:
+		if ExitCode(err) != 1 {
+			t.Errorf("%s: got %d, want 1", check.title, ExitCode(err))
+		}
+		runs, _ := ioutil.ReadFile(failing + ".runs")
+		if string(runs) != check.expected {
+			t.Errorf("%s: got runs %q, want %q", check.title, string(runs), check.expected)
+		}
+	}
+}
+
+func TestExecuteCommandContextCancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	slow := filepath.Join(dir, "slow")
+	ioutil.WriteFile(slow, []byte("#!/bin/sh\nsleep 30 &\nwait\n"), 0755)
+
+	gumContext := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: dir,
+		homeDir:    dir}
+	config := newConfig()
+	config.setQuiet(true)
+	args := ParseArgs([]string{"-gR", "2", "build"})
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// when:
+	start := time.Now()
+	err := executeCommandContext(ctx, gumContext, config, invocation{tool: "make", executable: slow, args: &args})
+
+	// then:
+	if err != context.DeadlineExceeded {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("build was not killed, took %s", elapsed)
+	}
+}
+
+func TestFormatBuildSummary(t *testing.T) {
+	var checks = []struct {
+		title     string
+		buildFile string
+		err       error
+		expected  string
+	}{
+		{"Success", "/p/build.gradle", nil, "Build succeeded in 2m 13s (gradle, /p/build.gradle, exit code 0)"},
+		{"NoBuildFile", "", nil, "Build succeeded in 2m 13s (gradle, exit code 0)"},
+		{"Timeout", "/p/build.gradle", &TimeoutError{Timeout: time.Minute}, "Build timed out in 2m 13s (gradle, /p/build.gradle, exit code 124)"},
+		{"Cancelled", "/p/build.gradle", context.Canceled, "Build cancelled in 2m 13s (gradle, /p/build.gradle, exit code -1)"},
+		{"LaunchFailure", "/p/build.gradle", errors.New("permission denied"), ""},
+	}
+
+	for _, check := range checks {
+		// given:
+		inv := invocation{tool: "gradle", buildFile: check.buildFile}
+
+		// when:
+		actual := formatBuildSummary(inv, 133*time.Second, check.err)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %q, want %q", check.title, actual, check.expected)
+		}
+	}
+}
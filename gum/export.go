@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Handles "gm gum export-script [--shell bash|powershell] [--output <file>] <args>".
+// Writes a standalone script that reproduces the invocation of the given args
+func runExportScriptCommand(context Context, args *ParsedArgs, params []string) int {
+	shellSet, shell, params := findFlagValue("--shell", params)
+	outputSet, output, params := findFlagValue("--output", params)
+
+	if !shellSet {
+		shell = "bash"
+		if context.IsWindows() {
+			shell = "powershell"
+		}
+	}
+	if shell != "bash" && shell != "powershell" {
+		fmt.Fprintln(gumOutput, "Unsupported shell: "+shell)
+		fmt.Fprintln(gumOutput, "Usage: gm gum export-script [--shell bash|powershell] [--output <file>] <args>")
+		return -1
+	}
+
+	tool := resolveRunTool(context, args)
+	if len(tool) == 0 {
+		fmt.Fprintln(gumOutput, noProjectMessage())
+		return -1
+	}
+
+	targs := ParseArgs(params)
+	for flag := range args.Gum {
+		targs.Gum[flag] = struct{}{}
+	}
+	// keeps banners out of the script
+	targs.Gum["gq"] = struct{}{}
+
+	_, inv, err := configureTool(context, tool, &targs)
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
+		return -1
+	}
+
+	dir, _ := filepath.Abs(context.GetWorkingDir())
+	if len(inv.workDir) > 0 {
+		dir = inv.workDir
+	}
+	env := make([]envVar, 0)
+	for _, key := range snapshotEnvKeys {
+		if value := context.Getenv(key); len(value) > 0 && key != "PATH" {
+			env = append(env, envVar{key: key, value: value})
+		}
+	}
+	for _, v := range inv.env {
+		if i := strings.Index(v, "="); i > 0 {
+			env = append(env, envVar{key: v[:i], value: v[i+1:]})
+		}
+	}
+
+	var script string
+	if shell == "powershell" {
+		script = formatPowerShellScript(dir, env, inv.executable, inv.args.Args)
+	} else {
+		script = formatBashScript(dir, env, inv.executable, inv.args.Args)
+	}
+
+	if !outputSet {
+		fmt.Print(script)
+		return 0
+	}
+
+	if err := ioutil.WriteFile(output, []byte(script), 0755); err != nil {
+		fmt.Fprintln(gumOutput, err)
+		return -1
+	}
+	fmt.Fprintln(gumOutput, "Script written to "+output)
+	return 0
+}
+
+func formatBashScript(dir string, env []envVar, executable string, args []string) string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\n")
+	b.WriteString("# Generated by gm\n")
+	b.WriteString("set -e\n")
+	for _, v := range env {
+		b.WriteString("export " + v.key + "=" + shellQuote(v.value) + "\n")
+	}
+	b.WriteString("cd " + shellQuote(dir) + "\n")
+	b.WriteString("exec " + formatCommandLine(executable, args) + "\n")
+	return b.String()
+}
+
+func formatPowerShellScript(dir string, env []envVar, executable string, args []string) string {
+	var b strings.Builder
+	b.WriteString("# Generated by gm\n")
+	b.WriteString("$ErrorActionPreference = 'Stop'\n")
+	for _, v := range env {
+		b.WriteString("$env:" + v.key + " = " + powerShellQuote(v.value) + "\n")
+	}
+	b.WriteString("Set-Location -LiteralPath " + powerShellQuote(dir) + "\n")
+
+	line := []string{"&", powerShellQuote(executable)}
+	for _, arg := range args {
+		line = append(line, powerShellQuote(arg))
+	}
+	b.WriteString(strings.Join(line, " ") + "\n")
+	b.WriteString("exit $LASTEXITCODE\n")
+	return b.String()
+}
+
+func powerShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
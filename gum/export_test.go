@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatScripts(t *testing.T) {
+	// given:
+	env := []envVar{{"JAVA_HOME", "/opt/java 11"}}
+	args := []string{"build", "-Dmsg=it's"}
+
+	// when:
+	bash := formatBashScript("/work/app", env, "/work/app/gradlew", args)
+	powershell := formatPowerShellScript("C:\\work\\app", env, "C:\\work\\app\\gradlew.bat", args)
+
+	// then:
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Bash", bash, "#!/usr/bin/env bash\n" +
+			"# Generated by gm\n" +
+			"set -e\n" +
+			"export JAVA_HOME='/opt/java 11'\n" +
+			"cd /work/app\n" +
+			"exec /work/app/gradlew build '-Dmsg=it'\"'\"'s'\n"},
+		{"PowerShell", powershell, "# Generated by gm\n" +
+			"$ErrorActionPreference = 'Stop'\n" +
+			"$env:JAVA_HOME = '/opt/java 11'\n" +
+			"Set-Location -LiteralPath 'C:\\work\\app'\n" +
+			"& 'C:\\work\\app\\gradlew.bat' 'build' '-Dmsg=it''s'\n" +
+			"exit $LASTEXITCODE\n"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestExportScriptWithProjectSettings(t *testing.T) {
+	var checks = []struct {
+		title, profile, config, expected string
+	}{
+		{"Profile", "ci", "[profiles.ci.gradle]\nargs = [\"--no-daemon\"]\n[profiles.ci.env]\nGRADLE_OPTS = \"-Xmx2g\"\n",
+			"export GRADLE_OPTS=-Xmx2g\n"},
+		{"Profile args", "ci", "[profiles.ci.gradle]\nargs = [\"--no-daemon\"]\n", "gradlew --no-daemon "},
+		{"Console", "", "[general]\nconsole = \"plain\"\n", " --console=plain "},
+	}
+
+	for _, check := range checks {
+		// given:
+		dir, _ := ioutil.TempDir("", "gum")
+		defer os.RemoveAll(dir)
+		dir, _ = filepath.EvalSymlinks(dir)
+		project := filepath.Join(dir, "project")
+		os.MkdirAll(filepath.Join(dir, "home"), 0755)
+		os.MkdirAll(project, 0755)
+		ioutil.WriteFile(filepath.Join(project, "gradlew"), []byte("#!/bin/sh\n"), 0755)
+		ioutil.WriteFile(filepath.Join(project, "build.gradle"), []byte(""), 0644)
+		ioutil.WriteFile(filepath.Join(project, "settings.gradle"), []byte(""), 0644)
+		ioutil.WriteFile(filepath.Join(project, ".gm.toml"), []byte(check.config), 0644)
+		output := filepath.Join(dir, "build.sh")
+
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: project,
+			homeDir:    filepath.Join(dir, "home"),
+			env:        map[string]string{"GUM_PROFILE": check.profile}}
+		args := ParseArgs([]string{"-gg", "gum", "export-script"})
+
+		// when:
+		code := runExportScriptCommand(context, &args, []string{"--shell", "bash", "--output", output, "build"})
+
+		// then:
+		data, _ := ioutil.ReadFile(output)
+		if code != 0 || !strings.Contains(string(data), check.expected) {
+			t.Errorf("%s: expected %q in %s", check.title, check.expected, string(data))
+		}
+	}
+}
+
+func TestExportScriptWithMavenProjectBaseDir(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "reactor"))
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "build.sh")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: filepath.Join(root, "core"),
+		homeDir:    dir,
+		paths:      []string{bin},
+		env:        map[string]string{}}
+	args := ParseArgs([]string{"-gm", "gum", "export-script"})
+
+	// when:
+	code := runExportScriptCommand(context, &args, []string{"--shell", "bash", "--output", output, "verify"})
+
+	// then:
+	data, _ := ioutil.ReadFile(output)
+	expected := "-Dmaven.multiModuleProjectDirectory=" + root
+	if code != 0 || !strings.Contains(string(data), expected) {
+		t.Errorf("expected %q in %s", expected, string(data))
+	}
+}
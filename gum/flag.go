@@ -17,14 +17,19 @@
 package gum
 
 import (
+	"errors"
+	"regexp"
+	"sort"
 	"strings"
 )
 
 // ParsedArgs captures input args separated by responsibility
 type ParsedArgs struct {
-	Gum  map[string]struct{}
-	Tool []string
-	Args []string
+	Gum    map[string]struct{}
+	Values map[string]string
+	Tool   []string
+	Args   []string
+	Errors []string
 }
 
 // HasGumFlag finds if a given Gum flag is specified in the parsed args
@@ -33,14 +38,62 @@ func (a *ParsedArgs) HasGumFlag(flag string) bool {
 	return ok
 }
 
-var gumFlags = []string{"ga", "gb", "gc", "gd", "gg", "gh", "gj", "gm", "gn", "gq", "gr", "gv"}
+// GumFlagValue returns the value of a given Gum flag, or an empty string if it's not specified
+func (a *ParsedArgs) GumFlagValue(flag string) string {
+	return a.Values[flag]
+}
+
+// Copies the parsed args. Flags are shared as they are not modified once parsed, while tool
+// args are copied as tools strip the flags they consume, such as -f
+func (a *ParsedArgs) clone() *ParsedArgs {
+	return &ParsedArgs{
+		Gum:    a.Gum,
+		Values: a.Values,
+		Tool:   append([]string{}, a.Tool...),
+		Args:   append([]string{}, a.Args...),
+		Errors: a.Errors}
+}
+
+var gumFlags = []string{"-gum-composite", "-gum-events", "-gum-tool", "-no-pty", "-no-wizard", "gR", "ga", "gb", "gbg", "gbk", "gbld", "gbt", "gc", "gcl", "gcm", "gconfirm", "gd", "gg", "ggr", "gh", "gi", "gj", "gl", "gm", "gmd", "gmk", "gn", "gnode", "gnoscan", "gp", "gq", "gr", "gs", "gscan", "gshow", "gt", "gv", "gx", "gy", "gz"}
+
+// valuedGumFlags lists Gum flags that take a value, such as -gt 10m or -gt=10m
+var valuedGumFlags = []string{"-gum-events", "-gum-tool", "gR", "gp", "gt"}
+
+// longGumFlags maps long Gum flags to their short form
+var longGumFlags = map[string]string{
+	"-gum-background":  "gbg",
+	"-gum-confirm":     "gconfirm",
+	"-gum-debug":       "gd",
+	"-gum-dry-run":     "gx",
+	"-gum-help":        "gh",
+	"-gum-interactive": "gi",
+	"-gum-mvnd":        "gmd",
+	"-gum-nearest":     "gn",
+	"-gum-no-replace":  "gr",
+	"-gum-no-scan":     "gnoscan",
+	"-gum-profile":     "gp",
+	"-gum-quiet":       "gq",
+	"-gum-retries":     "gR",
+	"-gum-scan":        "gscan",
+	"-gum-show":        "gshow",
+	"-gum-show-config": "gc",
+	"-gum-timeout":     "gt",
+	"-gum-version":     "gv",
+	"-gum-yes":         "gy"}
+
+// envGumFlags lists long Gum flags that may also be set with an environment variable,
+// such as GUM_NEAREST=true for --gum-nearest or GUM_TIMEOUT=30m for --gum-timeout 30m
+var envGumFlags = []string{"-gum-debug", "-gum-interactive", "-gum-mvnd", "-gum-nearest", "-gum-no-replace",
+	"-gum-no-scan", "-gum-quiet", "-gum-retries", "-gum-timeout", "-gum-yes"}
 
 // ParseArgs parses input args and separates them between Gum, Tool, and Args
 func ParseArgs(args []string) ParsedArgs {
 	flags := ParsedArgs{
-		Gum:  make(map[string]struct{}, 0),
-		Tool: make([]string, 0),
-		Args: make([]string, 0)}
+		Gum:    make(map[string]struct{}, 0),
+		Values: make(map[string]string, 0),
+		Tool:   make([]string, 0),
+		Args:   make([]string, 0),
+		Errors: make([]string, 0)}
 
 	if len(args) == 0 {
 		return flags
@@ -56,8 +109,24 @@ func ParseArgs(args []string) ParsedArgs {
 
 		switch mode {
 		case 0:
-			if s[0] == '-' && isGumFlag(s) {
-				flags.Gum[s[1:]] = struct{}{}
+			if s[0] == '-' && isValuedGumFlag(s) {
+				parts := strings.SplitN(s, "=", 2)
+				flag := resolveGumFlag(parts[0])
+				flags.Gum[flag] = struct{}{}
+				if len(parts) == 2 {
+					flags.Values[flag] = parts[1]
+				} else if i+1 < len(args) {
+					flags.Values[flag] = strings.TrimSpace(args[i+1])
+					i = i + 1
+				}
+			} else if s[0] == '-' && isGumFlag(s) {
+				flags.Gum[resolveGumFlag(s)] = struct{}{}
+			} else if bundle, err := expandGumFlagBundle(s); err != nil {
+				flags.Errors = append(flags.Errors, err.Error())
+			} else if len(bundle) > 0 {
+				for _, flag := range bundle {
+					flags.Gum[flag] = struct{}{}
+				}
 			} else {
 				mode = 1
 				i = i - 1
@@ -89,7 +158,58 @@ func ParseArgs(args []string) ParsedArgs {
 	return flags
 }
 
+// Expands a bundle of single letter Gum flags, such as -gnd into gn and gd. Returns no flags if s
+// is not a bundle, that is, its first letter is not a Gum flag. Valued flags cannot be bundled
+func expandGumFlagBundle(s string) ([]string, error) {
+	if len(s) < 4 || !strings.HasPrefix(s, "-g") || !isBundledGumFlag("g"+s[2:3]) {
+		return nil, nil
+	}
+
+	bundle := make([]string, 0)
+	for _, letter := range s[2:] {
+		flag := "g" + string(letter)
+		if isValuedGumFlag("-" + flag) {
+			return nil, errors.New("-" + flag + " takes a value and cannot be bundled in " + s)
+		}
+		if !isBundledGumFlag(flag) {
+			return nil, errors.New("Unknown flag -" + flag + " in " + s)
+		}
+		bundle = append(bundle, flag)
+	}
+	return bundle, nil
+}
+
+// Checks if the given flag, without its leading dash, is a single letter Gum flag that takes no value
+func isBundledGumFlag(flag string) bool {
+	return len(flag) == 2 && isGumFlag("-"+flag) && !isValuedGumFlag("-"+flag)
+}
+
+// ApplyEnvFlags sets Gum flags from their environment variables, unless given explicitly
+func ApplyEnvFlags(context Context, args *ParsedArgs) {
+	for _, long := range envGumFlags {
+		flag := longGumFlags[long]
+		value := strings.TrimSpace(context.Getenv(resolveFlagEnv(long)))
+		if len(value) == 0 || args.HasGumFlag(flag) {
+			continue
+		}
+		if isValuedGumFlag("-" + flag) {
+			args.Gum[flag] = struct{}{}
+			args.Values[flag] = value
+		} else if isTruthy(value) {
+			args.Gum[flag] = struct{}{}
+		}
+	}
+}
+
+// Resolves the environment variable of a long Gum flag, i.e, GUM_NO_REPLACE for --gum-no-replace
+func resolveFlagEnv(long string) string {
+	return "GUM_" + strings.ToUpper(strings.ReplaceAll(strings.TrimPrefix(long, "-gum-"), "-", "_"))
+}
+
 func isGumFlag(flag string) bool {
+	if _, ok := longGumFlags[flag[1:]]; ok {
+		return true
+	}
 	for _, f := range gumFlags {
 		if flag[1:] == f {
 			return true
@@ -98,6 +218,24 @@ func isGumFlag(flag string) bool {
 	return false
 }
 
+func isValuedGumFlag(flag string) bool {
+	name := resolveGumFlag(strings.SplitN(flag, "=", 2)[0])
+	for _, f := range valuedGumFlags {
+		if name == f {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolves the short form of the given Gum flag, without its leading dash
+func resolveGumFlag(flag string) string {
+	if short, ok := longGumFlags[flag[1:]]; ok {
+		return short
+	}
+	return flag[1:]
+}
+
 func findFlagValue(flag string, args []string) (bool, string, []string) {
 	if len(args) == 0 {
 		return false, "", args
@@ -139,7 +277,7 @@ func replaceArgs(args []string, replacements map[string]string, allowsSubMatch b
 	nargs := make([]string, 0)
 
 	for _, key := range args {
-		exactMatch := replacements[key]
+		exactMatch := resolveReplacement(key, replacements)
 
 		subMatch := ""
 
@@ -148,7 +286,7 @@ func replaceArgs(args []string, replacements map[string]string, allowsSubMatch b
 			if semicolon > -1 {
 				prefix := key[0:(semicolon + 1)]
 				suffix := key[(semicolon + 1):]
-				match := replacements[suffix]
+				match := resolveReplacement(suffix, replacements)
 
 				if len(match) > 0 {
 					subMatch = prefix + match
@@ -167,3 +305,70 @@ func replaceArgs(args []string, replacements map[string]string, allowsSubMatch b
 
 	return nargs
 }
+
+// Resolves the replacement for key, either by exact match or by the first pattern that
+// matches, in lexicographical order. Keys starting with ^ are regular expressions whose
+// replacement may refer to capture groups, i.e. "^it(.*)$" = "integrationTest$1"
+func resolveReplacement(key string, replacements map[string]string) string {
+	if match := replacements[key]; len(match) > 0 {
+		return match
+	}
+
+	patterns := make([]string, 0)
+	for k := range replacements {
+		if strings.HasPrefix(k, "^") {
+			patterns = append(patterns, k)
+		}
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(key) {
+			return re.ReplaceAllString(key, replacements[pattern])
+		}
+	}
+
+	return ""
+}
+
+// Splits a command line into words. Single and double quotes group words
+func splitCommandLine(s string) []string {
+	words := make([]string, 0)
+
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words
+}
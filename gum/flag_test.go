@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReplaceArgsWithPatterns(t *testing.T) {
+	// given:
+	replacements := map[string]string{
+		"build":        "verify",
+		"^it(.*)$":     "integrationTest$1",
+		"^(.*)Docs$":   "javadoc",
+		"^integ(.*)$":  "never",
+		"^broken(.*$":  "never",
+		"^itExact$":    "ignored",
+		"itExactMatch": "exact"}
+
+	var checks = []struct {
+		title          string
+		allowsSubMatch bool
+		args           []string
+		expected       string
+	}{
+		{"Exact", false, []string{"build", "clean"}, "verify clean"},
+		{"Pattern", false, []string{"itFast"}, "integrationTestFast"},
+		{"ExactBeforePattern", false, []string{"itExactMatch"}, "exact"},
+		{"FirstPattern", false, []string{"apiDocs"}, "javadoc"},
+		{"SubMatch", true, []string{":core:itSlow"}, ":core:integrationTestSlow"},
+		{"NoSubMatch", false, []string{":core:itSlow"}, ":core:itSlow"},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := strings.Join(replaceArgs(check.args, replacements, check.allowsSubMatch), " ")
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestParseLongGumFlags(t *testing.T) {
+	var checks = []struct {
+		title string
+		input []string
+		flag  string
+		value string
+	}{
+		{"Nearest", []string{"--gum-nearest", "build"}, "gn", ""},
+		{"Debug", []string{"--gum-debug", "build"}, "gd", ""},
+		{"NoReplace", []string{"--gum-no-replace", "build"}, "gr", ""},
+		{"Timeout", []string{"--gum-timeout", "30m", "build"}, "gt", "30m"},
+		{"TimeoutEquals", []string{"--gum-timeout=30m", "build"}, "gt", "30m"},
+		{"Profile", []string{"--gum-profile", "ci", "build"}, "gp", "ci"},
+	}
+
+	for _, check := range checks {
+		// when:
+		args := ParseArgs(check.input)
+
+		// then:
+		if !args.HasGumFlag(check.flag) || len(args.Tool) != 0 || len(args.Args) != 1 {
+			t.Errorf("%s: unexpected args %v", check.title, args)
+		}
+		if args.GumFlagValue(check.flag) != check.value {
+			t.Errorf("%s: got %s, want %s", check.title, args.GumFlagValue(check.flag), check.value)
+		}
+	}
+}
+
+func TestApplyEnvFlags(t *testing.T) {
+	var checks = []struct {
+		title string
+		input []string
+		env   map[string]string
+		flag  string
+		set   bool
+		value string
+	}{
+		{"Nearest", []string{"build"}, map[string]string{"GUM_NEAREST": "true"}, "gn", true, ""},
+		{"NoReplace", []string{"build"}, map[string]string{"GUM_NO_REPLACE": "1"}, "gr", true, ""},
+		{"Falsy", []string{"build"}, map[string]string{"GUM_DEBUG": "false"}, "gd", false, ""},
+		{"Timeout", []string{"build"}, map[string]string{"GUM_TIMEOUT": "10m"}, "gt", true, "10m"},
+		{"FlagWins", []string{"-gt", "5m", "build"}, map[string]string{"GUM_TIMEOUT": "10m"}, "gt", true, "5m"},
+		{"Unset", []string{"build"}, map[string]string{}, "gq", false, ""},
+	}
+
+	for _, check := range checks {
+		// given:
+		args := ParseArgs(check.input)
+		context := testContext{env: check.env}
+
+		// when:
+		ApplyEnvFlags(context, &args)
+
+		// then:
+		if args.HasGumFlag(check.flag) != check.set {
+			t.Errorf("%s: got %t, want %t", check.title, args.HasGumFlag(check.flag), check.set)
+		}
+		if args.GumFlagValue(check.flag) != check.value {
+			t.Errorf("%s: got %s, want %s", check.title, args.GumFlagValue(check.flag), check.value)
+		}
+	}
+}
+
+func TestParseBundledGumFlags(t *testing.T) {
+	var checks = []struct {
+		title  string
+		input  []string
+		flags  []string
+		errors int
+		tool   int
+	}{
+		{"Bundle", []string{"-gnd", "build"}, []string{"gn", "gd"}, 0, 0},
+		{"Triple", []string{"-gqnr", "build"}, []string{"gq", "gn", "gr"}, 0, 0},
+		{"ExactFlagWins", []string{"-gmd", "build"}, []string{"gmd"}, 0, 0},
+		{"UnknownLetter", []string{"-gnk", "build"}, []string{}, 1, 0},
+		{"ValuedLetter", []string{"-gnt", "build"}, []string{}, 1, 0},
+		{"NotABundle", []string{"-gfoo=bar", "build"}, []string{}, 0, 1},
+	}
+
+	for _, check := range checks {
+		// when:
+		args := ParseArgs(check.input)
+
+		// then:
+		for _, flag := range check.flags {
+			if !args.HasGumFlag(flag) {
+				t.Errorf("%s: missing flag %s in %v", check.title, flag, args)
+			}
+		}
+		if len(args.Gum) != len(check.flags) {
+			t.Errorf("%s: got %d flags, want %d", check.title, len(args.Gum), len(check.flags))
+		}
+		if len(args.Errors) != check.errors {
+			t.Errorf("%s: got errors %v, want %d", check.title, args.Errors, check.errors)
+		}
+		if len(args.Tool) != check.tool {
+			t.Errorf("%s: got tool args %v, want %d", check.title, args.Tool, check.tool)
+		}
+	}
+}
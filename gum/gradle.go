@@ -17,11 +17,12 @@
 package gum
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -32,25 +33,43 @@ type GradleCommand struct {
 	executable           string
 	args                 *ParsedArgs
 	explicitProjectDir   string
+	includedBuild        string
+	compositeDir         string
 	rootDir              string
 	buildFile            string
 	explicitBuildFile    string
 	rootBuildFile        string
 	settingsFile         string
 	explicitSettingsFile string
+	version              string
+	distribution         string
+	develocity           bool
 }
 
 // Execute executes the given command
-func (c GradleCommand) Execute() {
+func (c GradleCommand) Execute() int {
 	c.doConfigureGradle()
-	c.doExecuteGradle()
+	return ExitCode(c.doExecuteGradle())
+}
+
+// ExecuteContext executes the given command, killing the build along with its process group
+// once ctx is done. Use ExitCode to resolve the exit code matching the returned error
+func (c GradleCommand) ExecuteContext(ctx context.Context) error {
+	c.doConfigureGradle()
+	return executeCommandContext(ctx, c.context, c.config, c.invocation())
 }
 
 func (c *GradleCommand) doConfigureGradle() {
 	args := make([]string, 0)
 
 	banner := make([]string, 0)
-	banner = append(banner, "Using gradle at '"+c.executable+"'")
+	wrapperVersion, distribution := resolveGradleDistribution(c.context, c.executable)
+	c.distribution = distribution
+	if len(wrapperVersion) > 0 {
+		banner = append(banner, "Using gradle wrapper "+wrapperVersion+" ("+distribution+") at '"+c.executable+"'")
+	} else {
+		banner = append(banner, "Using gradle at '"+c.executable+"'")
+	}
 	nearest := c.args.HasGumFlag("gn")
 	debug := c.args.HasGumFlag("gd")
 	skipReplace := c.args.HasGumFlag("gr")
@@ -62,27 +81,48 @@ func (c *GradleCommand) doConfigureGradle() {
 		c.config.gradle.setReplace(!skipReplace)
 	}
 	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
 	otargs := c.args.Tool
 	oargs := c.args.Args
 	rtargs, rargs := replaceGradleTasks(c.config, c.args)
+	if c.config.gradle.replace {
+		rargs = expandTaskAbbreviations(c.context, invocation{
+			tool:       "gradle",
+			executable: c.executable,
+			rootDir:    c.rootDir,
+			buildFile:  c.resolveBuildFile()}, rargs)
+	}
+
+	c.version = readKnownToolVersion(c.context, "gradle", c.executable)
+	c.develocity = hasDevelocity(c.context, c.resolveSettingsFile())
+	projectPath := c.resolveProjectPath()
 
 	if len(c.explicitProjectDir) > 0 {
 		banner = append(banner, "to run project at '"+c.explicitProjectDir+"':")
+	} else if includedBuild := c.resolveIncludedBuild(); len(includedBuild) > 0 {
+		args = append(args, "-p")
+		args = append(args, c.rootDir)
+		rargs = qualifyGradleTasks(includedBuild, rargs)
+		banner = append(banner, "to run included build '"+includedBuild+"' at '"+c.rootDir+"':")
+	} else if len(projectPath) > 0 {
+		settingsDir := filepath.Dir(c.settingsFile)
+		args = append(args, "-p")
+		args = append(args, settingsDir)
+		rargs = qualifyGradleTasks(projectPath, rargs)
+		banner = append(banner, "to run project '"+projectPath+"' at '"+settingsDir+"':")
 	} else {
 		var buildFileSet bool
 		if len(c.explicitBuildFile) > 0 {
-			args = append(args, "-b")
-			args = append(args, c.explicitBuildFile)
+			args = append(args, c.buildFileArgs(c.explicitBuildFile, true)...)
 			banner = append(banner, "to run buildFile '"+c.explicitBuildFile+"':")
 			buildFileSet = true
 		} else if nearest && len(c.buildFile) > 0 {
-			args = append(args, "-b")
-			args = append(args, c.buildFile)
+			args = append(args, c.buildFileArgs(c.buildFile, false)...)
 			banner = append(banner, "to run buildFile '"+c.buildFile+"':")
 			buildFileSet = true
 		} else if len(c.rootBuildFile) > 0 {
-			args = append(args, "-b")
-			args = append(args, c.rootBuildFile)
+			args = append(args, c.buildFileArgs(c.rootBuildFile, false)...)
 			banner = append(banner, "to run buildFile '"+c.rootBuildFile+"':")
 			buildFileSet = true
 		}
@@ -95,7 +135,7 @@ func (c *GradleCommand) doConfigureGradle() {
 			pwd, _ := filepath.Abs(c.context.GetWorkingDir())
 			settingsDir, _ := filepath.Abs(filepath.Dir(c.settingsFile))
 
-			if c.rootDir != settingsDir || c.rootDir != pwd {
+			if (c.rootDir != settingsDir || c.rootDir != pwd) && majorVersion(c.version) < 7 {
 				args = append(args, "-c")
 				args = append(args, c.settingsFile)
 			}
@@ -106,21 +146,127 @@ func (c *GradleCommand) doConfigureGradle() {
 		}
 	}
 
+	args = appendSafe(args, resolveGradleInitScripts(c.context, c.config, c.rootDir))
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "gradle"))
+	args = appendSafe(args, resolveScanArgs(c.config, c.args))
 	args = appendSafe(args, rtargs)
 	c.args.Args = appendSafe(args, rargs)
 
-	c.debugGradle(otargs, oargs, rtargs, rargs)
+	c.debugGradle(otargs, oargs, rtargs, rargs, projectPath)
+	showArgsDiff(c.config, c.args, input)
 
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		if len(c.compositeDir) > 0 && len(c.includedBuild) == 0 {
+			fmt.Fprintln(gumOutput, "Part of the composite build at '"+c.compositeDir+"', use --gum-composite to run it from there")
+		}
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
 	}
 }
 
-func (c *GradleCommand) doExecuteGradle() {
-	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Run()
+func (c *GradleCommand) doExecuteGradle() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *GradleCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *GradleCommand) invocation() invocation {
+	return invocation{
+		tool:          "gradle",
+		executable:    c.executable,
+		rootDir:       c.rootDir,
+		buildFile:     c.resolveBuildFile(),
+		watchScan:     c.watchScan(),
+		scanOnFailure: isScanOnFailure(c.config, c.args),
+		args:          c.args}
+}
+
+// Resolves the settings file used by the invocation
+func (c *GradleCommand) resolveSettingsFile() string {
+	if len(c.explicitSettingsFile) > 0 {
+		return c.explicitSettingsFile
+	}
+	return c.settingsFile
+}
+
+// Checks if the build is expected to publish a build scan whose URL should be captured
+func (c *GradleCommand) watchScan() bool {
+	if c.args.HasGumFlag("gnoscan") {
+		return false
+	}
+	return c.develocity || c.args.HasGumFlag("gscan") || hasScanArg(c.args) || c.config.gradle.buildScan == "always"
+}
+
+// Resolves the build file used by the invocation
+func (c *GradleCommand) resolveBuildFile() string {
+	if len(c.explicitBuildFile) > 0 {
+		return c.explicitBuildFile
+	} else if c.args.HasGumFlag("gn") && len(c.buildFile) > 0 {
+		return c.buildFile
+	}
+	return c.rootBuildFile
+}
+
+// Selects the build file to use. Gradle 7 deprecated -b and Gradle 8 removed it,
+// thus -p is used instead when the build file follows the default naming
+func (c *GradleCommand) buildFileArgs(buildFile string, explicit bool) []string {
+	major := majorVersion(c.version)
+	name := filepath.Base(buildFile)
+	conventional := name == "build.gradle" || name == "build.gradle.kts"
+
+	if isAmperDescriptor(buildFile) {
+		return []string{"-p", filepath.Dir(buildFile)}
+	}
+
+	if major >= 8 {
+		if !conventional && !c.config.general.quiet {
+			fmt.Fprintln(gumOutput, "WARNING: Gradle "+c.version+" does not support -b. Running project at '"+filepath.Dir(buildFile)+"' instead")
+		}
+		return []string{"-p", filepath.Dir(buildFile)}
+	} else if major == 7 {
+		if explicit && !c.config.general.quiet {
+			fmt.Fprintln(gumOutput, "WARNING: -b is deprecated since Gradle 7.0. Consider using -p or enabling gradle.projectPaths")
+		}
+		if conventional && !explicit {
+			return []string{"-p", filepath.Dir(buildFile)}
+		}
+	}
+
+	return []string{"-b", buildFile}
+}
+
+// Resolves the path (such as :buildSrc) of the build at the working dir when it is included by
+// the outer project, unless -gn is given. Tasks of buildSrc may be addressed since Gradle 8
+func (c *GradleCommand) resolveIncludedBuild() string {
+	if len(c.includedBuild) == 0 || c.args.HasGumFlag("gn") {
+		return ""
+	}
+
+	major := majorVersion(c.version)
+	if strings.HasPrefix(c.includedBuild, ":buildSrc") && major > 0 && major < 8 {
+		return ""
+	}
+	return c.includedBuild
+}
+
+// Resolves the project path of the nearest build file when it belongs to a
+// child project and gradle.projectPaths is enabled. Returns an empty string otherwise
+func (c *GradleCommand) resolveProjectPath() string {
+	if !c.config.gradle.projectPaths ||
+		c.args.HasGumFlag("gn") ||
+		len(c.explicitBuildFile) > 0 ||
+		len(c.explicitSettingsFile) > 0 ||
+		len(c.settingsFile) == 0 {
+		return ""
+	}
+
+	settingsDir, _ := filepath.Abs(filepath.Dir(c.settingsFile))
+	includes := append(readGradleIncludes(c.settingsFile), readAmperModules(settingsDir)...)
+	pwd, _ := filepath.Abs(c.context.GetWorkingDir())
+	return resolveGradleProjectPath(settingsDir, pwd, includes, readGradleProjectDirs(c.settingsFile))
 }
 
 func (c *GradleCommand) debugConfig() {
@@ -130,29 +276,72 @@ func (c *GradleCommand) debugConfig() {
 	}
 }
 
-func (c *GradleCommand) debugGradle(otargs []string, oargs []string, rtargs []string, rargs []string) {
+func (c *GradleCommand) debugGradle(otargs []string, oargs []string, rtargs []string, rargs []string, projectPath string) {
 	if c.config.general.debug {
-		fmt.Println("nearest              = ", c.args.HasGumFlag("gn"))
-		fmt.Println("replace              = ", c.config.gradle.replace)
-		fmt.Println("pwd                  = ", c.context.GetWorkingDir())
-		fmt.Println("rootDir              = ", c.rootDir)
-		fmt.Println("rootBuildFile        = ", c.rootBuildFile)
-		fmt.Println("buildFile            = ", c.buildFile)
-		fmt.Println("settingsFile         = ", c.settingsFile)
-		fmt.Println("explicitBuildFile    = ", c.explicitBuildFile)
-		fmt.Println("explicitSettingsFile = ", c.explicitSettingsFile)
-		fmt.Println("explicitProjectDir   = ", c.explicitProjectDir)
-		fmt.Println("original tool args   = ", otargs)
+		fmt.Fprintln(gumOutput, "nearest              = ", c.args.HasGumFlag("gn"))
+		fmt.Fprintln(gumOutput, "replace              = ", c.config.gradle.replace)
+		fmt.Fprintln(gumOutput, "version              = ", c.version)
+		fmt.Fprintln(gumOutput, "distribution         = ", c.distribution)
+		fmt.Fprintln(gumOutput, "projectPaths         = ", c.config.gradle.projectPaths)
+		fmt.Fprintln(gumOutput, "projectPath          = ", projectPath)
+		fmt.Fprintln(gumOutput, "develocity           = ", c.develocity)
+		fmt.Fprintln(gumOutput, "pwd                  = ", c.context.GetWorkingDir())
+		fmt.Fprintln(gumOutput, "rootDir              = ", c.rootDir)
+		fmt.Fprintln(gumOutput, "rootBuildFile        = ", c.rootBuildFile)
+		fmt.Fprintln(gumOutput, "buildFile            = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "settingsFile         = ", c.settingsFile)
+		fmt.Fprintln(gumOutput, "explicitBuildFile    = ", c.explicitBuildFile)
+		fmt.Fprintln(gumOutput, "explicitSettingsFile = ", c.explicitSettingsFile)
+		fmt.Fprintln(gumOutput, "explicitProjectDir   = ", c.explicitProjectDir)
+		fmt.Fprintln(gumOutput, "original tool args   = ", otargs)
 		if c.config.gradle.replace {
-			fmt.Println("replaced tool args   = ", rtargs)
+			fmt.Fprintln(gumOutput, "replaced tool args   = ", rtargs)
 		}
-		fmt.Println("original args        = ", oargs)
+		fmt.Fprintln(gumOutput, "original args        = ", oargs)
 		if c.config.gradle.replace {
-			fmt.Println("replaced args        = ", rargs)
+			fmt.Fprintln(gumOutput, "replaced args        = ", rargs)
+		}
+		fmt.Fprintln(gumOutput, "actual args          = ", c.args.Args)
+		properties := resolveGradleProperties(c.context, c.rootDir, c.args.Args)
+		for _, name := range gradleDebugProperties {
+			if value, ok := properties[name]; ok {
+				fmt.Fprintln(gumOutput, fmt.Sprintf("%-21s= ", name), value)
+			}
+		}
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// Properties of gradle.properties displayed with -gd, as they tune memory and parallelism
+var gradleDebugProperties = []string{"org.gradle.jvmargs", "org.gradle.parallel", "org.gradle.caching"}
+
+// Resolves the effective values of gradleDebugProperties, along with where they were set.
+// The gradle.properties of the Gradle user home overrides the one at the root dir, and
+// -D args override both
+func resolveGradleProperties(context Context, rootDir string, args []string) map[string]string {
+	properties := make(map[string]string)
+
+	for _, path := range []string{filepath.Join(rootDir, "gradle.properties"), filepath.Join(resolveGradleUserHome(context), "gradle.properties")} {
+		props, err := readProperties(path)
+		if err != nil {
+			continue
+		}
+		for _, name := range gradleDebugProperties {
+			if value, ok := props[name]; ok {
+				properties[name] = value + " (" + path + ")"
+			}
+		}
+	}
+
+	for _, arg := range args {
+		for _, name := range gradleDebugProperties {
+			if strings.HasPrefix(arg, "-D"+name+"=") {
+				properties[name] = strings.TrimPrefix(arg, "-D"+name+"=") + " (command line)"
+			}
 		}
-		fmt.Println("actual args          = ", c.args.Args)
-		fmt.Println("")
 	}
+
+	return properties
 }
 
 func replaceGradleTasks(config *Config, args *ParsedArgs) ([]string, []string) {
@@ -170,18 +359,62 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 	gradle, noGradle := findGradleExec(context)
 	explicitProjectDirSet, explicitProjectDir := findExplicitProjectDir(args)
 
-	gradlew, noWrapper := resolveGradleWrapperExecutable(context, args)
+	walk := walkUp(context, pwd)
+	gradlew, noWrapper := resolveGradleWrapperExecutable(walk, args)
 	explicitBuildFileSet, explicitBuildFile := findExplicitGradleBuildFile(args)
 	explicitSettingsFileSet, explicitSettingsFile := findExplicitGradleSettingsFile(args)
-	settingsFile, noSettings := findGradleSettingsFile(context, pwd)
-	buildFile, noBuildFile := findGradleBuildFile(context, pwd)
+	explicitProjectDir = resolveRealPath(context, explicitProjectDir)
+	explicitBuildFile = resolveRealPath(context, explicitBuildFile)
+	explicitSettingsFile = resolveRealPath(context, explicitSettingsFile)
+	settingsFile, noSettings := findGradleSettingsFile(walk, pwd)
+	buildFile, noBuildFile := findGradleBuildFile(walk, pwd)
+	amper := noSettings == nil && isAmperProject(context, settingsFile)
+
+	if amper {
+		moduleFile, noModuleFile := findAmperModuleFile(context, pwd, filepath.Dir(settingsFile))
+		if noModuleFile == nil {
+			buildFile = nearestGradleOrAmperFile(buildFile, moduleFile)
+			noBuildFile = nil
+		}
+	}
 
 	sf := settingsFile
 	if explicitBuildFileSet {
 		sf = explicitBuildFile
 	}
 
-	rootBuildFile, noRootBuildFile := findGradleRootFile(context, filepath.Join(pwd, ".."), args, sf)
+	rootBuildFile, noRootBuildFile := findGradleRootFile(walk, filepath.Join(pwd, ".."), sf)
+	if amper && noRootBuildFile != nil {
+		projectFile := filepath.Join(filepath.Dir(settingsFile), amperProjectFile)
+		if context.FileExists(projectFile) {
+			rootBuildFile, noRootBuildFile = projectFile, nil
+		}
+	}
+	// buildSrc and builds included by the outer project are not roots on their own, neither are
+	// builds included by a sibling composite when --gum-composite is given
+	includedBuild, compositeDir := "", ""
+	if !explicitProjectDirSet && !explicitBuildFileSet && !explicitSettingsFileSet {
+		outerDir, buildDir := findGradleOuterBuild(walk, pwd)
+		if len(outerDir) == 0 && noSettings == nil {
+			buildDir = filepath.Dir(settingsFile)
+			compositeDir = findGradleComposite(walk, buildDir)
+			if args.HasGumFlag("-gum-composite") {
+				outerDir = compositeDir
+			}
+		}
+
+		if len(outerDir) > 0 {
+			includedBuild = ":" + filepath.Base(buildDir)
+			if noBuildFile == nil {
+				includedBuild += gradleProjectPath(buildDir, filepath.Dir(buildFile))
+			}
+			settingsFile, noSettings = findGradleFileAt(walk, outerDir, "settings.gradle", "settings.gradle.kts")
+			rootBuildFile, noRootBuildFile = findGradleFileAt(walk, outerDir, "build.gradle", "build.gradle.kts")
+			if wrapper, err := findGradleWrapperExec(walk, outerDir); err == nil {
+				gradlew, noWrapper = wrapper, nil
+			}
+		}
+	}
 	rootdir := resolveGradleRootDir(context, explicitProjectDir, explicitBuildFile, explicitSettingsFile, buildFile, rootBuildFile, settingsFile)
 	config := ReadConfig(context, rootdir)
 	quiet := args.HasGumFlag("gq")
@@ -190,22 +423,43 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 		config.setQuiet(quiet)
 	}
 
+	report := newDiscoveryReport("Gradle")
+	report.check(resolveGradleWrapperExec(context), gradlew)
+	report.check(resolveGradleExec(context), gradle)
+	report.check("settings file", settingsFile)
+	report.check("build file", buildFile)
+	report.check("root build file", rootBuildFile)
+	if amper {
+		report.assume("Amper project, module.yaml defines child projects")
+	}
+	if len(includedBuild) > 0 {
+		report.assume("included build " + includedBuild + " of the project at " + rootdir)
+	} else if len(compositeDir) > 0 {
+		report.assume("included build of the composite at " + compositeDir)
+	}
+
 	var executable string
 	if noWrapper == nil {
 		executable = gradlew
 	} else if noGradle == nil {
-		warnNoGradleWrapper(context, config)
+		if context.IsExplicit() {
+			report.fallback("using "+gradle+". Please consider setting up a wrapper",
+				"(https://gradle.org/docs/current/userguide/gradle_wrapper.html)")
+		}
 		executable = gradle
 	} else {
-		warnNoGradle(context, config)
-
 		if context.IsExplicit() {
+			report.fallback("none. Please install Gradle",
+				"(https://gradle.org/docs/current/userguide/installation.html)")
+			report.emit(config, args)
 			context.Exit(-1)
 		}
 		return nil
 	}
 
 	if explicitProjectDirSet {
+		report.check("explicit project dir", explicitProjectDir)
+		report.emit(config, args)
 		return &GradleCommand{
 			context:            context,
 			config:             config,
@@ -216,6 +470,8 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 	}
 
 	if explicitBuildFileSet {
+		report.check("explicit build file", explicitBuildFile)
+		report.emit(config, args)
 		if explicitSettingsFileSet {
 			return &GradleCommand{
 				context:              context,
@@ -238,14 +494,19 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 
 	if noRootBuildFile != nil {
 		rootBuildFile = buildFile
+		if noBuildFile == nil {
+			report.assume("the nearest build file is the root build file")
+		}
+	}
+	if noSettings != nil && noBuildFile == nil {
+		report.assume("single project build")
 	}
 
 	if noBuildFile != nil {
 		if explicitSettingsFileSet {
-			if !config.general.quiet {
-				fmt.Printf("Did not find a suitable Gradle build file but %s is specified", explicitSettingsFile)
-				fmt.Println()
-			}
+			report.check("explicit settings file", explicitSettingsFile)
+			report.fallback("running with the explicit settings file " + explicitSettingsFile)
+			report.emit(config, args)
 			return &GradleCommand{
 				context:              context,
 				config:               config,
@@ -256,25 +517,25 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 				rootBuildFile:        rootBuildFile,
 				explicitSettingsFile: explicitSettingsFile}
 		} else if noSettings == nil {
-			if !config.general.quiet {
-				fmt.Printf("Did not find a suitable Gradle build file but found %s", settingsFile)
-				fmt.Println()
-			}
+			report.fallback("running with the settings file " + settingsFile)
 		} else {
 			if context.IsExplicit() {
-				fmt.Println("No Gradle project found")
-				fmt.Println()
+				report.fallback("none. No Gradle project found")
+				report.emit(config, args)
 				context.Exit(-1)
 			}
 			return nil
 		}
 	}
 
+	report.emit(config, args)
 	return &GradleCommand{
 		context:              context,
 		config:               config,
 		executable:           executable,
 		args:                 args,
+		includedBuild:        includedBuild,
+		compositeDir:         compositeDir,
 		rootDir:              rootdir,
 		buildFile:            buildFile,
 		rootBuildFile:        rootBuildFile,
@@ -306,33 +567,14 @@ func resolveGradleRootDir(context Context,
 	return dir
 }
 
-func resolveGradleWrapperExecutable(context Context, args *ParsedArgs) (string, error) {
-	pwd := context.GetWorkingDir()
+func resolveGradleWrapperExecutable(walk *upwardWalk, args *ParsedArgs) (string, error) {
+	pwd := walk.context.GetWorkingDir()
 	projectDirSet, projectDir := findExplicitProjectDir(args)
 
 	if projectDirSet {
-		return findGradleWrapperExec(context, projectDir)
-	}
-	return findGradleWrapperExec(context, pwd)
-}
-
-func warnNoGradleWrapper(context Context, config *Config) {
-	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s set up for this project. ", resolveGradleWrapperExec(context))
-		fmt.Println()
-		fmt.Println("Please consider setting one up.")
-		fmt.Println("(https://gradle.org/docs/current/userguide/gradle_wrapper.html)")
-		fmt.Println()
-	}
-}
-
-func warnNoGradle(context Context, config *Config) {
-	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s found in path. Please install Gradle.", resolveGradleExec(context))
-		fmt.Println()
-		fmt.Println("(https://gradle.org/docs/current/userguide/installation.html)")
-		fmt.Println()
+		return findGradleWrapperExec(walk, projectDir)
 	}
+	return findGradleWrapperExec(walk, pwd)
 }
 
 // Finds the gradle executable
@@ -351,20 +593,16 @@ func findGradleExec(context Context) (string, error) {
 }
 
 // Finds the gradle wrapper (if it exists)
-func findGradleWrapperExec(context Context, dir string) (string, error) {
-	wrapper := resolveGradleWrapperExec(context)
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New(wrapper + " not found")
-	}
+func findGradleWrapperExec(walk *upwardWalk, dir string) (string, error) {
+	wrapper := resolveGradleWrapperExec(walk.context)
 
-	path := filepath.Join(dir, wrapper)
-	if context.FileExists(path) {
-		return filepath.Abs(path)
+	for _, listing := range walk.from(dir) {
+		if path, ok := walk.find(listing, wrapper); ok {
+			return filepath.Abs(resolveRealPath(walk.context, path))
+		}
 	}
 
-	return findGradleWrapperExec(context, parentdir)
+	return "", errors.New(wrapper + " not found")
 }
 
 func findExplicitProjectDir(args *ParsedArgs) (bool, string) {
@@ -438,79 +676,149 @@ func findExplicitGradleSettingsFile(args *ParsedArgs) (bool, string) {
 // - build.gradle.kts
 // - ${basedir}.gradle
 // - ${basedir}.gradle.kts
-func findGradleBuildFile(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New("Did not find Gradle build file")
+func findGradleBuildFile(walk *upwardWalk, dir string) (string, error) {
+	for _, listing := range walk.from(dir) {
+		basedir := filepath.Base(listing.dir)
+		if path, ok := walk.find(listing, "build.gradle", "build.gradle.kts", basedir+".gradle", basedir+".gradle.kts"); ok {
+			return filepath.Abs(path)
+		}
 	}
 
-	var buildFiles [4]string
-	buildFiles[0] = "build.gradle"
-	buildFiles[1] = "build.gradle.kts"
-	buildFiles[2] = filepath.Base(dir) + ".gradle"
-	buildFiles[3] = filepath.Base(dir) + ".gradle.kts"
+	return "", errors.New("Did not find Gradle build file")
+}
 
-	for i := range buildFiles {
-		path := filepath.Join(dir, buildFiles[i])
-		if context.FileExists(path) {
+// Finds settings.gradle(.kts)
+// Unless explicit -c settingsFile is given in args
+func findGradleSettingsFile(walk *upwardWalk, dir string) (string, error) {
+	for _, listing := range walk.from(dir) {
+		if path, ok := walk.find(listing, "settings.gradle", "settings.gradle.kts"); ok {
 			return filepath.Abs(path)
 		}
 	}
 
-	return findGradleBuildFile(context, parentdir)
+	return "", errors.New("Did not find Gradle settings file")
 }
 
-// Finds settings.gradle(.kts)
-// Unless explicit -c settingsFile is given in args
-func findGradleSettingsFile(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
+// Finds the root build file, stopping at the dir of the settings file if any. Build files above it
+// belong to another build, such as the one included with includeBuild("..") by the settings file
+func findGradleRootFile(walk *upwardWalk, dir string, settingsFile string) (string, error) {
+	for _, listing := range walk.from(dir) {
+		if len(settingsFile) > 0 && !isParentDir(filepath.Dir(settingsFile), listing.dir) {
+			break
+		}
 
-	if parentdir == dir {
-		return "", errors.New("Did not find Gradle settings file")
+		if path, ok := walk.find(listing, "build.gradle", "build.gradle.kts"); ok {
+			return filepath.Abs(path)
+		}
 	}
 
-	var settingsFiles [2]string
-	settingsFiles[0] = "settings.gradle"
-	settingsFiles[1] = "settings.gradle.kts"
+	return "", errors.New("Did not find root build file")
+}
+
+// Finds the project that includes the build at dir, either as its buildSrc or with includeBuild
+// in its settings file. Returns the dir of the outer project and the dir of the included build
+func findGradleOuterBuild(walk *upwardWalk, dir string) (string, string) {
+	dir = filepath.Clean(dir)
 
-	for i := range settingsFiles {
-		path := filepath.Join(dir, settingsFiles[i])
-		if context.FileExists(path) {
-			return filepath.Abs(path)
+	for _, listing := range walk.from(dir) {
+		rel, err := filepath.Rel(listing.dir, dir)
+		if err != nil || rel == "." {
+			continue
+		}
+
+		settingsFile, noSettings := findGradleFileAt(walk, listing.dir, "settings.gradle", "settings.gradle.kts")
+		_, noBuildFile := findGradleFileAt(walk, listing.dir, "build.gradle", "build.gradle.kts")
+		if noSettings != nil && noBuildFile != nil {
+			continue
+		}
+
+		if strings.Split(filepath.ToSlash(rel), "/")[0] == "buildSrc" {
+			return listing.dir, filepath.Join(listing.dir, "buildSrc")
+		}
+		if noSettings == nil {
+			for _, buildDir := range readGradleIncludedBuilds(settingsFile) {
+				if buildDir != listing.dir && isParentDir(buildDir, dir) {
+					return listing.dir, buildDir
+				}
+			}
 		}
 	}
 
-	return findGradleSettingsFile(context, parentdir)
+	return "", ""
 }
 
-// Finds the root build file
-func findGradleRootFile(context Context, dir string, args *ParsedArgs, settingsFile string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
+// Levels above an included build searched for a sibling composite
+const gradleCompositeDepth = 2
+
+// Finds a sibling composite that includes the build at buildDir with includeBuild, such as
+// ../composite/settings.gradle including ../lib, among the children of the parent and
+// grandparent of buildDir. Returns the dir of the composite
+func findGradleComposite(walk *upwardWalk, buildDir string) string {
+	listings := walk.from(filepath.Dir(buildDir))
+	if len(listings) > gradleCompositeDepth {
+		listings = listings[:gradleCompositeDepth]
+	}
+
+	for _, listing := range listings {
+		names := make([]string, 0)
+		for name := range listing.names {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 
-	if parentdir == dir {
-		return "", errors.New("Did not find root build file")
+		for _, name := range names {
+			dir := filepath.Join(listing.dir, name)
+			if isParentDir(dir, buildDir) {
+				continue
+			}
+			for _, settings := range []string{"settings.gradle", "settings.gradle.kts"} {
+				settingsFile := filepath.Join(dir, settings)
+				if !walk.context.FileExists(settingsFile) {
+					continue
+				}
+				for _, includedDir := range readGradleIncludedBuilds(settingsFile) {
+					if includedDir == buildDir {
+						return dir
+					}
+				}
+			}
+		}
 	}
 
-	var buildFiles [2]string
-	buildFiles[0] = "build.gradle"
-	buildFiles[1] = "build.gradle.kts"
+	return ""
+}
 
-	for i := range buildFiles {
-		path := filepath.Join(dir, buildFiles[i])
-		if context.FileExists(path) {
+// Finds the first of names in dir, without walking up
+func findGradleFileAt(walk *upwardWalk, dir string, names ...string) (string, error) {
+	if listings := walk.from(dir); len(listings) > 0 {
+		if path, ok := walk.find(listings[0], names...); ok {
 			return filepath.Abs(path)
 		}
 	}
 
-	if len(settingsFile) > 0 {
-		settingsdir := filepath.Dir(settingsFile)
-		if len(parentdir) <= len(settingsdir) {
-			return "", errors.New("Did not find root build file")
+	return "", errors.New("Did not find " + strings.Join(names, " or "))
+}
+
+// Resolves the --init-script args of gradle.initScripts. A leading ~ stands for the home dir,
+// relative paths are resolved against the root dir. Missing scripts are skipped with a warning
+func resolveGradleInitScripts(context Context, config *Config, rootDir string) []string {
+	args := make([]string, 0)
+	for _, script := range config.gradle.initScripts {
+		if script == "~" || strings.HasPrefix(script, "~/") {
+			script = filepath.Join(context.GetHomeDir(), script[1:])
+		} else if !filepath.IsAbs(script) {
+			script = filepath.Join(rootDir, script)
 		}
-	}
 
-	return findGradleRootFile(context, parentdir, args, settingsFile)
+		if !context.FileExists(script) {
+			if !config.general.quiet {
+				fmt.Fprintln(gumOutput, "WARNING: init script "+script+" not found")
+			}
+			continue
+		}
+		args = append(args, "--init-script", script)
+	}
+	return args
 }
 
 // Resolves the gradlew executable (OS dependent)
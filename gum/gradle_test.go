@@ -17,7 +17,10 @@
 package gum
 
 import (
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -167,6 +170,44 @@ func TestGradleSingleWithWrapper(t *testing.T) {
 	}
 }
 
+func TestGradleSingleWithWrapper8(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "single-with-wrapper-8"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build"})
+	cmd := FindGradle(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureGradle()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Version", cmd.version, "8.7"},
+		{"Args", strings.Join(cmd.args.Args, " "), "-p " + pwd + " build"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
 func TestGradleSingleWithoutWrapper(t *testing.T) {
 	// given:
 	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
@@ -249,6 +290,45 @@ func TestGradleParentWithWrapper(t *testing.T) {
 	}
 }
 
+func TestGradleParentWithProjectPaths(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "parent-with-child-project"))
+	pwd := filepath.Join(root, "child")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build", ":other:test", "-x", "javadoc"})
+	cmd := FindGradle(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.config.gradle.projectPaths = true
+	cmd.doConfigureGradle()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Args", strings.Join(cmd.args.Args, " "), "-p " + root + " :child:build :other:test -x javadoc"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
 func TestGradleParentWithoutWrapper(t *testing.T) {
 	// given:
 	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
@@ -534,3 +614,219 @@ func TestGradleReplaceWithSubMatch(t *testing.T) {
 		t.Errorf("args: got :subproject:verify, want b:subproject:build")
 	}
 }
+
+func TestGradleAmper(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "amper"))
+	module := filepath.Join(root, "libs", "core")
+
+	var checks = []struct {
+		title        string
+		projectPaths bool
+		flags        []string
+		expected     string
+	}{
+		{"Root", false, []string{}, "-p " + root + " build"},
+		{"Nearest", false, []string{"-gn"}, "-p " + module + " build"},
+		{"ProjectPaths", true, []string{}, "-p " + root + " :libs:core:build"},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   true,
+			windows:    false,
+			workingDir: filepath.Join(module, "src"),
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs(append(check.flags, "-gq", "build"))
+		cmd := FindGradle(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+
+		cmd.config.gradle.projectPaths = check.projectPaths
+		cmd.doConfigureGradle()
+
+		actual := strings.Join(cmd.args.Args, " ")
+		if actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestGradleIncludedBuilds(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "included"))
+
+	var checks = []struct {
+		title    string
+		pwd      string
+		flags    []string
+		expected string
+	}{
+		{"BuildSrc", filepath.Join(root, "buildSrc", "src", "main", "kotlin"), []string{}, "-p " + root + " :buildSrc:build"},
+		{"BuildSrcNearest", filepath.Join(root, "buildSrc"), []string{"-gn"}, "-p " + filepath.Join(root, "buildSrc") + " build"},
+		{"IncludedBuild", filepath.Join(root, "build-logic"), []string{}, "-p " + root + " :build-logic:build"},
+		{"IncludedBuildProject", filepath.Join(root, "build-logic", "plugins", "src"), []string{}, "-p " + root + " :build-logic:plugins:build"},
+		{"Project", filepath.Join(root, "app", "src"), []string{}, "-p " + root + " :app:build"},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   true,
+			windows:    false,
+			workingDir: check.pwd,
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs(append(check.flags, "-gq", "build"))
+		cmd := FindGradle(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+
+		cmd.doConfigureGradle()
+
+		if actual := strings.Join(cmd.args.Args, " "); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+		if cmd.executable != filepath.Join(root, "gradlew") {
+			t.Errorf("%s: got executable %s, want the wrapper of %s", check.title, cmd.executable, root)
+		}
+	}
+}
+
+func TestGradleCompositeBuilds(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "composite"))
+	composite := filepath.Join(root, "composite")
+	lib := filepath.Join(root, "lib")
+	samples := filepath.Join(root, "app", "samples")
+
+	var checks = []struct {
+		title      string
+		pwd        string
+		flags      []string
+		expected   string
+		executable string
+		composite  string
+	}{
+		{"Leaf", lib, []string{}, "-p " + lib + " build", filepath.Join(lib, "gradlew"), composite},
+		{"Composite", lib, []string{"--gum-composite"}, "-p " + composite + " :lib:build", filepath.Join(composite, "gradlew"), composite},
+		{"IncludesParent", samples, []string{}, "-p " + samples + " build", filepath.Join(root, "app", "gradlew"), ""},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   true,
+			windows:    false,
+			workingDir: check.pwd,
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs(append(check.flags, "-gq", "build"))
+		cmd := FindGradle(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+
+		cmd.doConfigureGradle()
+
+		if actual := strings.Join(cmd.args.Args, " "); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+		if cmd.executable != check.executable {
+			t.Errorf("%s: got executable %s, want %s", check.title, cmd.executable, check.executable)
+		}
+		if cmd.compositeDir != check.composite {
+			t.Errorf("%s: got composite %s, want %s", check.title, cmd.compositeDir, check.composite)
+		}
+	}
+}
+
+func TestGradleInitScripts(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "single-with-wrapper"))
+	os.MkdirAll(filepath.Join(home, ".gum", "init"), 0755)
+	ioutil.WriteFile(filepath.Join(home, ".gum", "init", "observability.gradle"), []byte(""), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: root,
+		homeDir:    home}
+
+	user := newConfig()
+	user.gradle.initScripts = []string{"~/.gum/init/observability.gradle", "~/.gum/init/missing.gradle"}
+	config := newConfig()
+	config.gradle.initScripts = []string{"build.gradle"}
+	config.merge(user)
+	config.general.quiet = true
+
+	// when:
+	args := resolveGradleInitScripts(context, config, root)
+
+	// then:
+	expected := "--init-script " + filepath.Join(home, ".gum", "init", "observability.gradle") +
+		" --init-script " + filepath.Join(root, "build.gradle")
+	if actual := strings.Join(args, " "); actual != expected {
+		t.Errorf("got %s, want %s", actual, expected)
+	}
+}
+
+func TestGradleProperties(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	root := filepath.Join(dir, "project")
+	home := filepath.Join(dir, "gradle-home")
+	os.MkdirAll(root, 0755)
+	os.MkdirAll(home, 0755)
+	ioutil.WriteFile(filepath.Join(root, "gradle.properties"), []byte("org.gradle.jvmargs=-Xmx1g\norg.gradle.parallel=true\norg.gradle.caching=true\n"), 0644)
+	ioutil.WriteFile(filepath.Join(home, "gradle.properties"), []byte("org.gradle.jvmargs=-Xmx4g\n"), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: root,
+		env:        map[string]string{"GRADLE_USER_HOME": home}}
+
+	// when:
+	properties := resolveGradleProperties(context, root, []string{"-Dorg.gradle.caching=false", "build"})
+
+	// then:
+	var checks = []struct {
+		name     string
+		expected string
+	}{
+		{"org.gradle.jvmargs", "-Xmx4g (" + filepath.Join(home, "gradle.properties") + ")"},
+		{"org.gradle.parallel", "true (" + filepath.Join(root, "gradle.properties") + ")"},
+		{"org.gradle.caching", "false (command line)"},
+	}
+
+	for _, check := range checks {
+		if actual := properties[check.name]; actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.name, actual, check.expected)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GrailsCommand defines an executable Grails command
+type GrailsCommand struct {
+	context    Context
+	config     *Config
+	rootdir    string
+	executable string
+	args       *ParsedArgs
+}
+
+// Execute executes the given command
+func (c GrailsCommand) Execute() int {
+	c.doConfigureGrails()
+	return ExitCode(c.doExecuteGrails())
+}
+
+func (c *GrailsCommand) doConfigureGrails() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using Grails at '"+c.executable+"'")
+	banner = append(banner, "to run application at '"+c.rootdir+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "grails"))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugGrails(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *GrailsCommand) doExecuteGrails() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the invocation of the configured command.
+// Resolves the config of the command
+func (c *GrailsCommand) configuration() *Config {
+	return c.config
+}
+
+// Grails commands apply to the application in the current directory thus it's launched from the root
+func (c *GrailsCommand) invocation() invocation {
+	return invocation{
+		tool:       "grails",
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		workDir:    c.rootdir,
+		args:       c.args}
+}
+
+func (c *GrailsCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *GrailsCommand) debugGrails(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindGrails finds and executes Grails.
+// Grails applications are also Gradle builds, thus Grails takes precedence only
+// if the application has a grailsw wrapper, or if it has a grails-app directory
+// but no Gradle build file. Otherwise the application is left to Gradle
+func FindGrails(context Context, args *ParsedArgs) *GrailsCommand {
+	pwd := context.GetWorkingDir()
+
+	rootdir, noRootDir := findGrailsRootDir(context, pwd)
+	if noRootDir != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No Grails application found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	grailsw := filepath.Join(rootdir, resolveGrailsWrapperExec(context))
+	grails, noGrails := findGrailsExec(context)
+
+	var executable string
+	if isRegularFile(grailsw) {
+		executable = grailsw
+	} else if !context.IsExplicit() && hasGradleBuildFile(context, rootdir) {
+		return nil
+	} else if noGrails == nil {
+		executable = grails
+	} else {
+		warnNoGrails(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &GrailsCommand{
+		context:    context,
+		config:     config,
+		rootdir:    rootdir,
+		executable: executable,
+		args:       args}
+}
+
+func warnNoGrails(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s set up for this application nor %s found in path. Please install Grails.",
+			resolveGrailsWrapperExec(context), resolveGrailsExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://grails.org/download.html)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the nearest directory with either a grailsw wrapper or a grails-app directory
+func findGrailsRootDir(context Context, dir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	if parentdir == dir {
+		return "", errors.New("Did not find a Grails application")
+	}
+
+	if isRegularFile(filepath.Join(dir, resolveGrailsWrapperExec(context))) ||
+		context.FileExists(filepath.Join(dir, "grails-app")) {
+		return filepath.Abs(dir)
+	}
+
+	if isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find a Grails application")
+	}
+
+	return findGrailsRootDir(context, parentdir)
+}
+
+// Checks if the given directory contains a Gradle build file
+func hasGradleBuildFile(context Context, dir string) bool {
+	for _, name := range []string{"build.gradle", "build.gradle.kts"} {
+		if context.FileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Finds the grails executable
+func findGrailsExec(context Context) (string, error) {
+	grails := resolveGrailsExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], grails)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(grails + " not found")
+}
+
+// Resolves the grailsw executable (OS dependent)
+func resolveGrailsWrapperExec(context Context) string {
+	if context.IsWindows() {
+		return "grailsw.bat"
+	}
+	return "grailsw"
+}
+
+// Resolves the grails executable (OS dependent)
+func resolveGrailsExec(context Context) string {
+	if context.IsWindows() {
+		return "grails.bat"
+	}
+	return "grails"
+}
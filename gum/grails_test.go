@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGrailsWithWrapper(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "grails", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "grails", "with-wrapper"))
+	pwd := filepath.Join(root, "grails-app", "controllers")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "run-app"})
+	cmd := FindGrails(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureGrails()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(root, "grailsw")},
+		{"RootDir", cmd.rootdir, root},
+		{"WorkDir", cmd.invocation().workDir, root},
+		{"Args", cmd.args.Args[0], "run-app"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestGrailsPrecedence(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "grails", "bin"))
+
+	var checks = []struct {
+		title    string
+		dir      string
+		explicit bool
+		expected string
+	}{
+		{"GradleBuildWithoutWrapper", "with-gradle", false, ""},
+		{"ExplicitGradleBuildWithoutWrapper", "with-gradle", true, filepath.Join(bin, "grails")},
+		{"WithoutGradleBuild", "without-gradle", false, filepath.Join(bin, "grails")},
+	}
+
+	for _, check := range checks {
+		pwd, _ := filepath.Abs(filepath.Join("..", "tests", "grails", check.dir))
+		context := testContext{
+			quiet:      true,
+			explicit:   check.explicit,
+			windows:    false,
+			workingDir: pwd,
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs([]string{"-gq"})
+		cmd := FindGrails(context, &args)
+
+		// then:
+		actual := ""
+		if cmd != nil {
+			actual = cmd.executable
+		}
+		if actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
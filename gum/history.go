@@ -0,0 +1,310 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// historyEntry records a single tool invocation
+type historyEntry struct {
+	Time     time.Time `json:"time"`
+	Tool     string    `json:"tool"`
+	Dir      string    `json:"dir"`
+	Tasks    []string  `json:"tasks"`
+	Args     []string  `json:"args"`
+	Duration int64     `json:"duration"`
+	Success  bool      `json:"success"`
+	ExitCode int       `json:"exitCode"`
+}
+
+// The number of previous runs taken into account when estimating durations
+const historySamples = 10
+
+func resolveHistoryFile(context Context) string {
+	return filepath.Join(resolveStateDir(context), "history.jsonl")
+}
+
+// Creates a history entry for the given invocation
+func newHistoryEntry(context Context, inv invocation) historyEntry {
+	dir := inv.rootDir
+	if len(dir) == 0 {
+		dir = context.GetWorkingDir()
+	}
+	dir, _ = filepath.Abs(dir)
+
+	tasks := make([]string, 0)
+	for _, i := range findTaskArgs(inv.tool, inv.args.Args) {
+		tasks = append(tasks, inv.args.Args[i])
+	}
+
+	return historyEntry{
+		Tool:  inv.tool,
+		Dir:   dir,
+		Tasks: tasks,
+		Args:  inv.args.Args}
+}
+
+// Checks if both entries ran the same tasks with the same tool on the same project
+func (e *historyEntry) matches(other historyEntry) bool {
+	return e.Tool == other.Tool &&
+		e.Dir == other.Dir &&
+		strings.Join(e.Tasks, " ") == strings.Join(other.Tasks, " ")
+}
+
+func readHistory(path string) []historyEntry {
+	entries := make([]historyEntry, 0)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return entries
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func writeHistoryEntry(path string, entry historyEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintln(file, string(data))
+	return err
+}
+
+// Estimates how long an invocation will take based on previous successful runs.
+// Returns the duration of the last run and the median of the most recent runs
+func estimateDuration(entries []historyEntry, entry historyEntry) (time.Duration, time.Duration, int) {
+	durations := make([]int64, 0)
+	for i := len(entries) - 1; i >= 0 && len(durations) < historySamples; i-- {
+		if entries[i].Success && entry.matches(entries[i]) {
+			durations = append(durations, entries[i].Duration)
+		}
+	}
+
+	if len(durations) == 0 {
+		return 0, 0, 0
+	}
+
+	return time.Duration(durations[0]) * time.Millisecond, time.Duration(medianOf(durations)) * time.Millisecond, len(durations)
+}
+
+// Prints the estimated duration of the given invocation, if known
+func printEstimatedDuration(entries []historyEntry, entry historyEntry) {
+	last, median, samples := estimateDuration(entries, entry)
+	if samples == 0 {
+		return
+	}
+
+	if samples > 2 {
+		fmt.Fprintln(gumOutput, "last run: "+formatDuration(last)+" (median of "+fmt.Sprint(samples)+" runs: "+formatDuration(median)+")")
+	} else {
+		fmt.Fprintln(gumOutput, "last run: "+formatDuration(last))
+	}
+}
+
+// Formats a duration such as 2m 13s
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+
+	d = d.Round(time.Second)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+
+	if h > 0 {
+		return fmt.Sprintf("%dh %dm %ds", h, m, s)
+	} else if m > 0 {
+		return fmt.Sprintf("%dm %ds", m, s)
+	}
+	return fmt.Sprintf("%ds", s)
+}
+
+// Describes the outcome of an entry, such as succeeded or failed (exit code 1)
+func (e *historyEntry) status() string {
+	if e.Success {
+		return "succeeded"
+	}
+	if e.ExitCode != 0 {
+		return "failed (exit code " + strconv.Itoa(e.ExitCode) + ")"
+	}
+	return "failed"
+}
+
+// Handles "gm gum last". Displays the previous invocation
+func runLastCommand(context Context, args *ParsedArgs, params []string) int {
+	entries := readHistory(resolveHistoryFile(context))
+	if len(entries) == 0 {
+		fmt.Fprintln(gumOutput, "No invocations recorded yet")
+		return -1
+	}
+
+	e := entries[len(entries)-1]
+	fmt.Println("time     = " + e.Time.Local().Format("2006-01-02 15:04:05"))
+	fmt.Println("tool     = " + e.Tool)
+	fmt.Println("project  = " + e.Dir)
+	fmt.Println("args     = " + strings.Join(e.Args, " "))
+	fmt.Println("duration = " + formatDuration(time.Duration(e.Duration)*time.Millisecond))
+	fmt.Println("status   = " + e.status())
+	return 0
+}
+
+// historyStats aggregates the runs of the same tasks with the same tool on the same project
+type historyStats struct {
+	tool      string
+	dir       string
+	tasks     string
+	runs      int
+	failed    int
+	durations []int64
+}
+
+// Median of the durations of successful runs
+func (s *historyStats) median() int64 {
+	return medianOf(s.durations)
+}
+
+// Percentage by which the median of the most recent half of successful runs differs from the
+// median of the oldest half, revealing builds that slowly get slower. Requires 4 runs at least
+func (s *historyStats) trend() (int, bool) {
+	if len(s.durations) < 4 {
+		return 0, false
+	}
+	half := len(s.durations) / 2
+	older := medianOf(s.durations[:half])
+	recent := medianOf(s.durations[len(s.durations)-half:])
+	if older == 0 {
+		return 0, false
+	}
+	return int((recent - older) * 100 / older), true
+}
+
+func medianOf(values []int64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]int64, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + median) / 2
+	}
+	return median
+}
+
+// Groups entries by project, tool, and tasks, sorted by project
+func aggregateHistory(entries []historyEntry) []*historyStats {
+	stats := make([]*historyStats, 0)
+	index := make(map[string]*historyStats)
+	for _, e := range entries {
+		tasks := strings.Join(e.Tasks, " ")
+		key := e.Dir + "\x00" + e.Tool + "\x00" + tasks
+		s, ok := index[key]
+		if !ok {
+			s = &historyStats{tool: e.Tool, dir: e.Dir, tasks: tasks, durations: make([]int64, 0)}
+			index[key] = s
+			stats = append(stats, s)
+		}
+		s.runs++
+		if e.Success {
+			s.durations = append(s.durations, e.Duration)
+		} else {
+			s.failed++
+		}
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].dir != stats[j].dir {
+			return stats[i].dir < stats[j].dir
+		}
+		if stats[i].tool != stats[j].tool {
+			return stats[i].tool < stats[j].tool
+		}
+		return stats[i].tasks < stats[j].tasks
+	})
+	return stats
+}
+
+// Handles "gm gum stats". Displays aggregate durations of the recorded invocations per project
+func runStatsCommand(context Context, args *ParsedArgs, params []string) int {
+	stats := aggregateHistory(readHistory(resolveHistoryFile(context)))
+	if len(stats) == 0 {
+		fmt.Fprintln(gumOutput, "No invocations recorded yet")
+		return -1
+	}
+
+	project := ""
+	for _, s := range stats {
+		if s.dir+s.tool != project {
+			if len(project) > 0 {
+				fmt.Println("")
+			}
+			project = s.dir + s.tool
+			fmt.Println(s.dir + " (" + s.tool + ")")
+		}
+
+		tasks := s.tasks
+		if len(tasks) == 0 {
+			tasks = "(default)"
+		}
+		line := "  " + tasks + ": " + strconv.Itoa(s.runs) + " runs"
+		if s.failed > 0 {
+			line += ", " + strconv.Itoa(s.failed) + " failed"
+		}
+		if len(s.durations) > 0 {
+			line += ", median " + formatDuration(time.Duration(s.median())*time.Millisecond) +
+				", last " + formatDuration(time.Duration(s.durations[len(s.durations)-1])*time.Millisecond)
+		}
+		if trend, ok := s.trend(); ok {
+			line += ", trend " + fmt.Sprintf("%+d%%", trend)
+		}
+		fmt.Println(line)
+	}
+	return 0
+}
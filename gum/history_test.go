@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Millis", formatDuration(800 * time.Millisecond), "800ms"},
+		{"Seconds", formatDuration(45 * time.Second), "45s"},
+		{"Minutes", formatDuration(133 * time.Second), "2m 13s"},
+		{"Hours", formatDuration(time.Hour + 2*time.Minute), "1h 2m 0s"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestEstimateDuration(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+	path := filepath.Join(home, "history.jsonl")
+
+	build := historyEntry{Tool: "gradle", Dir: "/p", Tasks: []string{"build"}, Success: true}
+	test := historyEntry{Tool: "gradle", Dir: "/p", Tasks: []string{"test"}, Success: true}
+	for _, d := range []int64{1000, 5000, 3000} {
+		build.Duration = d
+		writeHistoryEntry(path, build)
+	}
+	test.Duration = 9000
+	writeHistoryEntry(path, test)
+	build.Duration = 99000
+	build.Success = false
+	writeHistoryEntry(path, build)
+
+	// when:
+	last, median, samples := estimateDuration(readHistory(path), build)
+
+	// then:
+	if samples != 3 {
+		t.Errorf("Expected 3 samples, got %d", samples)
+	}
+	if last != 3*time.Second {
+		t.Errorf("Expected last run to take 3s, got %v", last)
+	}
+	if median != 3*time.Second {
+		t.Errorf("Expected median of 3s, got %v", median)
+	}
+}
+
+func TestAggregateHistory(t *testing.T) {
+	// given:
+	entries := []historyEntry{
+		{Tool: "gradle", Dir: "/b", Tasks: []string{"build"}, Duration: 1000, Success: true},
+		{Tool: "gradle", Dir: "/b", Tasks: []string{"build"}, Duration: 1100, Success: true},
+		{Tool: "maven", Dir: "/a", Tasks: []string{"verify"}, Duration: 500, Success: false, ExitCode: 1},
+		{Tool: "gradle", Dir: "/b", Tasks: []string{"build"}, Duration: 1500, Success: true},
+		{Tool: "gradle", Dir: "/b", Tasks: []string{"build"}, Duration: 1600, Success: true},
+		{Tool: "gradle", Dir: "/b", Tasks: []string{"build"}, Duration: 9000, Success: false},
+	}
+
+	// when:
+	stats := aggregateHistory(entries)
+
+	// then:
+	if len(stats) != 2 || stats[0].dir != "/a" || stats[1].dir != "/b" {
+		t.Fatalf("unexpected stats %v", stats)
+	}
+	build := stats[1]
+	if build.runs != 5 || build.failed != 1 || build.median() != 1300 {
+		t.Errorf("got %d runs, %d failed, median %d", build.runs, build.failed, build.median())
+	}
+	if trend, ok := build.trend(); !ok || trend != 47 {
+		t.Errorf("got trend %d (%t), want 47", trend, ok)
+	}
+	if _, ok := stats[0].trend(); ok {
+		t.Error("expected no trend without enough successful runs")
+	}
+}
+
+func TestHistoryEntryStatus(t *testing.T) {
+	var checks = []struct {
+		entry    historyEntry
+		expected string
+	}{
+		{historyEntry{Success: true}, "succeeded"},
+		{historyEntry{Success: false, ExitCode: 3}, "failed (exit code 3)"},
+		{historyEntry{Success: false}, "failed"},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := check.entry.status()
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("got %s, want %s", actual, check.expected)
+		}
+	}
+}
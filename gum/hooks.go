@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Runs the given hooks in order through the platform shell, stopping at the first one
+// that fails. Hooks run at the project's root directory and see the build in GUM_* variables
+func runHooks(ctx gocontext.Context, context Context, config *Config, inv invocation, hooks []string, env []string) error {
+	for _, hook := range hooks {
+		if config.general.debug {
+			fmt.Fprintln(gumOutput, "Running hook '"+hook+"'")
+		}
+
+		cmd := newHookCommand(ctx, context.IsWindows(), hook)
+		cmd.Dir = inv.rootDir
+		if len(cmd.Dir) == 0 {
+			cmd.Dir = inv.workDir
+		}
+		cmd.Env = append(append(os.Environ(), inv.env...), env...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return errors.New("Hook '" + hook + "' failed: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func newHookCommand(ctx gocontext.Context, windows bool, hook string) *exec.Cmd {
+	if windows {
+		return exec.CommandContext(ctx, "cmd", "/C", hook)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", hook)
+}
+
+// Resolves the variables seen by hooks. The outcome of the build is
+// exposed to hooks that run once the build has finished
+func hookEnv(inv invocation, finished bool, err error) []string {
+	env := []string{
+		"GUM_TOOL=" + inv.tool,
+		"GUM_EXECUTABLE=" + inv.executable,
+		"GUM_ROOT_DIR=" + inv.rootDir,
+		"GUM_BUILD_FILE=" + inv.buildFile,
+		"GUM_ARGS=" + strings.Join(inv.args.Args, " ")}
+
+	if finished {
+		status, code, ok := buildOutcome(err)
+		if !ok {
+			status = "not launched"
+		}
+		env = append(env, "GUM_EXIT_CODE="+strconv.Itoa(code), "GUM_BUILD_STATUS="+status)
+	}
+
+	return env
+}
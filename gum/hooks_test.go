@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLoadHooksConfig(t *testing.T) {
+	// given:
+	path, _ := filepath.Abs(filepath.Join("..", "tests", "hooks", ".gm.toml"))
+
+	// when:
+	config := ReadConfigFile(testContext{}, path)
+
+	// then:
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"hooks.before", strings.Join(config.hooks.before, ","), "docker compose up -d"},
+		{"hooks.after", strings.Join(config.hooks.after, ","), "docker compose down"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestHookEnv(t *testing.T) {
+	// given:
+	args := ParseArgs([]string{"clean", "build"})
+	inv := invocation{tool: "gradle", executable: "/p/gradlew", rootDir: "/p", buildFile: "/p/build.gradle", args: &args}
+
+	var checks = []struct {
+		title    string
+		finished bool
+		err      error
+		expected string
+	}{
+		{"Before", false, nil, "GUM_TOOL=gradle GUM_EXECUTABLE=/p/gradlew GUM_ROOT_DIR=/p GUM_BUILD_FILE=/p/build.gradle GUM_ARGS=clean build"},
+		{"After", true, nil, "GUM_TOOL=gradle GUM_EXECUTABLE=/p/gradlew GUM_ROOT_DIR=/p GUM_BUILD_FILE=/p/build.gradle GUM_ARGS=clean build GUM_EXIT_CODE=0 GUM_BUILD_STATUS=succeeded"},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := strings.Join(hookEnv(inv, check.finished, check.err), " ")
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestExecuteCommandHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	failing := filepath.Join(dir, "failing")
+	ioutil.WriteFile(failing, []byte("#!/bin/sh\necho build >> log\nexit 3\n"), 0755)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: dir,
+		homeDir:    dir}
+	config := newConfig()
+	config.setQuiet(true)
+	config.hooks.before = []string{"echo before $GUM_TOOL >> log"}
+	config.hooks.after = []string{"echo after $GUM_BUILD_STATUS $GUM_EXIT_CODE >> log"}
+	args := ParseArgs([]string{"build"})
+
+	// when:
+	err := executeCommand(context, config, invocation{tool: "make", executable: failing, rootDir: dir, workDir: dir, args: &args})
+
+	// then:
+	if ExitCode(err) != 3 {
+		t.Errorf("got %d, want 3", ExitCode(err))
+	}
+	log, _ := ioutil.ReadFile(filepath.Join(dir, "log"))
+	if string(log) != "before make\nbuild\nafter failed 3\n" {
+		t.Errorf("got %q, want before, build, and after hooks", string(log))
+	}
+
+	// when:
+	config.hooks.before = []string{"exit 1"}
+	err = executeCommand(context, config, invocation{tool: "make", executable: failing, rootDir: dir, workDir: dir, args: &args})
+
+	// then:
+	if err == nil || ExitCode(err) == 3 {
+		t.Errorf("got %v, want the build to be skipped", err)
+	}
+}
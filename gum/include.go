@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// Time given to fetch a remote include
+const includeTimeout = 10 * time.Second
+
+// Merges the files listed by the include key of the given config underneath it. Includes
+// listed later take precedence over earlier ones, and the including file over all of them.
+// Local includes of a project config are subject to the same signature policy as the project
+// config, p is nil otherwise. Returns false if an include is not a valid config file or if it
+// could not be verified in strict mode
+func resolveIncludes(context Context, t *toml.Tree, location string, remote bool, p *policy, visited map[string]bool) bool {
+	includes := toStringSlice(t.GetPath([]string{"include"}))
+	ok := true
+
+	for i := len(includes) - 1; i >= 0; i-- {
+		target := resolveIncludeLocation(location, includes[i])
+		if visited[target] {
+			continue
+		}
+		visited[target] = true
+
+		if !verifyIncludeSignature(context, p, target) {
+			ok = false
+			continue
+		}
+
+		it, err := loadInclude(context, target, remote)
+		if err != nil {
+			fmt.Fprintln(gumOutput, "WARNING: could not include "+target)
+			fmt.Fprintln(gumOutput, err)
+			continue
+		}
+		if !reportConfigProblems(validateConfigTree(target, it)) || !resolveIncludes(context, it, target, remote, p, visited) {
+			ok = false
+			continue
+		}
+		mergeTrees(t, it)
+	}
+
+	return ok
+}
+
+// Verifies the signature of a local include when required by policy. Returns false only
+// if verification fails in strict mode
+func verifyIncludeSignature(context Context, p *policy, location string) bool {
+	if p == nil || (p.configSignatures != "warn" && p.configSignatures != "strict") ||
+		isRemoteInclude(location) || !context.FileExists(location) {
+		return true
+	}
+
+	if !verifyConfigSignature(location, p.configKeys) {
+		if p.configSignatures == "strict" {
+			fmt.Fprintln(gumOutput, "Refusing to include unverified config "+location)
+			return false
+		}
+		fmt.Fprintln(gumOutput, "WARNING: using unverified include "+location)
+	}
+	return true
+}
+
+// Resolves an include relative to the location of the including file, which may be remote
+func resolveIncludeLocation(base string, include string) string {
+	if isRemoteInclude(include) || filepath.IsAbs(include) {
+		return include
+	}
+
+	if isRemoteInclude(base) {
+		u, err := url.Parse(base)
+		if err != nil {
+			return include
+		}
+		u.Path = path.Join(path.Dir(u.Path), include)
+		return u.String()
+	}
+
+	return filepath.Join(filepath.Dir(base), include)
+}
+
+func isRemoteInclude(location string) bool {
+	return strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://")
+}
+
+func loadInclude(context Context, location string, remote bool) (*toml.Tree, error) {
+	var doc []byte
+	var err error
+
+	if isRemoteInclude(location) {
+		if !remote {
+			return nil, errors.New("Remote includes are disabled. Set general.remoteIncludes = true in your user config")
+		}
+		doc, err = fetchInclude(context, location)
+	} else if !context.FileExists(location) {
+		return nil, errors.New("File not found")
+	} else {
+		doc, err = ioutil.ReadFile(location)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return parseConfigTree(location, doc)
+}
+
+// Fetches a remote include, keeping a copy in the cache dir that is used when the fetch fails
+func fetchInclude(context Context, location string) ([]byte, error) {
+	hash := sha256.Sum256([]byte(location))
+	cached := filepath.Join(resolveCacheDir(context), "includes", hex.EncodeToString(hash[:8])+path.Ext(location))
+
+	doc, err := downloadInclude(location)
+	if err == nil {
+		os.MkdirAll(filepath.Dir(cached), 0755)
+		ioutil.WriteFile(cached, doc, 0644)
+		return doc, nil
+	}
+
+	if context.FileExists(cached) {
+		fmt.Fprintln(gumOutput, "WARNING: using cached copy of "+location)
+		return ioutil.ReadFile(cached)
+	}
+	return nil, err
+}
+
+func downloadInclude(location string) ([]byte, error) {
+	client := &http.Client{Timeout: includeTimeout}
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(location + " responded with " + resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Copies the entries of src missing in dst, merging tables recursively
+func mergeTrees(dst *toml.Tree, src *toml.Tree) {
+	for _, key := range src.Keys() {
+		if key == "include" {
+			continue
+		}
+
+		sv := src.GetPath([]string{key})
+		dv := dst.GetPath([]string{key})
+		if dv == nil {
+			dst.SetPath([]string{key}, sv)
+			continue
+		}
+
+		dt, dok := dv.(*toml.Tree)
+		st, sok := sv.(*toml.Tree)
+		if dok && sok {
+			mergeTrees(dt, st)
+		}
+	}
+}
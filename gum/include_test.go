@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestReadConfigWithIncludes(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	os.MkdirAll(filepath.Join(dir, "project"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "gum-common.toml"), []byte(`include = ["project/.gm.toml", "gum-base.yml"]
+
+[general]
+debug = true
+
+[gradle.mappings]
+build = "check"
+"^it(.*)$" = "integrationTest$1"
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "gum-base.yml"), []byte("general:\n  debug: false\n  timeout: 10m\n"), 0644)
+	path := filepath.Join(dir, "project", ".gm.toml")
+	ioutil.WriteFile(path, []byte(`include = ["../gum-common.toml"]
+
+[gradle.mappings]
+build = "assemble"
+`), 0644)
+
+	// when:
+	config := ReadConfigFile(testContext{}, path)
+
+	// then:
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"general.debug", strconv.FormatBool(config.general.d.WithMaybeAsFalse()), "true"},
+		{"general.timeout", config.general.timeout, "10m"},
+		{"gradle.mappings.build", config.gradle.mappings["build"], "assemble"},
+		{"gradle.mappings.it", config.gradle.mappings["^it(.*)$"], "integrationTest$1"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestReadConfigWithRemoteIncludes(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[general]\ntimeout = \"45m\"\n"))
+	}))
+	path := filepath.Join(dir, ".gm.toml")
+	ioutil.WriteFile(path, []byte("include = [\""+server.URL+"/org-gum.toml\"]\n"), 0644)
+	context := testContext{env: map[string]string{"XDG_CACHE_HOME": filepath.Join(dir, "cache")}}
+
+	var checks = []struct {
+		title    string
+		remote   bool
+		offline  bool
+		expected string
+	}{
+		{"Disabled", false, false, ""},
+		{"Enabled", true, false, "45m"},
+		{"Cached", true, true, "45m"},
+	}
+
+	for _, check := range checks {
+		if check.offline {
+			server.Close()
+		}
+
+		// when:
+		config := readConfigFile(context, path, check.remote, nil)
+
+		// then:
+		if config.general.timeout != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, config.general.timeout, check.expected)
+		}
+	}
+}
+
+func TestReadConfigWithSignedIncludes(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+
+	public, private, _ := ed25519.GenerateKey(nil)
+	der, _ := x509.MarshalPKIXPublicKey(public)
+	keys := filepath.Join(dir, "keys.pem")
+	ioutil.WriteFile(keys, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644)
+	sign := func(path string, doc []byte) {
+		ioutil.WriteFile(path, doc, 0644)
+		ioutil.WriteFile(path+".sig", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, doc))), 0644)
+	}
+
+	path := filepath.Join(dir, ".gm.toml")
+	sign(path, []byte("include = [\"gum-common.toml\"]\n"))
+	include := filepath.Join(dir, "gum-common.toml")
+	doc := []byte("[general]\ntimeout = \"10m\"\n")
+
+	var checks = []struct {
+		title    string
+		mode     string
+		signed   bool
+		expected string
+	}{
+		{"Off", "off", false, "10m"},
+		{"Warn unsigned", "warn", false, "10m"},
+		{"Strict unsigned", "strict", false, ""},
+		{"Strict signed", "strict", true, "10m"},
+	}
+
+	for _, check := range checks {
+		os.Remove(include + ".sig")
+		if check.signed {
+			sign(include, doc)
+		} else {
+			ioutil.WriteFile(include, doc, 0644)
+		}
+		p := policy{configSignatures: check.mode, configKeys: keys}
+
+		// when:
+		config := readProjectConfigFile(testContext{}, p, path, false)
+
+		// then:
+		if config.general.timeout != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, config.general.timeout, check.expected)
+		}
+	}
+}
+
+func TestResolveIncludeLocation(t *testing.T) {
+	var checks = []struct {
+		base, include, expected string
+	}{
+		{filepath.Join("p", ".gm.toml"), "common.toml", filepath.Join("p", "common.toml")},
+		{filepath.Join("p", ".gm.toml"), "https://example.com/org-gum.toml", "https://example.com/org-gum.toml"},
+		{"https://example.com/gum/org-gum.toml", "base.toml", "https://example.com/gum/base.toml"},
+		{"https://example.com/gum/org-gum.toml", "../base.toml", "https://example.com/base.toml"},
+	}
+
+	for _, check := range checks {
+		if actual := resolveIncludeLocation(check.base, check.include); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.include, actual, check.expected)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Args suggested for the ci profile of the starter config, per tool
+var initProfileArgs = map[string]string{
+	"gradle": `["--no-daemon", "--console=plain"]`,
+	"maven":  `["--batch-mode", "--no-transfer-progress"]`,
+	"sbt":    `["-batch"]`,
+}
+
+// Handles "gm gum init [--force]", writing a starter config at the project's root
+func runInitCommand(context Context, args *ParsedArgs, params []string) int {
+	force, _ := hasOption("--force", params)
+	rootDir := resolveProjectRootDir(context)
+
+	if !force {
+		for _, name := range projectConfigFiles {
+			if path := filepath.Join(rootDir, name); context.FileExists(path) {
+				fmt.Fprintln(gumOutput, "Config already exists at "+path+". Use --force to overwrite it")
+				return -1
+			}
+		}
+	}
+
+	path := filepath.Join(rootDir, projectConfigFiles[0])
+	if err := writeConfigLines(path, formatInitConfig(resolveRunTool(context, args))); err != nil {
+		fmt.Fprintln(gumOutput, err)
+		return -1
+	}
+
+	fmt.Fprintln(gumOutput, "Config written to "+path)
+	return 0
+}
+
+// Formats the starter config for a project built with the given tool. Every setting is
+// commented out, thus the config has no effect until edited
+func formatInitConfig(tool string) []string {
+	if len(tool) == 0 {
+		tool = "gradle"
+	}
+	mappings := "gradle.mappings"
+	pattern := `"^it(.*)$" = "integrationTest$1"`
+	if tool == "maven" {
+		mappings = "maven.mappings"
+		pattern = `"^it(.*)$" = "failsafe:integration-test"`
+	}
+	profileArgs, ok := initProfileArgs[tool]
+	if !ok {
+		profileArgs = "[]"
+	}
+
+	return []string{
+		"# Gum project config. Settings defined here override those of your user config.",
+		"# Check the effective configuration with \"gm gum config\" and validate this file",
+		"# with \"gm gum config --validate\".",
+		"",
+		"[general]",
+		"# kills the build if it does not finish in time, same as passing -gt",
+		"# timeout = \"30m\"",
+		"",
+		"# goal/task names replaced before running the build. Keys starting with ^ are",
+		"# regular expressions whose replacement may refer to capture groups",
+		"[" + mappings + "]",
+		"# " + pattern,
+		"",
+		"# aliases expand to multiple arguments, i.e, \"gm ci\" runs \"gm clean build\"",
+		"[aliases]",
+		"# ci = \"clean build\"",
+		"",
+		"# profiles contribute args and environment variables to the build,",
+		"# activated with -gp <name> or $GUM_PROFILE",
+		"# [profiles.ci." + tool + "]",
+		"# args = " + profileArgs,
+		"# [profiles.ci.env]",
+		"# CI = \"true\""}
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+)
+
+var commentedSetting = regexp.MustCompile(`(?m)^# ((\[|"|\w+ = ).*)$`)
+
+func TestFormatInitConfig(t *testing.T) {
+	for _, tool := range []string{"", "gradle", "maven", "make"} {
+		// when:
+		doc := strings.Join(formatInitConfig(tool), "\n")
+		tree, err := toml.Load(doc)
+
+		// then:
+		if err != nil {
+			t.Errorf("%s: %v", tool, err)
+			continue
+		}
+		if problems := validateConfigTree(".gm.toml", tree); len(problems) > 0 {
+			t.Errorf("%s: got %s, want no problems", tool, problems[0])
+		}
+		// uncommenting every example must yield a valid config too
+		tree, err = toml.Load(commentedSetting.ReplaceAllString(doc, "$1"))
+		if err != nil {
+			t.Errorf("%s: %v", tool, err)
+			continue
+		}
+		for _, p := range validateConfigTree(".gm.toml", tree) {
+			t.Errorf("%s: uncommented example: %s", tool, p)
+		}
+	}
+}
+
+func TestInitCommand(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	context := testContext{workingDir: dir, homeDir: dir}
+	args := ParseArgs([]string{"gum", "init"})
+
+	var checks = []struct {
+		title    string
+		params   []string
+		expected int
+	}{
+		{"Init", []string{}, 0},
+		{"Exists", []string{}, -1},
+		{"Force", []string{"--force"}, 0},
+	}
+
+	for _, check := range checks {
+		// when:
+		code := runInitCommand(context, &args, check.params)
+
+		// then:
+		if code != check.expected {
+			t.Errorf("%s: got %d, want %d", check.title, code, check.expected)
+		}
+		if !context.FileExists(filepath.Join(dir, ".gm.toml")) {
+			t.Errorf("%s: expected config to be written", check.title)
+		}
+	}
+}
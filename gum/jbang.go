@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -37,6 +36,9 @@ const JshExt = ".jsh"
 // JarExt the .jar file extension
 const JarExt = ".jar"
 
+// jbangDirectives lists the comment directives understood by JBang
+var jbangDirectives = []string{"//DEPS", "//JAVA", "//SOURCES", "//FILES", "//REPOS", "//JAVA_OPTIONS"}
+
 // JbangCommand defines an executable Jbang command
 type JbangCommand struct {
 	context            Context
@@ -48,9 +50,9 @@ type JbangCommand struct {
 }
 
 // Execute executes the given command
-func (c JbangCommand) Execute() {
+func (c JbangCommand) Execute() int {
 	c.doConfigureJbang()
-	c.doExecuteJbang()
+	return ExitCode(c.doExecuteJbang())
 }
 
 func (c *JbangCommand) doConfigureJbang() {
@@ -65,8 +67,11 @@ func (c *JbangCommand) doConfigureJbang() {
 		c.config.setDebug(debug)
 	}
 	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
 	oargs := c.args.Args
 
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "jbang"))
 	args = appendSafe(args, c.args.Tool)
 
 	if len(c.explicitSourceFile) > 0 {
@@ -79,17 +84,28 @@ func (c *JbangCommand) doConfigureJbang() {
 	c.args.Args = appendSafe(args, oargs)
 
 	c.debugJbang(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
 
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
 	}
 }
 
-func (c *JbangCommand) doExecuteJbang() {
-	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Run()
+func (c *JbangCommand) doExecuteJbang() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *JbangCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *JbangCommand) invocation() invocation {
+	return invocation{
+		tool:       "jbang",
+		executable: c.executable,
+		args:       c.args}
 }
 
 func (c *JbangCommand) debugConfig() {
@@ -101,13 +117,13 @@ func (c *JbangCommand) debugConfig() {
 
 func (c *JbangCommand) debugJbang(config *Config, oargs []string) {
 	if c.config.general.debug {
-		fmt.Println("discovery          = ", config.jbang.discovery)
-		fmt.Println("pwd                = ", c.context.GetWorkingDir())
-		fmt.Println("sourceFile         = ", c.sourceFile)
-		fmt.Println("explicitSourceFile = ", c.explicitSourceFile)
-		fmt.Println("original args      = ", oargs)
-		fmt.Println("actual args        = ", c.args.Args)
-		fmt.Println("")
+		fmt.Fprintln(gumOutput, "discovery          = ", config.jbang.discovery)
+		fmt.Fprintln(gumOutput, "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(gumOutput, "sourceFile         = ", c.sourceFile)
+		fmt.Fprintln(gumOutput, "explicitSourceFile = ", c.explicitSourceFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
 	}
 }
 
@@ -155,8 +171,8 @@ func FindJbang(context Context, args *ParsedArgs) *JbangCommand {
 
 	if noSourceFile != nil {
 		if context.IsExplicit() {
-			fmt.Println("No jbang project found")
-			fmt.Println()
+			fmt.Fprintln(gumOutput, "No jbang project found")
+			fmt.Fprintln(gumOutput)
 			context.Exit(-1)
 		}
 		return nil
@@ -182,20 +198,20 @@ func resolveJbangRootDir(context Context,
 
 func warnNoJbangWrapper(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s set up for this project. ", resolveJbangWrapperExec(context))
-		fmt.Println()
-		fmt.Println("Please consider setting one up.")
-		fmt.Println("(https://github.com/jbangdev)")
-		fmt.Println()
+		fmt.Fprintf(gumOutput, "No %s set up for this project. ", resolveJbangWrapperExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "Please consider setting one up.")
+		fmt.Fprintln(gumOutput, "(https://github.com/jbangdev)")
+		fmt.Fprintln(gumOutput)
 	}
 }
 
 func warnNoJbang(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s found in path. Please install jbang.", resolveJbangExec(context))
-		fmt.Println()
-		fmt.Println("(https://github.com/jbangdev)")
-		fmt.Println()
+		fmt.Fprintf(gumOutput, "No %s found in path. Please install jbang.", resolveJbangExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://github.com/jbangdev)")
+		fmt.Fprintln(gumOutput)
 	}
 }
 
@@ -224,11 +240,51 @@ func findJbangWrapperExec(context Context, dir string) (string, error) {
 	}
 
 	path := filepath.Join(dir, wrapper)
-	if context.FileExists(path) {
+	if isRegularFile(path) {
 		return filepath.Abs(path)
 	}
 
-	return "", errors.New(wrapper + " not found")
+	if isSearchBoundary(context, dir) {
+		return "", errors.New(wrapper + " not found")
+	}
+
+	return findJbangWrapperExec(context, parentdir)
+}
+
+// Checks if the target of args is a local source file with JBang directives,
+// such as //DEPS or //JAVA, in which case JBang should run it regardless of
+// other build files found in the project
+func isJbangScript(context Context, args *ParsedArgs) bool {
+	found, file := findExplicitJbangSourceFile(context.GetWorkingDir(), args.Args)
+	if !found || !context.FileExists(file) {
+		return false
+	}
+	if !strings.HasSuffix(file, JavaExt) && !strings.HasSuffix(file, JshExt) {
+		return false
+	}
+	return hasJbangDirectives(file)
+}
+
+// Checks if a source file contains JBang directives
+func hasJbangDirectives(file string) bool {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return false
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "///usr/bin/env jbang") {
+			return true
+		}
+		for _, directive := range jbangDirectives {
+			if strings.HasPrefix(line, directive+" ") {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 func isLaunchableSource(source string) bool {
@@ -331,7 +387,7 @@ func findJbangSourceFile(context Context, dir string, config *Config, args []str
 				file, exists = choices[JarExt]
 				break
 			default:
-				fmt.Println("Unsupported extension: " + choice)
+				fmt.Fprintln(gumOutput, "Unsupported extension: "+choice)
 				os.Exit(-1)
 			}
 
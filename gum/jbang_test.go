@@ -273,3 +273,73 @@ func TestJbangWithoutExecutables(t *testing.T) {
 		t.Error("Expected a nil command but got something")
 	}
 }
+
+func TestJbangScriptWithDirectives(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "jbang", "script-in-project", "scripts"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd}
+
+	var checks = []struct {
+		title    string
+		args     []string
+		expected bool
+	}{
+		{"Directives", []string{"-gq", "hello.java", "foo"}, true},
+		{"NoDirectives", []string{"-gq", "plain.java"}, false},
+		{"Missing", []string{"-gq", "missing.java"}, false},
+		{"Task", []string{"-gq", "build"}, false},
+	}
+
+	for _, check := range checks {
+		// when:
+		args := ParseArgs(check.args)
+		actual := isJbangScript(context, &args)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %v, want %v", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestJbangWrapperInParent(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "jbang", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "jbang", "script-in-project"))
+	pwd := filepath.Join(root, "scripts")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "hello.java"})
+	cmd := FindJbang(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(root, "jbang")},
+		{"ExplicitSourceFile", cmd.explicitSourceFile, filepath.Join(pwd, "hello.java")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LeinCommand defines an executable Leiningen command
+type LeinCommand struct {
+	context       Context
+	config        *Config
+	executable    string
+	args          *ParsedArgs
+	rootBuildFile string
+	buildFile     string
+}
+
+// Execute executes the given command
+func (c LeinCommand) Execute() int {
+	c.doConfigureLein()
+	return ExitCode(c.doExecuteLein())
+}
+
+func (c *LeinCommand) doConfigureLein() {
+	args := make([]string, 0)
+
+	buildFile := c.resolveBuildFile()
+	banner := make([]string, 0)
+	banner = append(banner, "Using Leiningen at '"+c.executable+"'")
+	banner = append(banner, "to run buildFile '"+buildFile+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "lein"))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugLein(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *LeinCommand) doExecuteLein() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the invocation of the configured command.
+// Resolves the config of the command
+func (c *LeinCommand) configuration() *Config {
+	return c.config
+}
+
+// Leiningen reads project.clj from the current directory thus it's launched from there
+func (c *LeinCommand) invocation() invocation {
+	buildFile := c.resolveBuildFile()
+	return invocation{
+		tool:       "lein",
+		executable: c.executable,
+		rootDir:    filepath.Dir(buildFile),
+		buildFile:  buildFile,
+		workDir:    filepath.Dir(buildFile),
+		args:       c.args}
+}
+
+// Resolves the build file used by the invocation
+func (c *LeinCommand) resolveBuildFile() string {
+	if c.args.HasGumFlag("gn") && len(c.buildFile) > 0 {
+		return c.buildFile
+	}
+	return c.rootBuildFile
+}
+
+func (c *LeinCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *LeinCommand) debugLein(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "nearest            = ", c.args.HasGumFlag("gn"))
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "rootBuildFile      = ", c.rootBuildFile)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindLein finds and executes Leiningen
+func FindLein(context Context, args *ParsedArgs) *LeinCommand {
+	pwd := context.GetWorkingDir()
+
+	lein, noLein := findLeinExec(context)
+	rootBuildFile, noRootBuildFile := findLeinBuildFile(context, filepath.Join(pwd, ".."))
+	buildFile, noBuildFile := findLeinBuildFile(context, pwd)
+
+	if noBuildFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No Leiningen project found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	if noRootBuildFile != nil {
+		rootBuildFile = buildFile
+	}
+
+	config := ReadConfig(context, filepath.Dir(rootBuildFile))
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	if noLein != nil {
+		warnNoLein(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &LeinCommand{
+		context:       context,
+		config:        config,
+		executable:    lein,
+		args:          args,
+		rootBuildFile: rootBuildFile,
+		buildFile:     buildFile}
+}
+
+func warnNoLein(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s found in path. Please install Leiningen.", resolveLeinExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://leiningen.org/#install)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the lein executable
+func findLeinExec(context Context) (string, error) {
+	lein := resolveLeinExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], lein)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(lein + " not found")
+}
+
+// Finds the nearest project.clj
+func findLeinBuildFile(context Context, dir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	if parentdir == dir {
+		return "", errors.New("Did not find project.clj")
+	}
+
+	path := filepath.Join(dir, "project.clj")
+	if context.FileExists(path) {
+		return filepath.Abs(path)
+	}
+
+	if isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find project.clj")
+	}
+
+	return findLeinBuildFile(context, parentdir)
+}
+
+// Resolves the lein executable (OS dependent)
+func resolveLeinExec(context Context) string {
+	if context.IsWindows() {
+		return "lein.bat"
+	}
+	return "lein"
+}
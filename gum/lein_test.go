@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLeinSingle(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "lein", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "lein", "single"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "test"})
+	cmd := FindLein(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureLein()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "lein")},
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(pwd, "project.clj")},
+		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "project.clj")},
+		{"WorkDir", cmd.invocation().workDir, pwd},
+		{"Args", cmd.args.Args[0], "test"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestLeinParent(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "lein", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "lein", "parent"))
+	pwd := filepath.Join(root, "child")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindLein(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(root, "project.clj")},
+		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "project.clj")},
+		{"WorkDir", cmd.invocation().workDir, root},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestLeinParentNearest(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "lein", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "lein", "parent", "child"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "-gn"})
+	cmd := FindLein(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	if cmd.invocation().workDir != pwd {
+		t.Errorf("WorkDir: got %s, want %s", cmd.invocation().workDir, pwd)
+	}
+}
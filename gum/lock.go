@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pelletier/go-toml"
+)
+
+// Tools whose versions are recorded in gum.lock, in order
+var lockedTools = []string{"gradle", "maven", "java"}
+
+// Handles "gm gum lock [--check [--strict]]". Records the Gradle/Maven and JDK versions
+// used by the project in gum.lock, or checks the live versions against it
+func runLockCommand(context Context, args *ParsedArgs, params []string) int {
+	check, params := hasOption("--check", params)
+	strict, _ := hasOption("--strict", params)
+
+	path := filepath.Join(resolveProjectRootDir(context), "gum.lock")
+	versions := resolveLockVersions(context, resolveRunTool(context, args))
+
+	if !check {
+		if err := writeLockFile(path, versions); err != nil {
+			fmt.Fprintln(gumOutput, err)
+			return -1
+		}
+		fmt.Fprintln(gumOutput, "Versions written to "+path)
+		for _, tool := range lockedTools {
+			if version, ok := versions[tool]; ok {
+				fmt.Fprintln(gumOutput, "  "+tool+" = "+version)
+			}
+		}
+		return 0
+	}
+
+	locked, err := readLockFile(path)
+	if err != nil {
+		fmt.Fprintln(gumOutput, "Could not read "+path)
+		fmt.Fprintln(gumOutput, err)
+		return -1
+	}
+
+	deviations := findLockDeviations(locked, versions)
+	for _, d := range deviations {
+		fmt.Fprintln(gumOutput, d)
+	}
+	if len(deviations) > 0 && strict {
+		return -1
+	}
+	return 0
+}
+
+// Resolves the versions of the given build tool (if Gradle or Maven) and the JDK
+func resolveLockVersions(context Context, tool string) map[string]string {
+	versions := make(map[string]string)
+	probe := ParseArgs([]string{})
+
+	var executable string
+	switch tool {
+	case "gradle":
+		if c := FindGradle(context, &probe); c != nil {
+			executable = c.executable
+		}
+	case "maven":
+		if c := FindMaven(context, &probe); c != nil {
+			executable = c.executable
+		}
+	}
+
+	if len(executable) > 0 {
+		if version := resolveToolVersion(context, tool, executable); len(version) > 0 {
+			versions[tool] = version
+		}
+	}
+	if version := resolveJavaVersion(context); len(version) > 0 {
+		versions["java"] = version
+	}
+
+	return versions
+}
+
+// Finds live versions that differ from locked versions
+func findLockDeviations(locked map[string]string, live map[string]string) []string {
+	deviations := make([]string, 0)
+	for _, tool := range lockedTools {
+		expected, ok := locked[tool]
+		if !ok {
+			continue
+		}
+		actual, ok := live[tool]
+		if !ok {
+			deviations = append(deviations, "WARNING: "+tool+" "+expected+" is locked but could not be found")
+		} else if actual != expected {
+			deviations = append(deviations, "WARNING: "+tool+" "+actual+" does not match locked version "+expected)
+		}
+	}
+	return deviations
+}
+
+func readLockFile(path string) (map[string]string, error) {
+	versions := make(map[string]string)
+
+	doc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return versions, err
+	}
+
+	t, err := toml.LoadBytes(doc)
+	if err != nil {
+		return versions, err
+	}
+
+	tt := t.Get("versions")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		for _, tool := range lockedTools {
+			v := table.Get(tool)
+			if v != nil {
+				versions[tool] = v.(string)
+			}
+		}
+	}
+
+	return versions, nil
+}
+
+func writeLockFile(path string, versions map[string]string) error {
+	lines := []string{
+		"# Generated by gm gum lock",
+		"[versions]"}
+	for _, tool := range lockedTools {
+		if version, ok := versions[tool]; ok {
+			lines = append(lines, tool+" = "+strconv.Quote(version))
+		}
+	}
+	return writeConfigLines(path, lines)
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLockFile(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gum.lock")
+
+	// when:
+	writeLockFile(path, map[string]string{"gradle": "7.0", "java": "11.0.11"})
+	locked, err := readLockFile(path)
+	deviations := findLockDeviations(locked, map[string]string{"gradle": "7.0.2"})
+
+	// then:
+	if err != nil {
+		t.Error(err)
+	}
+	if locked["gradle"] != "7.0" || locked["java"] != "11.0.11" {
+		t.Errorf("Unexpected locked versions %v", locked)
+	}
+	if len(deviations) != 2 ||
+		!strings.Contains(deviations[0], "gradle 7.0.2 does not match locked version 7.0") ||
+		!strings.Contains(deviations[1], "java 11.0.11 is locked but could not be found") {
+		t.Errorf("Unexpected deviations %v", deviations)
+	}
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"testing"
+)
+
+func TestLowerPriority(t *testing.T) {
+	// given:
+	own, _ := syscall.Getpriority(syscall.PRIO_PROCESS, 0)
+	cmd := exec.Command("sleep", "1")
+	var priority, ioPriority int
+	started := func(cmd *exec.Cmd) {
+		if err := lowerPriority(cmd); err != nil {
+			t.Skip("requires permission to change process priority")
+		}
+		// the kernel reports 20 - nice
+		prio, _ := syscall.Getpriority(syscall.PRIO_PROCESS, cmd.Process.Pid)
+		priority = 20 - prio
+		r, _, _ := syscall.Syscall(syscall.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(cmd.Process.Pid), 0)
+		ioPriority = int(r)
+	}
+
+	// when:
+	_, err := runForwardingSignals(context.Background(), cmd, 0, nil, started)
+
+	// then:
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if priority != 10 {
+		t.Errorf("child niceness: got %d, want 10", priority)
+	}
+	if ioPriority != ioprioClassBE<<ioprioClassShift|7 {
+		t.Errorf("child I/O priority: got %d, want %d", ioPriority, ioprioClassBE<<ioprioClassShift|7)
+	}
+	if actual, _ := syscall.Getpriority(syscall.PRIO_PROCESS, 0); actual != own {
+		t.Errorf("gum priority changed: got %d, want %d", actual, own)
+	}
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package gum
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// Nothing to prepare, the priority of the child process is lowered once started
+func prepareLowPriority(cmd *exec.Cmd) {}
+
+// Lowers the CPU and I/O priority of a started child process. Threads and
+// processes spawned by the child afterwards inherit them
+func lowerPriority(cmd *exec.Cmd) error {
+	if err := syscall.Setpriority(syscall.PRIO_PROCESS, cmd.Process.Pid, 10); err != nil {
+		return err
+	}
+	return lowerIOPriority(cmd.Process.Pid)
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Makefile names, in the order GNU make looks for them
+var makeFiles = []string{"GNUmakefile", "makefile", "Makefile"}
+
+// MakeCommand defines an executable make command
+type MakeCommand struct {
+	context    Context
+	config     *Config
+	rootdir    string
+	executable string
+	args       *ParsedArgs
+	buildFile  string
+}
+
+// Execute executes the given command
+func (c MakeCommand) Execute() int {
+	c.doConfigureMake()
+	return ExitCode(c.doExecuteMake())
+}
+
+func (c *MakeCommand) doConfigureMake() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using make at '"+c.executable+"'")
+	banner = append(banner, "to run buildFile '"+c.buildFile+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "make"))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugMake(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *MakeCommand) doExecuteMake() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the invocation of the configured command.
+// Resolves the config of the command
+func (c *MakeCommand) configuration() *Config {
+	return c.config
+}
+
+// Makefiles use paths relative to their directory thus make is launched from there
+func (c *MakeCommand) invocation() invocation {
+	return invocation{
+		tool:       "make",
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		buildFile:  c.buildFile,
+		workDir:    c.rootdir,
+		args:       c.args}
+}
+
+func (c *MakeCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *MakeCommand) debugMake(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindMake finds and executes make
+func FindMake(context Context, args *ParsedArgs) *MakeCommand {
+	pwd := context.GetWorkingDir()
+
+	buildFile, noBuildFile := findMakeBuildFile(context, pwd, resolveSearchBoundary(context, pwd))
+	if noBuildFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No Makefile found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	rootdir := filepath.Dir(buildFile)
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	executable, noMake := findMakeExec(context)
+	if noMake != nil {
+		warnNoMake(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &MakeCommand{
+		context:    context,
+		config:     config,
+		rootdir:    rootdir,
+		executable: executable,
+		args:       args,
+		buildFile:  buildFile}
+}
+
+func warnNoMake(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s found in path. Please install make.", resolveMakeExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://www.gnu.org/software/make/)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the nearest Makefile, stopping at the given boundary dir
+func findMakeBuildFile(context Context, dir string, boundary string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	for _, name := range makeFiles {
+		path := filepath.Join(dir, name)
+		if context.FileExists(path) {
+			return filepath.Abs(path)
+		}
+	}
+
+	abs, _ := filepath.Abs(dir)
+	if abs == boundary || parentdir == dir || isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find Makefile")
+	}
+
+	return findMakeBuildFile(context, parentdir, boundary)
+}
+
+// Finds the make executable
+func findMakeExec(context Context) (string, error) {
+	mk := resolveMakeExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], mk)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(mk + " not found")
+}
+
+// Resolves the make executable (OS dependent)
+func resolveMakeExec(context Context) string {
+	if context.IsWindows() {
+		return "make.exe"
+	}
+	return "make"
+}
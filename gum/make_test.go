@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMakeStopsAtVcsRoot(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	repo := filepath.Join(dir, "repo")
+	native := filepath.Join(repo, "native")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(filepath.Join(repo, ".git"), 0755)
+	os.MkdirAll(filepath.Join(repo, "module"), 0755)
+	os.MkdirAll(filepath.Join(native, "src"), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "make"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "Makefile"), []byte("all:\n"), 0644)
+	ioutil.WriteFile(filepath.Join(native, "Makefile"), []byte("all:\n"), 0644)
+
+	var checks = []struct {
+		title, pwd, expected string
+	}{
+		{"Nearest", filepath.Join(native, "src"), filepath.Join(native, "Makefile")},
+		{"OutsideOfVcsRoot", filepath.Join(repo, "module"), ""},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: check.pwd,
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs([]string{"-gq", "all"})
+		cmd := FindMake(context, &args)
+
+		// then:
+		actual := ""
+		if cmd != nil {
+			actual = cmd.buildFile
+			if cmd.invocation().workDir != native {
+				t.Errorf("%s: got %s, want %s", check.title, cmd.invocation().workDir, native)
+			}
+		}
+		if actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestCMakeRootProject(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	repo := filepath.Join(dir, "repo")
+	lib := filepath.Join(repo, "lib")
+	os.MkdirAll(bin, 0755)
+	os.MkdirAll(filepath.Join(repo, ".git"), 0755)
+	os.MkdirAll(filepath.Join(lib, "src"), 0755)
+	ioutil.WriteFile(filepath.Join(bin, "cmake"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "CMakeLists.txt"), []byte("project(unrelated)\n"), 0644)
+	ioutil.WriteFile(filepath.Join(repo, "CMakeLists.txt"), []byte("project(app)\nadd_subdirectory(lib)\n"), 0644)
+	ioutil.WriteFile(filepath.Join(lib, "CMakeLists.txt"), []byte("add_library(lib src/lib.c)\n"), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: filepath.Join(lib, "src"),
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindCMake(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureCMake()
+	if cmd.buildFile != filepath.Join(repo, "CMakeLists.txt") {
+		t.Errorf("BuildFile: got %s, want %s", cmd.buildFile, filepath.Join(repo, "CMakeLists.txt"))
+	}
+	if actual := strings.Join(cmd.args.Args, " "); actual != "-S . -B build" {
+		t.Errorf("Args: got %s, want -S . -B build", actual)
+	}
+
+	// when:
+	os.MkdirAll(filepath.Join(repo, "build"), 0755)
+	ioutil.WriteFile(filepath.Join(repo, "build", "CMakeCache.txt"), []byte(""), 0644)
+	args = ParseArgs([]string{"-gq"})
+	cmd = FindCMake(context, &args)
+	cmd.doConfigureCMake()
+
+	// then:
+	if actual := strings.Join(cmd.args.Args, " "); actual != "--build build" {
+		t.Errorf("Args: got %s, want --build build", actual)
+	}
+}
@@ -17,10 +17,11 @@
 package gum
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -34,12 +35,20 @@ type MavenCommand struct {
 	buildFile         string
 	explicitBuildFile string
 	rootBuildFile     string
+	projectBaseDir    string
 }
 
 // Execute executes the given command
-func (c MavenCommand) Execute() {
+func (c MavenCommand) Execute() int {
 	c.doConfigureMaven()
-	c.doExecuteMaven()
+	return ExitCode(c.doExecuteMaven())
+}
+
+// ExecuteContext executes the given command, killing the build along with its process group
+// once ctx is done. Use ExitCode to resolve the exit code matching the returned error
+func (c MavenCommand) ExecuteContext(ctx context.Context) error {
+	c.doConfigureMaven()
+	return executeCommandContext(ctx, c.context, c.config, c.invocation())
 }
 
 func (c *MavenCommand) doConfigureMaven() {
@@ -55,12 +64,20 @@ func (c *MavenCommand) doConfigureMaven() {
 		c.config.setDebug(debug)
 	}
 	if skipReplace {
-		c.config.gradle.setReplace(!skipReplace)
+		c.config.maven.setReplace(!skipReplace)
 	}
 	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
 	otargs := c.args.Tool
 	oargs := c.args.Args
 	rtargs, rargs := replaceMavenGoals(c.config, c.args)
+	if c.config.maven.replace {
+		rargs = expandTaskAbbreviations(c.context, invocation{tool: "maven"}, rargs)
+	}
+	module := c.resolveModule()
+	c.projectBaseDir = findMavenProjectBaseDir(c.context, filepath.Dir(c.resolveBuildFile()))
+	args = appendSafe(args, c.resolveProjectBaseDirArgs())
 
 	if len(c.explicitBuildFile) > 0 {
 		args = append(args, "-f")
@@ -73,24 +90,89 @@ func (c *MavenCommand) doConfigureMaven() {
 	} else if len(c.rootBuildFile) > 0 {
 		args = append(args, "-f")
 		args = append(args, c.rootBuildFile)
-		banner = append(banner, "to run buildFile '"+c.rootBuildFile+"':")
+		if len(module) > 0 {
+			args = append(args, "-pl")
+			args = append(args, module)
+			if c.config.maven.alsoMake {
+				args = append(args, "-am")
+			}
+			banner = append(banner, "to run module '"+module+"' of buildFile '"+c.rootBuildFile+"':")
+		} else {
+			banner = append(banner, "to run buildFile '"+c.rootBuildFile+"':")
+		}
 	}
 
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "maven"))
 	args = appendSafe(args, rtargs)
 	c.args.Args = appendSafe(args, rargs)
 
-	c.debugMaven(otargs, oargs, rtargs, rargs)
+	c.debugMaven(otargs, oargs, rtargs, rargs, module)
+	showArgsDiff(c.config, c.args, input)
 
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
 	}
 }
 
-func (c *MavenCommand) doExecuteMaven() {
-	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Run()
+func (c *MavenCommand) doExecuteMaven() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *MavenCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *MavenCommand) invocation() invocation {
+	buildFile := c.resolveBuildFile()
+	return invocation{
+		tool:       "maven",
+		executable: c.executable,
+		rootDir:    filepath.Dir(buildFile),
+		buildFile:  buildFile,
+		args:       c.args}
+}
+
+// Resolves maven.multiModuleProjectDirectory when plain mvn runs from a dir other than the
+// project base dir, where .mvn/maven.config is read from. Wrappers and mvnd resolve the base
+// dir on their own. Returns nothing if args already set it
+func (c *MavenCommand) resolveProjectBaseDirArgs() []string {
+	if len(c.projectBaseDir) == 0 ||
+		filepath.Base(c.executable) != resolveMavenExec(c.context) ||
+		hasArgPrefix(appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args), "-Dmaven.multiModuleProjectDirectory=") {
+		return nil
+	}
+
+	pwd, _ := filepath.Abs(c.context.GetWorkingDir())
+	if pwd == c.projectBaseDir {
+		return nil
+	}
+	return []string{"-Dmaven.multiModuleProjectDirectory=" + c.projectBaseDir}
+}
+
+// Resolves the build file used by the invocation
+func (c *MavenCommand) resolveBuildFile() string {
+	if len(c.explicitBuildFile) > 0 {
+		return c.explicitBuildFile
+	} else if c.args.HasGumFlag("gn") && len(c.buildFile) > 0 {
+		return c.buildFile
+	}
+	return c.rootBuildFile
+}
+
+// Resolves the selector (such as :core) of the module at the working dir when it belongs to the
+// reactor of the root build file and maven.projectList is enabled. Returns an empty string otherwise
+func (c *MavenCommand) resolveModule() string {
+	if !c.config.maven.projectList ||
+		c.args.HasGumFlag("gn") ||
+		len(c.explicitBuildFile) > 0 ||
+		len(c.buildFile) == 0 ||
+		c.buildFile == c.rootBuildFile ||
+		hasProjectListArg(c.args) {
+		return ""
+	}
+	return resolveMavenModule(c.rootBuildFile, c.buildFile)
 }
 
 func (c *MavenCommand) debugConfig() {
@@ -100,24 +182,33 @@ func (c *MavenCommand) debugConfig() {
 	}
 }
 
-func (c *MavenCommand) debugMaven(otargs []string, oargs []string, rtargs []string, rargs []string) {
+func (c *MavenCommand) debugMaven(otargs []string, oargs []string, rtargs []string, rargs []string, module string) {
 	if c.config.general.debug {
-		fmt.Println("nearest            = ", c.args.HasGumFlag("gn"))
-		fmt.Println("replace            = ", c.config.maven.replace)
-		fmt.Println("pwd                = ", c.context.GetWorkingDir())
-		fmt.Println("rootBuildFile      = ", c.rootBuildFile)
-		fmt.Println("buildFile          = ", c.buildFile)
-		fmt.Println("explicitBuildFile  = ", c.explicitBuildFile)
-		fmt.Println("original tool args = ", otargs)
+		fmt.Fprintln(gumOutput, "nearest            = ", c.args.HasGumFlag("gn"))
+		fmt.Fprintln(gumOutput, "replace            = ", c.config.maven.replace)
+		fmt.Fprintln(gumOutput, "daemon             = ", c.config.maven.daemon || c.args.HasGumFlag("gmd"))
+		fmt.Fprintln(gumOutput, "projectList        = ", c.config.maven.projectList)
+		fmt.Fprintln(gumOutput, "module             = ", module)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(gumOutput, "rootBuildFile      = ", c.rootBuildFile)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "explicitBuildFile  = ", c.explicitBuildFile)
+		fmt.Fprintln(gumOutput, "original tool args = ", otargs)
 		if c.config.maven.replace {
-			fmt.Println("replaced tool args = ", rtargs)
+			fmt.Fprintln(gumOutput, "replaced tool args = ", rtargs)
 		}
-		fmt.Println("original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
 		if c.config.maven.replace {
-			fmt.Println("replaced args      = ", rargs)
+			fmt.Fprintln(gumOutput, "replaced args      = ", rargs)
 		}
-		fmt.Println("actual args        = ", c.args.Args)
-		fmt.Println("")
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "projectBaseDir     = ", c.projectBaseDir)
+		if len(c.projectBaseDir) > 0 {
+			fmt.Fprintln(gumOutput, "maven.config       = ", readMavenConfigFile(filepath.Join(c.projectBaseDir, ".mvn", "maven.config")))
+			fmt.Fprintln(gumOutput, "jvm.config         = ", readMavenConfigFile(filepath.Join(c.projectBaseDir, ".mvn", "jvm.config")))
+		}
+		fmt.Fprintln(gumOutput, "")
 	}
 }
 
@@ -133,12 +224,15 @@ func replaceMavenGoals(config *Config, args *ParsedArgs) ([]string, []string) {
 func FindMaven(context Context, args *ParsedArgs) *MavenCommand {
 	pwd := context.GetWorkingDir()
 
-	mvnw, noWrapper := findMavenWrapperExec(context, pwd)
+	walk := walkUp(context, pwd)
+	mvnw, noWrapper := findMavenWrapperExec(walk, pwd)
 	mvn, noMaven := findMavenExec(context)
+	mvnd, noDaemon := findMavenDaemonExec(context)
 	explicitBuildFileSet, explicitBuildFile := findExplicitMavenBuildFile(args)
+	explicitBuildFile = resolveRealPath(context, explicitBuildFile)
 
-	rootBuildFile, noRootBuildFile := findMavenRootFile(context, filepath.Join(pwd, ".."))
-	buildFile, noBuildFile := findMavenBuildFile(context, pwd)
+	rootBuildFile, noRootBuildFile := findMavenRootFile(walk, filepath.Join(pwd, ".."))
+	buildFile, noBuildFile := findMavenBuildFile(walk, pwd)
 	rootdir := resolveMavenRootDir(context, explicitBuildFile, buildFile, rootBuildFile)
 	config := ReadConfig(context, rootdir)
 	quiet := args.HasGumFlag("gq")
@@ -147,22 +241,46 @@ func FindMaven(context Context, args *ParsedArgs) *MavenCommand {
 		config.setQuiet(quiet)
 	}
 
+	report := newDiscoveryReport("Maven")
+	report.check(resolveMavenWrapperExec(context), mvnw)
+	report.check(resolveMavenExec(context), mvn)
+	daemon := config.maven.daemon || args.HasGumFlag("gmd")
+	if daemon {
+		report.check(resolveMavenDaemonExec(context), mvnd)
+	}
+	report.check("build file", buildFile)
+	report.check("root build file", rootBuildFile)
+
 	var executable string
-	if noWrapper == nil {
+	if daemon && noDaemon == nil {
+		executable = mvnd
+	} else if noWrapper == nil {
+		if daemon {
+			warnNoMavenDaemon(context, config, mvnw)
+		}
 		executable = mvnw
 	} else if noMaven == nil {
-		warnNoMavenWrapper(context, config)
+		if daemon {
+			warnNoMavenDaemon(context, config, mvn)
+		}
+		if context.IsExplicit() {
+			report.fallback("using "+mvn+". Please consider setting up a wrapper",
+				"(https://maven.apache.org/)")
+		}
 		executable = mvn
 	} else {
-		warnNoMaven(context, config)
-
 		if context.IsExplicit() {
+			report.fallback("none. Please install Maven",
+				"(https://maven.apache.org/download.cgi)")
+			report.emit(config, args)
 			context.Exit(-1)
 		}
 		return nil
 	}
 
 	if explicitBuildFileSet {
+		report.check("explicit build file", explicitBuildFile)
+		report.emit(config, args)
 		return &MavenCommand{
 			context:           context,
 			config:            config,
@@ -173,17 +291,21 @@ func FindMaven(context Context, args *ParsedArgs) *MavenCommand {
 
 	if noRootBuildFile != nil {
 		rootBuildFile = buildFile
+		if noBuildFile == nil {
+			report.assume("the nearest build file is the root build file")
+		}
 	}
 
 	if noBuildFile != nil {
 		if context.IsExplicit() {
-			fmt.Println("No Maven project found")
-			fmt.Println()
+			report.fallback("none. No Maven project found")
+			report.emit(config, args)
 			context.Exit(-1)
 		}
 		return nil
 	}
 
+	report.emit(config, args)
 	return &MavenCommand{
 		context:       context,
 		config:        config,
@@ -193,6 +315,15 @@ func FindMaven(context Context, args *ParsedArgs) *MavenCommand {
 		buildFile:     buildFile}
 }
 
+func warnNoMavenDaemon(context Context, config *Config, fallback string) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s found in path. Falling back to %s", resolveMavenDaemonExec(context), fallback)
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://github.com/apache/maven-mvnd)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
 func resolveMavenRootDir(context Context,
 	explicitBuildFile string,
 	buildFile string,
@@ -206,25 +337,6 @@ func resolveMavenRootDir(context Context,
 	return filepath.Dir(buildFile)
 }
 
-func warnNoMavenWrapper(context Context, config *Config) {
-	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s set up for this project. ", resolveMavenWrapperExec(context))
-		fmt.Println()
-		fmt.Println("Please consider setting one up.")
-		fmt.Println("(https://maven.apache.org/)")
-		fmt.Println()
-	}
-}
-
-func warnNoMaven(context Context, config *Config) {
-	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s found in path. Please install Maven.", resolveMavenExec(context))
-		fmt.Println()
-		fmt.Println("(https://maven.apache.org/download.cgi)")
-		fmt.Println()
-	}
-}
-
 // Finds the maven executable
 func findMavenExec(context Context) (string, error) {
 	maven := resolveMavenExec(context)
@@ -240,21 +352,32 @@ func findMavenExec(context Context) (string, error) {
 	return "", errors.New(maven + " not found")
 }
 
-// Finds the Maven wrapper (if it exists)
-func findMavenWrapperExec(context Context, dir string) (string, error) {
-	wrapper := resolveMavenWrapperExec(context)
-	parentdir := filepath.Join(dir, "..")
+// Finds the mvnd executable
+func findMavenDaemonExec(context Context) (string, error) {
+	mvnd := resolveMavenDaemonExec(context)
+	paths := context.GetPaths()
 
-	if parentdir == dir {
-		return "", errors.New(wrapper + " not found")
+	for i := range paths {
+		name := filepath.Join(paths[i], mvnd)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
 	}
 
-	path := filepath.Join(dir, wrapper)
-	if context.FileExists(path) {
-		return filepath.Abs(path)
+	return "", errors.New(mvnd + " not found")
+}
+
+// Finds the Maven wrapper (if it exists)
+func findMavenWrapperExec(walk *upwardWalk, dir string) (string, error) {
+	wrapper := resolveMavenWrapperExec(walk.context)
+
+	for _, listing := range walk.from(dir) {
+		if path, ok := walk.find(listing, wrapper); ok {
+			return filepath.Abs(resolveRealPath(walk.context, path))
+		}
 	}
 
-	return findMavenWrapperExec(context, parentdir)
+	return "", errors.New(wrapper + " not found")
 }
 
 func findExplicitMavenBuildFile(args *ParsedArgs) (bool, string) {
@@ -274,35 +397,45 @@ func findExplicitMavenBuildFile(args *ParsedArgs) (bool, string) {
 }
 
 // Finds the nearest pom.xml
-func findMavenBuildFile(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New("Did not find pom.xml")
-	}
-
-	path := filepath.Join(dir, "pom.xml")
-	if context.FileExists(path) {
-		return filepath.Abs(path)
+func findMavenBuildFile(walk *upwardWalk, dir string) (string, error) {
+	for _, listing := range walk.from(dir) {
+		if path, ok := walk.find(listing, "pom.xml"); ok {
+			return filepath.Abs(path)
+		}
 	}
 
-	return findMavenBuildFile(context, parentdir)
+	return "", errors.New("Did not find pom.xml")
 }
 
 // Finds the root pom.xml
-func findMavenRootFile(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New("Did not find root pom.xml")
+func findMavenRootFile(walk *upwardWalk, dir string) (string, error) {
+	if path, err := findMavenBuildFile(walk, dir); err == nil {
+		return path, nil
 	}
 
-	path := filepath.Join(dir, "pom.xml")
-	if context.FileExists(path) {
-		return filepath.Abs(path)
+	return "", errors.New("Did not find root pom.xml")
+}
+
+// Finds the project base dir, the nearest dir holding .mvn, walking up from dir
+func findMavenProjectBaseDir(context Context, dir string) string {
+	walk := walkUp(context, dir)
+	for _, listing := range walk.dirs {
+		if path, ok := walk.find(listing, ".mvn"); ok {
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				return listing.dir
+			}
+		}
 	}
+	return ""
+}
 
-	return findMavenRootFile(context, parentdir)
+// Reads a file of .mvn such as maven.config, joining its lines. Returns an empty string if missing
+func readMavenConfigFile(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(string(data)), " ")
 }
 
 // Resolves the mvnw executable (OS dependent)
@@ -320,3 +453,11 @@ func resolveMavenExec(context Context) string {
 	}
 	return "mvn"
 }
+
+// Resolves the mvnd executable (OS dependent)
+func resolveMavenDaemonExec(context Context) string {
+	if context.IsWindows() {
+		return "mvnd.cmd"
+	}
+	return "mvnd"
+}
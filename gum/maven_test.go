@@ -18,6 +18,7 @@ package gum
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -113,6 +114,30 @@ func TestMavenGoalSubstitutionPrependFlag(t *testing.T) {
 	}
 }
 
+func TestMavenGoalSubstitutionSkipped(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "single-with-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "-gr", "build", "check"})
+	cmd := FindMaven(context, &args)
+	cmd.doConfigureMaven()
+
+	// then:
+	actual := strings.Join(cmd.args.Args[2:], " ")
+	if actual != "build check" {
+		t.Errorf("args: got %s, want build check", actual)
+	}
+}
+
 func TestMavenSingleWithWrapper(t *testing.T) {
 	// given:
 	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
@@ -374,3 +399,194 @@ func TestMavenWithoutExecutables(t *testing.T) {
 		t.Error("Expected a nil command but got something")
 	}
 }
+
+func TestMavenWithDaemonFlag(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	daemon, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "daemon"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "single-with-wrapper"))
+
+	var checks = []struct {
+		title    string
+		paths    []string
+		expected string
+	}{
+		{"Daemon", []string{bin, daemon}, filepath.Join(daemon, "mvnd")},
+		{"FallbackToWrapper", []string{bin}, filepath.Join(pwd, "mvnw")},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   true,
+			windows:    false,
+			workingDir: pwd,
+			paths:      check.paths}
+
+		// when:
+		args := ParseArgs([]string{"-gq", "-gmd", "build"})
+		cmd := FindMaven(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+		if cmd.executable != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, cmd.executable, check.expected)
+		}
+	}
+}
+
+func TestMavenWithDaemonConfig(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	daemon, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "daemon"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "single-with-daemon"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin, daemon}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build"})
+	cmd := FindMaven(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+	if cmd.executable != filepath.Join(daemon, "mvnd") {
+		t.Errorf("Executable: got %s, want %s", cmd.executable, filepath.Join(daemon, "mvnd"))
+	}
+}
+
+func TestMavenModules(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "reactor"))
+	pom := filepath.Join(root, "pom.xml")
+	apps := filepath.Join(root, "apps", "pom.xml")
+	// plain mvn is told the project base dir when not running from it
+	base := "-Dmaven.multiModuleProjectDirectory=" + root + " "
+
+	var checks = []struct {
+		title    string
+		pwd      string
+		flags    []string
+		alsoMake bool
+		expected string
+	}{
+		{"Root", root, []string{}, false, "-f " + pom + " verify"},
+		{"Module", filepath.Join(root, "core"), []string{}, false, base + "-f " + pom + " -pl :reactor-core verify"},
+		{"NestedModule", filepath.Join(root, "apps", "web"), []string{}, false, base + "-f " + apps + " -pl :web verify"},
+		{"AlsoMake", filepath.Join(root, "apps", "web"), []string{}, true, base + "-f " + apps + " -pl :web -am verify"},
+		{"Nearest", filepath.Join(root, "core"), []string{"-gn"}, false, base + "-f " + filepath.Join(root, "core", "pom.xml") + " verify"},
+		{"Explicit", filepath.Join(root, "core"), []string{"-pl", ":apps"}, false, base + "-f " + pom + " -pl :apps verify"},
+		{"NotInReactor", filepath.Join(root, "orphan"), []string{}, false, base + "-f " + pom + " verify"},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   true,
+			windows:    false,
+			workingDir: check.pwd,
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs(append(append([]string{"-gq"}, check.flags...), "verify"))
+		cmd := FindMaven(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+
+		cmd.config.maven.alsoMake = check.alsoMake
+		cmd.doConfigureMaven()
+
+		if actual := strings.Join(cmd.args.Args, " "); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestMavenProjectBaseDir(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "reactor"))
+
+	var checks = []struct {
+		title    string
+		pwd      string
+		args     []string
+		expected int
+	}{
+		{"Root", root, []string{}, 0},
+		{"Module", filepath.Join(root, "core"), []string{}, 1},
+		{"NestedModule", filepath.Join(root, "apps", "web"), []string{}, 1},
+		{"Explicit", filepath.Join(root, "core"), []string{"-Dmaven.multiModuleProjectDirectory=" + root}, 1},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   true,
+			windows:    false,
+			workingDir: check.pwd,
+			paths:      []string{bin},
+			env:        map[string]string{}}
+
+		// when:
+		args := ParseArgs(append(append([]string{"-gq"}, check.args...), "verify"))
+		cmd := FindMaven(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+
+		cmd.doConfigureMaven()
+
+		if cmd.projectBaseDir != root {
+			t.Errorf("%s: got base dir %s, want %s", check.title, cmd.projectBaseDir, root)
+		}
+		found := 0
+		for _, arg := range cmd.args.Args {
+			if arg == "-Dmaven.multiModuleProjectDirectory="+root {
+				found++
+			}
+		}
+		if found != check.expected {
+			t.Errorf("%s: got %d base dir args in %v, want %d", check.title, found, cmd.args.Args, check.expected)
+		}
+	}
+}
+
+func TestReadMavenConfigFile(t *testing.T) {
+	// given:
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "reactor"))
+
+	// when:
+	mavenConfig := readMavenConfigFile(filepath.Join(root, ".mvn", "maven.config"))
+	jvmConfig := readMavenConfigFile(filepath.Join(root, ".mvn", "jvm.config"))
+	missing := readMavenConfigFile(filepath.Join(root, ".mvn", "missing.config"))
+
+	// then:
+	if mavenConfig != "-T 1C --fail-at-end" {
+		t.Errorf("maven.config: got %s", mavenConfig)
+	}
+	if jvmConfig != "-Xmx2g" {
+		t.Errorf("jvm.config: got %s", jvmConfig)
+	}
+	if missing != "" {
+		t.Errorf("missing: got %s", missing)
+	}
+}
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Build files that mark a directory as part of a JVM build. A package.json is
+// only taken into account if found before any of these
+var jvmBuildFiles = []string{
+	"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts",
+	"pom.xml", "build.xml", "build.sbt", "project.clj", "deps.edn"}
+
+// Lockfiles that identify a Node package manager, in order
+var nodeLockFiles = []struct {
+	name           string
+	packageManager string
+}{
+	{"pnpm-lock.yaml", "pnpm"},
+	{"yarn.lock", "yarn"},
+	{"bun.lockb", "bun"},
+	{"package-lock.json", "npm"},
+	{"npm-shrinkwrap.json", "npm"},
+}
+
+// npm commands that must not be prefixed with "run" when a script shares their name
+var npmCommands = []string{
+	"access", "audit", "cache", "ci", "config", "dedupe", "exec", "explain", "init", "install",
+	"link", "ls", "outdated", "pack", "prune", "publish", "rebuild", "restart", "run", "run-script",
+	"start", "stop", "test", "uninstall", "update", "version", "view"}
+
+// packageJSON captures the fields of package.json used by Gum
+type packageJSON struct {
+	PackageManager string            `json:"packageManager"`
+	Scripts        map[string]string `json:"scripts"`
+}
+
+// NodeCommand defines an executable Node package manager command
+type NodeCommand struct {
+	context        Context
+	config         *Config
+	rootdir        string
+	executable     string
+	packageManager string
+	args           *ParsedArgs
+	buildFile      string
+	scripts        map[string]string
+}
+
+// Execute executes the given command
+func (c NodeCommand) Execute() int {
+	c.doConfigureNode()
+	return ExitCode(c.doExecuteNode())
+}
+
+func (c *NodeCommand) doConfigureNode() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using "+c.packageManager+" at '"+c.executable+"'")
+	banner = append(banner, "to run package '"+c.buildFile+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "node"))
+	args = appendSafe(args, c.args.Tool)
+	if c.packageManager == "npm" && len(oargs) > 0 && c.isScript(oargs[0]) && !contains(npmCommands, oargs[0]) {
+		args = append(args, "run")
+	}
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugNode(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *NodeCommand) isScript(name string) bool {
+	_, ok := c.scripts[name]
+	return ok
+}
+
+func (c *NodeCommand) doExecuteNode() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the config of the command
+func (c *NodeCommand) configuration() *Config {
+	return c.config
+}
+
+// Resolves the invocation of the configured command
+func (c *NodeCommand) invocation() invocation {
+	return invocation{
+		tool:       "node",
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		buildFile:  c.buildFile,
+		workDir:    c.rootdir,
+		args:       c.args}
+}
+
+func (c *NodeCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *NodeCommand) debugNode(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "packageManager     = ", c.packageManager)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindNode finds and executes the Node package manager of the nearest package.json,
+// as long as the package is not part of a JVM build
+func FindNode(context Context, args *ParsedArgs) *NodeCommand {
+	pwd := context.GetWorkingDir()
+
+	buildFile, noBuildFile := findNodeBuildFile(context, pwd)
+	if noBuildFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No Node package found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	rootdir := filepath.Dir(buildFile)
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	pkg := readPackageJSON(buildFile)
+	packageManager := resolveNodePackageManager(context, rootdir, pkg)
+	executable, noExecutable := findNodeExec(context, packageManager)
+	if noExecutable != nil {
+		warnNoNode(context, config, packageManager)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &NodeCommand{
+		context:        context,
+		config:         config,
+		rootdir:        rootdir,
+		executable:     executable,
+		packageManager: packageManager,
+		args:           args,
+		buildFile:      buildFile,
+		scripts:        pkg.Scripts}
+}
+
+func warnNoNode(context Context, config *Config, packageManager string) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s found in path. Please install it.", resolveNodeExec(context, packageManager))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://nodejs.org/en/download)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the nearest package.json, unless a JVM build file is found first
+func findNodeBuildFile(context Context, dir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	if parentdir == dir {
+		return "", errors.New("Did not find package.json")
+	}
+
+	for _, name := range jvmBuildFiles {
+		if context.FileExists(filepath.Join(dir, name)) {
+			return "", errors.New("Found " + name + " before package.json")
+		}
+	}
+
+	path := filepath.Join(dir, "package.json")
+	if context.FileExists(path) {
+		return filepath.Abs(path)
+	}
+
+	if isSearchBoundary(context, dir) {
+		return "", errors.New("Did not find package.json")
+	}
+
+	return findNodeBuildFile(context, parentdir)
+}
+
+func readPackageJSON(path string) packageJSON {
+	pkg := packageJSON{}
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		json.Unmarshal(data, &pkg)
+	}
+	return pkg
+}
+
+// Resolves the package manager from the packageManager field of package.json,
+// such as "pnpm@8.6.0", otherwise from the lockfile. Defaults to npm
+func resolveNodePackageManager(context Context, dir string, pkg packageJSON) string {
+	if len(pkg.PackageManager) > 0 {
+		return strings.SplitN(pkg.PackageManager, "@", 2)[0]
+	}
+
+	for _, lockFile := range nodeLockFiles {
+		if context.FileExists(filepath.Join(dir, lockFile.name)) {
+			return lockFile.packageManager
+		}
+	}
+
+	return "npm"
+}
+
+// Finds the executable of the given package manager
+func findNodeExec(context Context, packageManager string) (string, error) {
+	exec := resolveNodeExec(context, packageManager)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], exec)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(exec + " not found")
+}
+
+// Resolves the executable of the given package manager (OS dependent)
+func resolveNodeExec(context Context, packageManager string) string {
+	if context.IsWindows() {
+		if packageManager == "bun" {
+			return "bun.exe"
+		}
+		return packageManager + ".cmd"
+	}
+	return packageManager
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNodeInsideGradleProject(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "node", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "node", "gradle-with-frontend", "frontend"))
+	pwd := filepath.Join(root, "src")
+
+	var checks = []struct {
+		title    string
+		args     []string
+		expected string
+	}{
+		{"Script", []string{"-gq", "build"}, "run build"},
+		{"Command", []string{"-gq", "test"}, "test"},
+		{"NotAScript", []string{"-gq", "install"}, "install"},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: pwd,
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs(check.args)
+		cmd := FindNode(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+
+		cmd.doConfigureNode()
+		if cmd.executable != filepath.Join(bin, "npm") {
+			t.Errorf("%s: got %s, want %s", check.title, cmd.executable, filepath.Join(bin, "npm"))
+		}
+		if cmd.invocation().workDir != root {
+			t.Errorf("%s: got %s, want %s", check.title, cmd.invocation().workDir, root)
+		}
+		if actual := strings.Join(cmd.args.Args, " "); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestNodePackageManager(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "node", "bin"))
+
+	var checks = []struct {
+		dir, expected string
+	}{
+		{"pnpm", "pnpm"},
+		{"yarn-lock", "yarn"},
+	}
+
+	for _, check := range checks {
+		pwd, _ := filepath.Abs(filepath.Join("..", "tests", "node", check.dir))
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: pwd,
+			paths:      []string{bin}}
+
+		// when:
+		args := ParseArgs([]string{"-gq"})
+		cmd := FindNode(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.dir)
+			continue
+		}
+		if cmd.packageManager != check.expected || cmd.executable != filepath.Join(bin, check.expected) {
+			t.Errorf("%s: got %s at %s, want %s", check.dir, cmd.packageManager, cmd.executable, check.expected)
+		}
+	}
+}
+
+func TestNodeSkippedInsideJvmBuild(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "node", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "node", "backend-in-node", "backend"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindNode(context, &args)
+
+	// then:
+	if cmd != nil {
+		t.Error("Expected a nil command but got something")
+	}
+}
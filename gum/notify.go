@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Builds finishing sooner than this are not notified, unless notify.threshold is set
+const defaultNotifyThreshold = time.Minute
+
+// Time given to a webhook to accept a notification
+const webhookTimeout = 5 * time.Second
+
+// buildNotification is the payload posted to webhooks
+type buildNotification struct {
+	Tool       string `json:"tool"`
+	BuildFile  string `json:"buildFile,omitempty"`
+	Status     string `json:"status"`
+	ExitCode   int    `json:"exitCode"`
+	DurationMs int64  `json:"durationMs"`
+	Message    string `json:"message"`
+}
+
+// Notifies the outcome of a finished build as set with general.notify,
+// as long as the build took longer than notify.threshold
+func notifyBuild(config *Config, inv invocation, duration time.Duration, err error) {
+	if len(config.general.notify) == 0 || duration < resolveNotifyThreshold(config) {
+		return
+	}
+	status, code, ok := buildOutcome(err)
+	if !ok {
+		return
+	}
+
+	notification := buildNotification{
+		Tool:       inv.tool,
+		BuildFile:  inv.buildFile,
+		Status:     status,
+		ExitCode:   code,
+		DurationMs: duration.Milliseconds(),
+		Message:    formatBuildSummary(inv, duration, err)}
+
+	var nerr error
+	switch config.general.notify {
+	case "desktop":
+		nerr = sendDesktopNotification(runtime.GOOS, notification)
+	case "webhook":
+		nerr = postWebhook(config.notify.url, notification)
+	default:
+		nerr = errors.New("Unsupported notification '" + config.general.notify + "'. Use desktop or webhook")
+	}
+
+	if nerr != nil && !config.general.quiet {
+		fmt.Fprintln(gumOutput, "WARNING: could not send build notification")
+		fmt.Fprintln(gumOutput, nerr)
+	}
+}
+
+// Resolves the minimum build duration that triggers a notification
+func resolveNotifyThreshold(config *Config) time.Duration {
+	if len(config.notify.threshold) == 0 {
+		return defaultNotifyThreshold
+	}
+	threshold, err := time.ParseDuration(config.notify.threshold)
+	if err != nil || threshold < 0 {
+		return defaultNotifyThreshold
+	}
+	return threshold
+}
+
+// Resolves the command that displays a desktop notification on the given OS
+func desktopNotificationCommand(goos string, title string, message string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"osascript", "-e", "display notification " + appleScriptQuote(message) + " with title " + appleScriptQuote(title)}
+	case "windows":
+		return []string{"powershell", "-NoProfile", "-Command",
+			"Add-Type -AssemblyName System.Windows.Forms; " +
+				"$n = New-Object System.Windows.Forms.NotifyIcon; " +
+				"$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; " +
+				"$n.ShowBalloonTip(5000, " + powerShellQuote(title) + ", " + powerShellQuote(message) + ", 'Info'); " +
+				"Start-Sleep -Seconds 5; $n.Dispose()"}
+	}
+	return []string{"notify-send", title, message}
+}
+
+func sendDesktopNotification(goos string, notification buildNotification) error {
+	title := "gum: " + notification.Tool + " build " + notification.Status
+	command := desktopNotificationCommand(goos, title, notification.Message)
+	return exec.Command(command[0], command[1:]...).Run()
+}
+
+func appleScriptQuote(s string) string {
+	return "\"" + strings.Replace(strings.Replace(s, "\\", "\\\\", -1), "\"", "\\\"", -1) + "\""
+}
+
+// Posts the notification as JSON to the given url
+func postWebhook(url string, notification buildNotification) error {
+	if len(url) == 0 {
+		return errors.New("Missing notify.url for webhook notifications")
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.New("Webhook " + url + " responded with " + resp.Status)
+	}
+	return nil
+}
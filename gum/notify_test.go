@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifyBuildWebhook(t *testing.T) {
+	var checks = []struct {
+		title     string
+		threshold string
+		expected  bool
+	}{
+		{"LongBuild", "1s", true},
+		{"ShortBuild", "", false},
+	}
+
+	for _, check := range checks {
+		// given:
+		received := make(chan buildNotification, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			notification := buildNotification{}
+			json.NewDecoder(r.Body).Decode(&notification)
+			received <- notification
+		}))
+
+		config := newConfig()
+		config.general.notify = "webhook"
+		config.notify.url = server.URL
+		config.notify.threshold = check.threshold
+		inv := invocation{tool: "gradle", buildFile: "/p/build.gradle"}
+
+		// when:
+		notifyBuild(config, inv, 5*time.Second, nil)
+		server.Close()
+
+		// then:
+		select {
+		case notification := <-received:
+			if !check.expected {
+				t.Errorf("%s: unexpected notification %v", check.title, notification)
+			} else if notification.Status != "succeeded" || notification.ExitCode != 0 || notification.DurationMs != 5000 {
+				t.Errorf("%s: unexpected notification %v", check.title, notification)
+			}
+		default:
+			if check.expected {
+				t.Errorf("%s: expected a notification", check.title)
+			}
+		}
+	}
+}
+
+func TestDesktopNotificationCommand(t *testing.T) {
+	var checks = []struct {
+		goos     string
+		expected []string
+	}{
+		{"linux", []string{"notify-send", "gum", "Build \"done\""}},
+		{"darwin", []string{"osascript", "-e", `display notification "Build \"done\"" with title "gum"`}},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := desktopNotificationCommand(check.goos, "gum", `Build "done"`)
+
+		// then:
+		if strings.Join(actual, "|") != strings.Join(check.expected, "|") {
+			t.Errorf("%s: got %v, want %v", check.goos, actual, check.expected)
+		}
+	}
+}
+
+func TestResolveNotifyThreshold(t *testing.T) {
+	var checks = []struct {
+		threshold string
+		expected  time.Duration
+	}{
+		{"", time.Minute},
+		{"10m", 10 * time.Minute},
+		{"later", time.Minute},
+	}
+
+	for _, check := range checks {
+		// given:
+		config := newConfig()
+		config.notify.threshold = check.threshold
+
+		// when:
+		actual := resolveNotifyThreshold(config)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.threshold, actual, check.expected)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time given to the collector to accept a span
+const otlpTimeout = 5 * time.Second
+
+// OTLP status codes
+const (
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+// OTLP span kind of an invocation
+const otlpSpanKindInternal = 1
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+func stringAttribute(key string, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+func intAttribute(key string, value int64) otlpAttribute {
+	s := strconv.FormatInt(value, 10)
+	return otlpAttribute{Key: key, Value: otlpValue{IntValue: &s}}
+}
+
+// Resolves the traces endpoint from $OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, used as is,
+// or else from $OTEL_EXPORTER_OTLP_ENDPOINT. Returns an empty string when not configured
+func resolveOtlpEndpoint(context Context) string {
+	if endpoint := context.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); len(endpoint) > 0 {
+		return endpoint
+	}
+	if endpoint := context.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); len(endpoint) > 0 {
+		return strings.TrimRight(endpoint, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// Exports a span for the invocation with OTLP/HTTP when an OTLP endpoint is configured
+func exportSpan(context Context, config *Config, inv invocation, start time.Time, duration time.Duration, err error) {
+	endpoint := resolveOtlpEndpoint(context)
+	if len(endpoint) == 0 {
+		return
+	}
+
+	traces := newInvocationTraces(context, inv, start, duration, err)
+	if xerr := postTraces(endpoint, parseOtlpHeaders(context.Getenv("OTEL_EXPORTER_OTLP_HEADERS")), traces); xerr != nil && config.general.debug {
+		fmt.Fprintln(gumOutput, "WARNING: could not export span to "+endpoint)
+		fmt.Fprintln(gumOutput, xerr)
+	}
+}
+
+// Creates the traces holding the span of an invocation. The span joins the trace given with
+// $TRACEPARENT, if any, such as when Gum is launched by an instrumented CI pipeline
+func newInvocationTraces(context Context, inv invocation, start time.Time, duration time.Duration, err error) otlpTraces {
+	status, code, ok := buildOutcome(err)
+	if !ok {
+		status = "not launched"
+	}
+
+	span := otlpSpan{
+		TraceID:           randomHex(16),
+		SpanID:            randomHex(8),
+		Name:              strings.TrimSpace(inv.tool + " " + strings.Join(inv.args.Args, " ")),
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(start.Add(duration).UnixNano(), 10),
+		Attributes: []otlpAttribute{
+			stringAttribute("gum.tool", inv.tool),
+			stringAttribute("gum.build_file", inv.buildFile),
+			stringAttribute("gum.args", strings.Join(inv.args.Args, " ")),
+			intAttribute("process.exit.code", int64(code)),
+			intAttribute("gum.duration_ms", duration.Milliseconds())},
+		Status: otlpStatus{Code: otlpStatusOk}}
+	if err != nil {
+		span.Status = otlpStatus{Code: otlpStatusError, Message: "build " + status}
+	}
+
+	if parts := strings.Split(context.Getenv("TRACEPARENT"), "-"); len(parts) == 4 && len(parts[1]) == 32 && len(parts[2]) == 16 {
+		span.TraceID = parts[1]
+		span.ParentSpanID = parts[2]
+	}
+
+	serviceName := context.Getenv("OTEL_SERVICE_NAME")
+	if len(serviceName) == 0 {
+		serviceName = "gum"
+	}
+
+	scopeSpans := otlpScopeSpans{Spans: []otlpSpan{span}}
+	scopeSpans.Scope.Name = "gum"
+	resourceSpans := otlpResourceSpans{ScopeSpans: []otlpScopeSpans{scopeSpans}}
+	resourceSpans.Resource.Attributes = []otlpAttribute{stringAttribute("service.name", serviceName)}
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{resourceSpans}}
+}
+
+// Parses $OTEL_EXPORTER_OTLP_HEADERS, given as key1=value1,key2=value2
+func parseOtlpHeaders(s string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 2 && len(strings.TrimSpace(kv[0])) > 0 {
+			headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return headers
+}
+
+func postTraces(endpoint string, headers map[string]string, traces otlpTraces) error {
+	payload, err := json.Marshal(traces)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{Timeout: otlpTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.New("Collector " + endpoint + " responded with " + resp.Status)
+	}
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExportSpan(t *testing.T) {
+	// given:
+	received := make(chan otlpTraces, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		traces := otlpTraces{}
+		json.NewDecoder(r.Body).Decode(&traces)
+		received <- traces
+	}))
+	defer server.Close()
+
+	context := testContext{env: map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": server.URL + "/",
+		"OTEL_EXPORTER_OTLP_HEADERS":  "Authorization=Bearer s3cr3t",
+		"TRACEPARENT":                 "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}}
+	args := ParseArgs([]string{"build"})
+	inv := invocation{tool: "gradle", buildFile: "/p/build.gradle", args: &args}
+
+	// when:
+	exportSpan(context, newConfig(), inv, time.Unix(100, 0), 2*time.Second, nil)
+
+	// then:
+	select {
+	case traces := <-received:
+		span := traces.ResourceSpans[0].ScopeSpans[0].Spans[0]
+		if span.Name != "gradle build" || span.TraceID != "0af7651916cd43dd8448eb211c80319c" || span.ParentSpanID != "b7ad6b7169203331" {
+			t.Errorf("unexpected span %v", span)
+		}
+		if span.StartTimeUnixNano != "100000000000" || span.EndTimeUnixNano != "102000000000" || span.Status.Code != otlpStatusOk {
+			t.Errorf("unexpected span %v", span)
+		}
+		attributes := make(map[string]string)
+		for _, a := range span.Attributes {
+			if a.Value.StringValue != nil {
+				attributes[a.Key] = *a.Value.StringValue
+			} else {
+				attributes[a.Key] = *a.Value.IntValue
+			}
+		}
+		if attributes["gum.build_file"] != "/p/build.gradle" || attributes["process.exit.code"] != "0" || attributes["gum.duration_ms"] != "2000" {
+			t.Errorf("unexpected attributes %v", attributes)
+		}
+	default:
+		t.Error("expected a span")
+	}
+}
+
+func TestResolveOtlpEndpoint(t *testing.T) {
+	var checks = []struct {
+		env      map[string]string
+		expected string
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"}, "http://collector:4318/v1/traces"},
+		{map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://traces:4318/spans"}, "http://traces:4318/spans"},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := resolveOtlpEndpoint(testContext{env: check.env})
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%v: got %s, want %s", check.env, actual, check.expected)
+		}
+	}
+}
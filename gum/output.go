@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io"
+	"os"
+)
+
+// gumOutput receives Gum's own messages, such as banners, warnings, and diagnostics.
+// Defaults to stderr thus pipelines only see the output of the build, i.e, gm help:evaluate | jq
+var gumOutput io.Writer = os.Stderr
+
+// Selects the stream of Gum's own messages as set with general.output. Messages are written
+// without locking, thus it must not be called while detectors run
+func setGumOutput(config *Config) {
+	gumOutput = os.Stderr
+	if config.general.output == "stdout" {
+		gumOutput = os.Stdout
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSetGumOutput(t *testing.T) {
+	defer func() { gumOutput = os.Stderr }()
+
+	var checks = []struct {
+		output   string
+		expected *os.File
+	}{
+		{"", os.Stderr},
+		{"stdout", os.Stdout},
+		{"stderr", os.Stderr},
+	}
+
+	for _, check := range checks {
+		// given:
+		config := newConfig()
+		config.general.output = check.output
+
+		// when:
+		setGumOutput(config)
+
+		// then:
+		if gumOutput != check.expected {
+			t.Errorf("%q: got %v, want %v", check.output, gumOutput, check.expected)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// policy defines machine-wide settings that can not be overridden by either user or project
+// config. It guards against config found in a checked out repository, not against whoever
+// launches gm, as the environment may select another policy file with $GUM_POLICY
+type policy struct {
+	file      string
+	checksums string
+	tofu      bool
+	printOnly bool
+	hosts     []string
+	hostsMode string
+
+	configSignatures string
+	configKeys       string
+
+	gradleVersion string
+	mavenVersion  string
+	versionsMode  string
+}
+
+func (p *policy) print(t Theme) {
+	if len(p.file) == 0 {
+		return
+	}
+
+	t.PrintSection("policy")
+	t.PrintKeyValueLiteral("file", p.file)
+	t.PrintKeyValueBoolean("printOnly", p.printOnly)
+	t.PrintKeyValueLiteral("checksums", p.checksums)
+	t.PrintKeyValueBoolean("tofu", p.tofu)
+	t.PrintKeyValueArrayS("hosts", p.hosts)
+	t.PrintKeyValueLiteral("hostsMode", p.hostsMode)
+	t.PrintKeyValueLiteral("configSignatures", p.configSignatures)
+	t.PrintKeyValueLiteral("configKeys", p.configKeys)
+	t.PrintSection("policy.versions")
+	t.PrintKeyValueLiteral("gradle", p.gradleVersion)
+	t.PrintKeyValueLiteral("maven", p.mavenVersion)
+	t.PrintKeyValueLiteral("mode", p.versionsMode)
+}
+
+// ReadPolicy reads the machine-wide policy file.
+// The location may be overridden with $GUM_POLICY, see policy
+func ReadPolicy(context Context) policy {
+	path := resolvePolicyFile(context)
+	p := policy{
+		hosts:            make([]string, 0),
+		hostsMode:        "warn",
+		configSignatures: "off",
+		versionsMode:     "warn"}
+
+	if !context.FileExists(path) {
+		return p
+	}
+
+	doc, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
+		return p
+	}
+
+	t, err := toml.LoadBytes(doc)
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
+		return p
+	}
+
+	p.file = path
+	resolvePolicySectionGeneral(t, &p)
+	resolvePolicySectionSecurity(t, &p)
+	resolvePolicySectionVersions(t, &p)
+
+	return p
+}
+
+func resolvePolicySectionGeneral(t *toml.Tree, p *policy) {
+	tt := t.Get("general")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("printOnly")
+		if v != nil {
+			p.printOnly = v.(bool)
+		}
+	}
+}
+
+func resolvePolicySectionSecurity(t *toml.Tree, p *policy) {
+	tt := t.Get("security")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("checksums")
+		if v != nil {
+			p.checksums = v.(string)
+		}
+		v = table.Get("tofu")
+		if v != nil {
+			p.tofu = v.(bool)
+		}
+		v = table.Get("hosts")
+		if v != nil {
+			data := v.([]interface{})
+			p.hosts = make([]string, len(data))
+			for i, e := range data {
+				p.hosts[i] = e.(string)
+			}
+		}
+		v = table.Get("hostsMode")
+		if v != nil {
+			p.hostsMode = strings.ToLower(v.(string))
+		}
+		v = table.Get("configSignatures")
+		if v != nil {
+			p.configSignatures = strings.ToLower(v.(string))
+		}
+		v = table.Get("configKeys")
+		if v != nil {
+			p.configKeys = v.(string)
+		}
+	}
+}
+
+func resolvePolicySectionVersions(t *toml.Tree, p *policy) {
+	tt := t.Get("versions")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("gradle")
+		if v != nil {
+			p.gradleVersion = v.(string)
+		}
+		v = table.Get("maven")
+		if v != nil {
+			p.mavenVersion = v.(string)
+		}
+		v = table.Get("mode")
+		if v != nil {
+			p.versionsMode = strings.ToLower(v.(string))
+		}
+	}
+}
+
+// Resolves the policy file (OS dependent)
+func resolvePolicyFile(context Context) string {
+	file := context.Getenv("GUM_POLICY")
+	if len(file) > 0 {
+		return file
+	}
+
+	if context.IsWindows() {
+		return filepath.Join(context.Getenv("ProgramData"), "Gum", "policy.toml")
+	}
+	return filepath.Join("/etc", "gum", "policy.toml")
+}
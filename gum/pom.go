@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// pomFile holds the parts of a pom.xml read by Gum. Maven 4 lists subprojects instead of modules
+type pomFile struct {
+	ArtifactID  string   `xml:"artifactId"`
+	Modules     []string `xml:"modules>module"`
+	Subprojects []string `xml:"subprojects>subproject"`
+}
+
+func readPom(path string) (*pomFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pom := &pomFile{}
+	if err := xml.Unmarshal(data, pom); err != nil {
+		return nil, err
+	}
+	return pom, nil
+}
+
+// Resolves the selector of the module defined by buildFile, such as :core, when it is part
+// of the reactor of rootBuildFile, following nested modules. Returns an empty string otherwise
+func resolveMavenModule(rootBuildFile string, buildFile string) string {
+	target := filepath.Clean(buildFile)
+	visited := make(map[string]bool)
+	pending := []string{filepath.Clean(rootBuildFile)}
+
+	for len(pending) > 0 {
+		path := pending[0]
+		pending = pending[1:]
+		if visited[path] {
+			continue
+		}
+		visited[path] = true
+
+		if path == target {
+			pom, err := readPom(path)
+			if err != nil || len(pom.ArtifactID) == 0 || strings.Contains(pom.ArtifactID, "${") {
+				return ""
+			}
+			return ":" + pom.ArtifactID
+		}
+
+		pom, err := readPom(path)
+		if err != nil {
+			continue
+		}
+		for _, module := range append(pom.Modules, pom.Subprojects...) {
+			module = filepath.Join(filepath.Dir(path), filepath.FromSlash(strings.TrimSpace(module)))
+			if !strings.HasSuffix(module, ".xml") {
+				module = filepath.Join(module, "pom.xml")
+			}
+			pending = append(pending, module)
+		}
+	}
+
+	return ""
+}
+
+// Checks if args already select the projects of the reactor with -pl or --projects
+func hasProjectListArg(args *ParsedArgs) bool {
+	for _, arg := range appendSafe(appendSafe(make([]string, 0), args.Tool), args.Args) {
+		if arg == "-pl" || arg == "--projects" || strings.HasPrefix(arg, "--projects=") {
+			return true
+		}
+	}
+	return false
+}
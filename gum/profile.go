@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// profile defines default args per tool and environment variables,
+// applied when activated with -gp or $GUM_PROFILE
+type profile struct {
+	args map[string][]string
+	env  map[string]string
+}
+
+// Resolves the name of the active profile, if any. -gp takes precedence over $GUM_PROFILE
+func resolveProfileName(context Context, args *ParsedArgs) string {
+	if name := args.GumFlagValue("gp"); len(name) > 0 {
+		return name
+	}
+	return context.Getenv("GUM_PROFILE")
+}
+
+// Prepends the args of the active profile for the invoked tool and sets its environment
+func applyProfile(context Context, config *Config, inv *invocation) error {
+	name := resolveProfileName(context, inv.args)
+	if len(name) == 0 {
+		return nil
+	}
+
+	p, ok := config.profiles[name]
+	if !ok {
+		return errors.New("Unknown profile '" + name + "'. Valid profiles are [" + strings.Join(sortedProfiles(config.profiles), ", ") + "]")
+	}
+
+	inv.args.Args = append(append(make([]string, 0), p.args[inv.tool]...), inv.args.Args...)
+	inv.env = append(make([]string, 0), inv.env...)
+	for _, k := range sortedEnvKeys(p.env) {
+		inv.env = append(inv.env, k+"="+p.env[k])
+	}
+
+	return nil
+}
+
+func sortedProfiles(profiles map[string]*profile) []string {
+	names := make([]string, 0)
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedEnvKeys(env map[string]string) []string {
+	keys := make([]string, 0)
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
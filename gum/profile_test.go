@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyProfile(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".gm.toml")
+	ioutil.WriteFile(path, []byte(`[profiles.ci.gradle]
+args = ["--no-daemon", "--console=plain"]
+
+[profiles.ci.env]
+GRADLE_OPTS = "-Xmx2g"
+CI = "true"
+
+[profiles.local.maven]
+args = ["-o"]
+`), 0644)
+
+	config := ReadConfigFile(testContext{}, path)
+
+	var checks = []struct {
+		title, tool, flag, env    string
+		expectedArgs, expectedEnv string
+		fails                     bool
+	}{
+		{"None", "gradle", "", "", "build", "", false},
+		{"Flag", "gradle", "ci", "", "--no-daemon --console=plain build", "CI=true GRADLE_OPTS=-Xmx2g", false},
+		{"Env", "gradle", "", "ci", "--no-daemon --console=plain build", "CI=true GRADLE_OPTS=-Xmx2g", false},
+		{"FlagOverridesEnv", "maven", "local", "ci", "-o build", "", false},
+		{"OtherTool", "maven", "ci", "", "build", "CI=true GRADLE_OPTS=-Xmx2g", false},
+		{"Unknown", "gradle", "release", "", "build", "", true},
+	}
+
+	for _, check := range checks {
+		flags := []string{"build"}
+		if len(check.flag) > 0 {
+			flags = append([]string{"-gp", check.flag}, flags...)
+		}
+		args := ParseArgs(flags)
+		inv := invocation{tool: check.tool, args: &args}
+		context := testContext{env: map[string]string{"GUM_PROFILE": check.env}}
+
+		// when:
+		err := applyProfile(context, config, &inv)
+
+		// then:
+		if (err != nil) != check.fails {
+			t.Errorf("%s: unexpected error %v", check.title, err)
+		}
+		if actual := strings.Join(inv.args.Args, " "); actual != check.expectedArgs {
+			t.Errorf("%s: args: got %s, want %s", check.title, actual, check.expectedArgs)
+		}
+		if actual := strings.Join(inv.env, " "); actual != check.expectedEnv {
+			t.Errorf("%s: env: got %s, want %s", check.title, actual, check.expectedEnv)
+		}
+	}
+}
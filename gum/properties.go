@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// Reads a Java properties file. Supports comments, key=value and key:value
+// entries, and escaped characters. Multiline values are not supported
+func readProperties(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		sep := -1
+		for i := 0; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+				continue
+			}
+			if line[i] == '=' || line[i] == ':' {
+				sep = i
+				break
+			}
+		}
+
+		if sep == -1 {
+			props[unescapeProperty(line)] = ""
+		} else {
+			key := strings.TrimSpace(line[:sep])
+			value := strings.TrimSpace(line[sep+1:])
+			props[unescapeProperty(key)] = unescapeProperty(value)
+		}
+	}
+
+	return props, scanner.Err()
+}
+
+func unescapeProperty(s string) string {
+	if strings.Index(s, "\\") == -1 {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 't':
+				b.WriteByte('\t')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Time given to the pseudo terminal to drain once the child process has exited
+const ptyDrainPeriod = time.Second
+
+// Copies the output of a child process attached to a pseudo terminal
+type ptyOutput struct {
+	master *os.File
+	slave  *os.File
+	done   chan struct{}
+}
+
+// Attaches stdout and stderr of cmd to a pseudo terminal, thus the build tool detects
+// a terminal and emits rich output, which is copied to w once the child has started
+func attachPty(cmd *exec.Cmd, w io.Writer) (*ptyOutput, error) {
+	master, slave, err := openPty()
+	if err != nil {
+		return nil, err
+	}
+	copyWinsize(os.Stdout, master)
+
+	cmd.Stdout = slave
+	cmd.Stderr = slave
+
+	pty := &ptyOutput{master: master, slave: slave, done: make(chan struct{})}
+	go func() {
+		// reading from the master fails once every copy of the slave has been closed
+		io.Copy(w, master)
+		close(pty.done)
+	}()
+	return pty, nil
+}
+
+// Closes the parent's copy of the slave once the child has started
+func (p *ptyOutput) started() {
+	if p != nil {
+		p.slave.Close()
+	}
+}
+
+// Waits for the remaining output of the child, then releases the pseudo terminal.
+// Processes spawned by the child may keep the slave open, hence the wait is bounded
+func (p *ptyOutput) finish() {
+	if p == nil {
+		return
+	}
+	p.slave.Close()
+	select {
+	case <-p.done:
+	case <-time.After(ptyDrainPeriod):
+	}
+	p.master.Close()
+}
+
+// Whether the build should run under a pseudo terminal. Only needed when Gum inspects
+// the output of the build while stdout is a terminal. Disabled with --no-pty
+func usePty(args *ParsedArgs) bool {
+	return !args.HasGumFlag("-no-pty") && isTerminalOutput()
+}
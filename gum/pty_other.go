@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package gum
+
+import (
+	"errors"
+	"os"
+)
+
+// Pseudo terminals are not supported, builds fall back to plain pipes
+func openPty() (*os.File, *os.File, error) {
+	return nil, nil, errors.New("pseudo terminals are not supported on this platform")
+}
+
+func copyWinsize(from *os.File, to *os.File) {}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux || darwin
+// +build linux darwin
+
+package gum
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestAttachPty(t *testing.T) {
+	// given:
+	cmd := exec.Command("sh", "-c", "if [ -t 1 ]; then echo terminal; else echo pipe; fi")
+	output := &bytes.Buffer{}
+	pty, err := attachPty(cmd, output)
+	if err != nil {
+		t.Skip("requires pseudo terminals")
+	}
+
+	// when:
+	_, err = runForwardingSignals(context.Background(), cmd, 0, pty, nil)
+
+	// then:
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := strings.TrimSpace(output.String()); actual != "terminal" {
+		t.Errorf("got %q, want %q", actual, "terminal")
+	}
+}
+
+func TestUsePtyDisabled(t *testing.T) {
+	// given:
+	args := ParseArgs([]string{"--no-pty", "build"})
+
+	// when:
+	actual := usePty(&args)
+
+	// then:
+	if actual {
+		t.Error("expected no pseudo terminal with --no-pty")
+	}
+}
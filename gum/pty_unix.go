@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux || darwin
+// +build linux darwin
+
+package gum
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	rows   uint16
+	cols   uint16
+	xpixel uint16
+	ypixel uint16
+}
+
+func ioctl(f *os.File, request uintptr, arg uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), request, arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Sizes the pseudo terminal like the terminal Gum runs on. Errors are ignored
+func copyWinsize(from *os.File, to *os.File) {
+	ws := &winsize{}
+	if ioctl(from, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(ws))) == nil {
+		ioctl(to, syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(ws)))
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Files that hold the configuration of the JReleaser CLI, in order
+var jreleaserConfigFiles = []string{"jreleaser.yml", "jreleaser.yaml", "jreleaser.toml", "jreleaser.json", ".jreleaser"}
+
+// Handles "gm gum release [<command>] [args]". Invokes the JReleaser CLI if the project
+// has a JReleaser config file, otherwise the tasks/goals of the Gradle or Maven plugin.
+// The command defaults to full-release
+func runReleaseCommand(context Context, args *ParsedArgs, params []string) int {
+	rootdir := resolveProjectRootDir(context)
+
+	tool, targs, err := resolveRelease(context, rootdir, params)
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
+		return -1
+	}
+
+	rargs := ParseArgs(targs)
+	for flag := range args.Gum {
+		rargs.Gum[flag] = struct{}{}
+	}
+
+	var config *Config
+	var inv invocation
+	if tool == "jreleaser" {
+		config = ReadConfig(context, rootdir)
+		if rargs.HasGumFlag("gq") {
+			config.setQuiet(true)
+		}
+		jreleaser, _ := findJReleaserExec(context)
+		inv = invocation{
+			tool:       "jreleaser",
+			executable: jreleaser,
+			rootDir:    rootdir,
+			workDir:    rootdir,
+			args:       &rargs}
+		if !config.general.quiet {
+			fmt.Fprintln(gumOutput, "Using JReleaser at '"+jreleaser+"' to release project at '"+rootdir+"':")
+		}
+	} else {
+		config, inv, err = configureTool(context, tool, &rargs)
+		if err != nil {
+			fmt.Fprintln(gumOutput, err)
+			return -1
+		}
+	}
+
+	if executeCommand(context, config, inv) != nil {
+		return -1
+	}
+	return 0
+}
+
+// Resolves the tool and args used to release the project found at rootdir
+func resolveRelease(context Context, rootdir string, params []string) (string, []string, error) {
+	command := "full-release"
+	if len(params) > 0 && !strings.HasPrefix(params[0], "-") {
+		command = params[0]
+		params = params[1:]
+	}
+
+	configFile := findJReleaserConfigFile(context, rootdir)
+	_, noJReleaser := findJReleaserExec(context)
+	if len(configFile) > 0 && noJReleaser == nil {
+		return "jreleaser", append([]string{command}, params...), nil
+	}
+
+	if hasJReleaserPlugin(filepath.Join(rootdir, "build.gradle"), filepath.Join(rootdir, "build.gradle.kts")) {
+		return "gradle", append([]string{jreleaserGradleTask(command)}, params...), nil
+	}
+	if hasJReleaserPlugin(filepath.Join(rootdir, "pom.xml")) {
+		return "maven", append([]string{"jreleaser:" + command}, params...), nil
+	}
+
+	if len(configFile) > 0 {
+		return "", nil, errors.New("No " + resolveJReleaserExec(context) + " found in path. Please install JReleaser (https://jreleaser.org/guide/latest/install.html)")
+	}
+	return "", nil, errors.New("Did not find a JReleaser config file nor the JReleaser Gradle/Maven plugin")
+}
+
+// Finds the JReleaser config file at the given dir
+func findJReleaserConfigFile(context Context, dir string) string {
+	for _, name := range jreleaserConfigFiles {
+		path := filepath.Join(dir, name)
+		if context.FileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// Checks if any of the given build files applies the JReleaser plugin
+func hasJReleaserPlugin(buildFiles ...string) bool {
+	for _, buildFile := range buildFiles {
+		data, err := ioutil.ReadFile(buildFile)
+		if err != nil {
+			continue
+		}
+		content := string(data)
+		if strings.Contains(content, "org.jreleaser") || strings.Contains(content, "jreleaser-maven-plugin") {
+			return true
+		}
+	}
+	return false
+}
+
+// Converts a JReleaser command such as full-release into its Gradle task, jreleaserFullRelease
+func jreleaserGradleTask(command string) string {
+	task := "jreleaser"
+	for _, part := range strings.Split(command, "-") {
+		if len(part) > 0 {
+			task += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return task
+}
+
+// Finds the jreleaser executable
+func findJReleaserExec(context Context) (string, error) {
+	jreleaser := resolveJReleaserExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], jreleaser)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(jreleaser + " not found")
+}
+
+// Resolves the jreleaser executable (OS dependent)
+func resolveJReleaserExec(context Context) string {
+	if context.IsWindows() {
+		return "jreleaser.bat"
+	}
+	return "jreleaser"
+}
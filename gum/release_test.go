@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveRelease(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "release", "bin"))
+
+	var checks = []struct {
+		title    string
+		dir      string
+		paths    []string
+		params   []string
+		tool     string
+		expected string
+	}{
+		{"Cli", "cli", []string{bin}, []string{}, "jreleaser", "full-release"},
+		{"CliCommand", "cli", []string{bin}, []string{"release", "--dry-run"}, "jreleaser", "release --dry-run"},
+		{"Gradle", "gradle", []string{bin}, []string{"full-release"}, "gradle", "jreleaserFullRelease"},
+		{"Maven", "maven", []string{}, []string{"assemble"}, "maven", "jreleaser:assemble"},
+		{"CliMissing", "cli", []string{}, []string{}, "", ""},
+	}
+
+	for _, check := range checks {
+		rootdir, _ := filepath.Abs(filepath.Join("..", "tests", "release", check.dir))
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: rootdir,
+			paths:      check.paths}
+
+		// when:
+		tool, args, err := resolveRelease(context, rootdir, check.params)
+
+		// then:
+		if len(check.tool) == 0 {
+			if err == nil {
+				t.Errorf("%s: expected an error", check.title)
+			}
+			continue
+		}
+		if tool != check.tool || strings.Join(args, " ") != check.expected {
+			t.Errorf("%s: got %s %v, want %s %s", check.title, tool, args, check.tool, check.expected)
+		}
+	}
+}
+
+func TestJReleaserGradleTask(t *testing.T) {
+	var checks = []struct {
+		command, expected string
+	}{
+		{"full-release", "jreleaserFullRelease"},
+		{"config", "jreleaserConfig"},
+		{"auto-config", "jreleaserAutoConfig"},
+	}
+
+	for _, check := range checks {
+		if actual := jreleaserGradleTask(check.command); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.command, actual, check.expected)
+		}
+	}
+}
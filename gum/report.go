@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// discoveryReport describes what was found while discovering a project, what was
+// missing, what was assumed, and which fallback was chosen (if any)
+type discoveryReport struct {
+	Tool     string        `json:"tool"`
+	Found    []reportEntry `json:"found"`
+	Missing  []string      `json:"missing"`
+	Assumed  []string      `json:"assumed"`
+	Fallback string        `json:"fallback,omitempty"`
+	Hints    []string      `json:"hints,omitempty"`
+}
+
+type reportEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+func newDiscoveryReport(tool string) *discoveryReport {
+	return &discoveryReport{
+		Tool:    tool,
+		Found:   make([]reportEntry, 0),
+		Missing: make([]string, 0),
+		Assumed: make([]string, 0)}
+}
+
+// Records name as found at path, or as missing if path is empty
+func (r *discoveryReport) check(name string, path string) {
+	if len(path) > 0 {
+		r.Found = append(r.Found, reportEntry{Name: name, Path: path})
+	} else {
+		r.Missing = append(r.Missing, name)
+	}
+}
+
+func (r *discoveryReport) assume(assumption string) {
+	r.Assumed = append(r.Assumed, assumption)
+}
+
+func (r *discoveryReport) fallback(fallback string, hints ...string) {
+	r.Fallback = fallback
+	r.Hints = append(r.Hints, hints...)
+}
+
+// Prints the report when a fallback was chosen, unless quiet. Debug prints the report as JSON
+func (r *discoveryReport) emit(config *Config, args *ParsedArgs) {
+	if args.HasGumFlag("gd") {
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err == nil {
+			fmt.Fprintln(gumOutput, string(data))
+		}
+	}
+
+	if config.general.quiet || len(r.Fallback) == 0 {
+		return
+	}
+
+	found := make([]string, 0)
+	for _, e := range r.Found {
+		found = append(found, e.Name+" ("+e.Path+")")
+	}
+
+	fmt.Fprintln(gumOutput, r.Tool+" discovery report")
+	printReportLine("found", found)
+	printReportLine("missing", r.Missing)
+	printReportLine("assumed", r.Assumed)
+	printReportLine("fallback", []string{r.Fallback})
+	for _, hint := range r.Hints {
+		fmt.Fprintln(gumOutput, "  "+hint)
+	}
+	fmt.Fprintln(gumOutput)
+}
+
+func printReportLine(label string, values []string) {
+	if len(values) > 0 {
+		fmt.Fprintln(gumOutput, "  "+pad(label+":", 10)+strings.Join(values, ", "))
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiscoveryReport(t *testing.T) {
+	// given:
+	report := newDiscoveryReport("Gradle")
+
+	// when:
+	report.check("gradlew", "")
+	report.check("settings file", "/p/settings.gradle")
+	report.check("build file", "")
+	report.fallback("running with the settings file /p/settings.gradle")
+	data, _ := json.Marshal(report)
+
+	// then:
+	expected := `{"tool":"Gradle",` +
+		`"found":[{"name":"settings file","path":"/p/settings.gradle"}],` +
+		`"missing":["gradlew","build file"],` +
+		`"assumed":[],` +
+		`"fallback":"running with the settings file /p/settings.gradle"}`
+	if string(data) != expected {
+		t.Errorf("got %s, want %s", string(data), expected)
+	}
+}
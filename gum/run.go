@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runStep is a single tool invocation of "gm gum run"
+type runStep struct {
+	line            string
+	continueOnError bool
+	onlyIfFailed    bool
+	status          string
+	duration        time.Duration
+}
+
+var forcedToolFlags = map[string]string{
+	"ga":    "ant",
+	"gb":    "bach",
+	"gbk":   "buck2",
+	"gbld":  "bld",
+	"gbt":   "boot",
+	"gcl":   "clojure",
+	"gcm":   "cmake",
+	"gg":    "gradle",
+	"ggr":   "grails",
+	"gj":    "jbang",
+	"gl":    "lein",
+	"gm":    "maven",
+	"gmk":   "make",
+	"gnode": "node",
+	"gs":    "sbt",
+	"gz":    "bazel",
+}
+
+// Handles "gm gum run [options] <step> [[options] <step> ...]". Each step is a command line
+// executed with the tool resolved for the current project. Stops at the first failure unless
+// the failed step is marked with --continue-on-error. Steps marked with --only-if-failed run
+// only after a failure
+func runRunCommand(context Context, args *ParsedArgs, params []string) int {
+	steps := parseRunSteps(params)
+	if len(steps) == 0 {
+		fmt.Fprintln(gumOutput, "Usage: gm gum run [--continue-on-error|--only-if-failed] <step> ...")
+		fmt.Fprintln(gumOutput, "Example: gm gum run clean \"build -x test\" --only-if-failed \"help --scan\"")
+		return -1
+	}
+
+	tool := resolveRunTool(context, args)
+	if len(tool) == 0 {
+		fmt.Fprintln(gumOutput, noProjectMessage())
+		return -1
+	}
+
+	exitCode := runSteps(context, tool, args.Gum, steps)
+	printRunSummary(steps)
+	return exitCode
+}
+
+// Parses steps. Options apply to the step that follows them
+func parseRunSteps(params []string) []runStep {
+	steps := make([]runStep, 0)
+	step := runStep{status: "skipped"}
+
+	for _, param := range params {
+		switch param {
+		case "--continue-on-error":
+			step.continueOnError = true
+		case "--only-if-failed":
+			step.onlyIfFailed = true
+		default:
+			step.line = param
+			steps = append(steps, step)
+			step = runStep{status: "skipped"}
+		}
+	}
+
+	return steps
+}
+
+// Runs steps in order. Returns -1 if any step failed, unless marked with --continue-on-error
+func runSteps(context Context, tool string, flags map[string]struct{}, steps []runStep) int {
+	exitCode := 0
+	failed := false
+	halted := false
+
+	for i := range steps {
+		step := &steps[i]
+		if (step.onlyIfFailed && !failed) || (!step.onlyIfFailed && halted) {
+			continue
+		}
+
+		fmt.Fprintln(gumOutput, "==> ["+strconv.Itoa(i+1)+"/"+strconv.Itoa(len(steps))+"] "+step.line)
+
+		start := time.Now()
+		err := executeStep(context, tool, flags, step.line)
+		step.duration = time.Since(start)
+
+		if err == nil {
+			step.status = "ok"
+			continue
+		}
+
+		failed = true
+		if step.continueOnError {
+			step.status = "ignored"
+		} else {
+			step.status = "failed"
+			halted = true
+			exitCode = -1
+		}
+	}
+
+	return exitCode
+}
+
+// Resolves the tool to use, either forced with a flag or the highest scored detection
+func resolveRunTool(context Context, args *ParsedArgs) string {
+	if tool := resolveForcedTool(args); len(tool) > 0 {
+		return tool
+	}
+
+	if isJbangScript(context, args) {
+		return "jbang"
+	}
+
+	config := ReadUserConfig(context)
+	config.merge(nil)
+
+	probe := ParseArgs([]string{})
+	if d := detectTool(context, config, &probe); d != nil {
+		return d.tool
+	}
+
+	return ""
+}
+
+// Executes a single step. Gum flags given to "gm gum run" apply to every step
+func executeStep(context Context, tool string, flags map[string]struct{}, line string) error {
+	args := ParseArgs(splitCommandLine(line))
+	for flag := range flags {
+		args.Gum[flag] = struct{}{}
+	}
+
+	config, inv, err := configureTool(context, tool, &args)
+	if err != nil {
+		return err
+	}
+	return executeCommand(context, config, inv)
+}
+
+// Finds and configures the given tool, resolving its invocation without executing it
+func configureTool(context Context, tool string, args *ParsedArgs) (*Config, invocation, error) {
+	config, inv, err := findConfiguredTool(context, tool, args)
+	if err != nil {
+		return config, inv, err
+	}
+	if err := prepareInvocation(context, config, &inv); err != nil {
+		return config, inv, err
+	}
+	return config, inv, nil
+}
+
+func findConfiguredTool(context Context, tool string, args *ParsedArgs) (*Config, invocation, error) {
+	switch tool {
+	case "gradle":
+		if c := FindGradle(context, args); c != nil {
+			c.doConfigureGradle()
+			return c.config, c.invocation(), nil
+		}
+	case "maven":
+		if c := FindMaven(context, args); c != nil {
+			c.doConfigureMaven()
+			return c.config, c.invocation(), nil
+		}
+	case "sbt":
+		if c := FindSbt(context, args); c != nil {
+			c.doConfigureSbt()
+			return c.config, c.invocation(), nil
+		}
+	case "bazel":
+		if c := FindBazel(context, args); c != nil {
+			c.doConfigureBazel()
+			return c.config, c.invocation(), nil
+		}
+	case "lein":
+		if c := FindLein(context, args); c != nil {
+			c.doConfigureLein()
+			return c.config, c.invocation(), nil
+		}
+	case "bld":
+		if c := FindBld(context, args); c != nil {
+			c.doConfigureBld()
+			return c.config, c.invocation(), nil
+		}
+	case "clojure":
+		if c := FindClojure(context, args); c != nil {
+			c.doConfigureClojure()
+			return c.config, c.invocation(), nil
+		}
+	case "grails":
+		if c := FindGrails(context, args); c != nil {
+			c.doConfigureGrails()
+			return c.config, c.invocation(), nil
+		}
+	case "node":
+		if c := FindNode(context, args); c != nil {
+			c.doConfigureNode()
+			return c.config, c.invocation(), nil
+		}
+	case "cmake":
+		if c := FindCMake(context, args); c != nil {
+			c.doConfigureCMake()
+			return c.config, c.invocation(), nil
+		}
+	case "make":
+		if c := FindMake(context, args); c != nil {
+			c.doConfigureMake()
+			return c.config, c.invocation(), nil
+		}
+	case "buck2":
+		if c := FindBuck2(context, args); c != nil {
+			c.doConfigureBuck2()
+			return c.config, c.invocation(), nil
+		}
+	case "boot":
+		if c := FindBoot(context, args); c != nil {
+			c.doConfigureBoot()
+			return c.config, c.invocation(), nil
+		}
+	case "ant":
+		if c := FindAnt(context, args); c != nil {
+			c.doConfigureAnt()
+			return c.config, c.invocation(), nil
+		}
+	case "bach":
+		if c := FindBach(context, args); c != nil {
+			c.doConfigureBach()
+			return c.config, c.invocation(), nil
+		}
+	case "jbang":
+		if c := FindJbang(context, args); c != nil {
+			c.doConfigureJbang()
+			return c.config, c.invocation(), nil
+		}
+	default:
+		config := ReadUserConfig(context)
+		if custom, ok := config.tools[tool]; ok {
+			if c := FindCustom(context, args, tool, custom); c != nil {
+				c.doConfigureCustom()
+				return c.config, c.invocation(), nil
+			}
+		}
+	}
+
+	return nil, invocation{}, errors.New("Did not find a " + tool + " project")
+}
+
+func printRunSummary(steps []runStep) {
+	width := 0
+	for _, step := range steps {
+		if len(step.line) > width {
+			width = len(step.line)
+		}
+	}
+
+	fmt.Fprintln(gumOutput, "")
+	fmt.Fprintln(gumOutput, "Summary")
+	for _, step := range steps {
+		line := "  " + pad(step.line, width) + "  " + pad(step.status, 7)
+		if step.status != "skipped" {
+			line = line + "  " + formatDuration(step.duration)
+		}
+		fmt.Fprintln(gumOutput, strings.TrimRight(line, " "))
+	}
+}
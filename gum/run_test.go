@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunStopsAtFirstFailure(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "single-with-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd,
+		homeDir:    home,
+		paths:      []string{bin}}
+
+	args := ParseArgs([]string{"-gq", "gum", "run", "clean", "build"})
+	steps := []runStep{{line: "clean", status: "skipped"}, {line: "build", status: "skipped"}}
+
+	// when:
+	tool := resolveRunTool(context, &args)
+	exitCode := runSteps(context, tool, args.Gum, steps)
+
+	// then:
+	if tool != "gradle" {
+		t.Errorf("Expected gradle, got %s", tool)
+	}
+	// the wrapper in tests is not executable
+	if exitCode == 0 {
+		t.Error("Expected run to fail")
+	}
+	if steps[0].status != "failed" || steps[1].status != "skipped" {
+		t.Errorf("Unexpected step status %s, %s", steps[0].status, steps[1].status)
+	}
+}
+
+func TestRunConditionalSteps(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "single-with-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd,
+		homeDir:    home,
+		paths:      []string{bin}}
+
+	args := ParseArgs([]string{"-gq"})
+	steps := parseRunSteps([]string{"--continue-on-error", "check", "build", "--only-if-failed", "help --scan"})
+
+	// when:
+	exitCode := runSteps(context, "gradle", args.Gum, steps)
+
+	// then:
+	if len(steps) != 3 || !steps[0].continueOnError || !steps[2].onlyIfFailed || steps[2].line != "help --scan" {
+		t.Errorf("Unexpected steps %v", steps)
+		return
+	}
+	// the wrapper in tests is not executable
+	if exitCode == 0 {
+		t.Error("Expected run to fail")
+	}
+	if steps[0].status != "ignored" || steps[1].status != "failed" || steps[2].status != "failed" {
+		t.Errorf("Unexpected step status %s, %s, %s", steps[0].status, steps[1].status, steps[2].status)
+	}
+}
@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SbtCommand defines an executable sbt command
+type SbtCommand struct {
+	context    Context
+	config     *Config
+	rootdir    string
+	executable string
+	args       *ParsedArgs
+	buildFile  string
+}
+
+// Execute executes the given command
+func (c SbtCommand) Execute() int {
+	c.doConfigureSbt()
+	return ExitCode(c.doExecuteSbt())
+}
+
+func (c *SbtCommand) doConfigureSbt() {
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using sbt at '"+c.executable+"'")
+	banner = append(banner, "to run build at '"+c.rootdir+"':")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	input := appendSafe(appendSafe(make([]string, 0), c.args.Tool), c.args.Args)
+	expandAliases(c.config, c.args)
+	oargs := c.args.Args
+
+	args = appendSafe(args, resolveBranchArgs(c.context, c.config, "sbt"))
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugSbt(c.config, oargs)
+	showArgsDiff(c.config, c.args, input)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(gumOutput, strings.Join(banner, " "))
+	}
+}
+
+func (c *SbtCommand) doExecuteSbt() error {
+	return executeCommand(c.context, c.config, c.invocation())
+}
+
+// Resolves the invocation of the configured command.
+// Resolves the config of the command
+func (c *SbtCommand) configuration() *Config {
+	return c.config
+}
+
+// sbt resolves the build from the current directory thus it's launched from the root
+func (c *SbtCommand) invocation() invocation {
+	return invocation{
+		tool:       "sbt",
+		executable: c.executable,
+		rootDir:    c.rootdir,
+		buildFile:  c.buildFile,
+		workDir:    c.rootdir,
+		args:       c.args}
+}
+
+func (c *SbtCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print()
+		os.Exit(0)
+	}
+}
+
+func (c *SbtCommand) debugSbt(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(gumOutput, "rootdir            = ", c.rootdir)
+		fmt.Fprintln(gumOutput, "executable         = ", c.executable)
+		fmt.Fprintln(gumOutput, "buildFile          = ", c.buildFile)
+		fmt.Fprintln(gumOutput, "original args      = ", oargs)
+		fmt.Fprintln(gumOutput, "actual args        = ", c.args.Args)
+		fmt.Fprintln(gumOutput, "")
+	}
+}
+
+// FindSbt finds and executes sbt
+func FindSbt(context Context, args *ParsedArgs) *SbtCommand {
+	pwd := context.GetWorkingDir()
+
+	rootdir, noRootDir := findSbtRootDir(context, pwd)
+	if noRootDir != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(gumOutput, "No sbt project found")
+			fmt.Fprintln(gumOutput)
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	sbtx, noSbtx := findSbtWrapperExec(context, pwd, rootdir)
+	sbt, noSbt := findSbtExec(context)
+
+	var executable string
+	if noSbtx == nil {
+		executable = sbtx
+	} else if noSbt == nil {
+		executable = sbt
+	} else {
+		warnNoSbt(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	buildFile := filepath.Join(rootdir, "build.sbt")
+	if !context.FileExists(buildFile) {
+		buildFile = ""
+	}
+
+	return &SbtCommand{
+		context:    context,
+		config:     config,
+		rootdir:    rootdir,
+		executable: executable,
+		args:       args,
+		buildFile:  buildFile}
+}
+
+func warnNoSbt(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(gumOutput, "No %s found in path. Please install sbt.", resolveSbtExec(context))
+		fmt.Fprintln(gumOutput)
+		fmt.Fprintln(gumOutput, "(https://www.scala-sbt.org/download.html)")
+		fmt.Fprintln(gumOutput)
+	}
+}
+
+// Finds the root of an sbt build. Subprojects may define their own build.sbt
+// but only the root defines project/build.properties, thus the topmost directory
+// with project/build.properties wins, otherwise the nearest with build.sbt
+func findSbtRootDir(context Context, dir string) (string, error) {
+	rootdir := ""
+	nearest := ""
+
+	for {
+		if context.FileExists(filepath.Join(dir, "project", "build.properties")) {
+			rootdir = dir
+		} else if len(nearest) == 0 && context.FileExists(filepath.Join(dir, "build.sbt")) {
+			nearest = dir
+		}
+
+		parentdir := filepath.Join(dir, "..")
+		if parentdir == dir || isSearchBoundary(context, dir) {
+			break
+		}
+		dir = parentdir
+	}
+
+	if len(rootdir) > 0 {
+		return filepath.Abs(rootdir)
+	}
+	if len(nearest) > 0 {
+		return filepath.Abs(nearest)
+	}
+	return "", errors.New("Did not find build.sbt")
+}
+
+// Finds a project local sbt launcher script, from the current directory up to the root
+func findSbtWrapperExec(context Context, dir string, rootdir string) (string, error) {
+	sbtx := resolveSbtWrapperExec(context)
+
+	for {
+		path := filepath.Join(dir, sbtx)
+		if context.FileExists(path) {
+			return filepath.Abs(path)
+		}
+
+		parentdir := filepath.Join(dir, "..")
+		if dir == rootdir || parentdir == dir {
+			break
+		}
+		dir = parentdir
+	}
+
+	return "", errors.New(sbtx + " not found")
+}
+
+// Finds the sbt executable
+func findSbtExec(context Context) (string, error) {
+	sbt := resolveSbtExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], sbt)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(sbt + " not found")
+}
+
+// Resolves the sbt launcher script (OS dependent)
+func resolveSbtWrapperExec(context Context) string {
+	if context.IsWindows() {
+		return "sbtx.bat"
+	}
+	return "sbtx"
+}
+
+// Resolves the sbt executable (OS dependent)
+func resolveSbtExec(context Context) string {
+	if context.IsWindows() {
+		return "sbt.bat"
+	}
+	return "sbt"
+}
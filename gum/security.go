@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Verifies that the executable and its wrapper jar (if any) are listed
+// in the checksum allowlist set by policy
+func verifyChecksums(context Context, config *Config, executable string) bool {
+	if len(config.policy.checksums) == 0 {
+		return true
+	}
+
+	allowed, err := readChecksums(config.policy.checksums)
+	if err != nil {
+		fmt.Fprintln(gumOutput, "Could not read checksum allowlist "+config.policy.checksums)
+		fmt.Fprintln(gumOutput, err)
+		return false
+	}
+
+	files := []string{executable}
+	jar := resolveWrapperJar(context, executable)
+	if len(jar) > 0 {
+		files = append(files, jar)
+	}
+
+	verified := true
+	for _, file := range files {
+		checksum, err := computeChecksum(file)
+		if err != nil {
+			fmt.Fprintln(gumOutput, "Could not compute checksum of "+file)
+			fmt.Fprintln(gumOutput, err)
+			verified = false
+			continue
+		}
+
+		if _, ok := allowed[checksum]; !ok {
+			fmt.Fprintln(gumOutput, "Checksum of "+file+" is not allowed")
+			fmt.Fprintln(gumOutput, "  sha256    = "+checksum)
+			fmt.Fprintln(gumOutput, "  allowlist = "+config.policy.checksums)
+			verified = false
+		}
+	}
+
+	return verified
+}
+
+// Reads a checksum allowlist. Each line follows the format used by sha256sum
+// where the first column is the checksum and the rest is informational
+func readChecksums(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	checksums := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		checksums[strings.ToLower(strings.Fields(line)[0])] = struct{}{}
+	}
+
+	return checksums, scanner.Err()
+}
+
+func computeChecksum(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(resolved)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Verifies that wrapper distribution URLs point to hosts allowed by policy.
+// Returns false only if the policy mode is set to block
+func verifyDistributionHosts(context Context, config *Config, executable string) bool {
+	if len(config.policy.hosts) == 0 {
+		return true
+	}
+
+	path := resolveWrapperProperties(context, executable)
+	if len(path) == 0 {
+		return true
+	}
+
+	props, err := readProperties(path)
+	if err != nil {
+		fmt.Fprintln(gumOutput, "Could not read "+path)
+		fmt.Fprintln(gumOutput, err)
+		return config.policy.hostsMode != "block"
+	}
+
+	verified := true
+	for _, key := range []string{"distributionUrl", "wrapperUrl"} {
+		value, ok := props[key]
+		if !ok {
+			continue
+		}
+
+		host := ""
+		u, err := url.Parse(value)
+		if err == nil {
+			host = u.Hostname()
+		}
+
+		if !isAllowedHost(host, config.policy.hosts) {
+			if config.policy.hostsMode == "block" {
+				fmt.Fprintln(gumOutput, "Refusing to run "+executable)
+				verified = false
+			} else {
+				fmt.Fprintln(gumOutput, "WARNING: unexpected host found in "+path)
+			}
+			fmt.Fprintln(gumOutput, "  "+key+" = "+value)
+			fmt.Fprintln(gumOutput, "  allowed hosts = "+strings.Join(config.policy.hosts, ", "))
+		}
+	}
+
+	return verified
+}
+
+// Matches a host against a list of allowed hosts. Entries may use a leading
+// wildcard such as *.example.com to match subdomains
+func isAllowedHost(host string, hosts []string) bool {
+	host = strings.ToLower(host)
+	for _, h := range hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == host {
+			return true
+		}
+		if strings.HasPrefix(h, "*.") && strings.HasSuffix(host, h[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifies the tool version is at least the minimum version required by
+// policy or config. Returns false only if the policy mode is set to block.
+// May run the tool with --version, thus it must be called after verifyTrust
+func verifyMinimumVersion(context Context, config *Config, inv invocation) bool {
+	var required string
+	var configured string
+	switch inv.tool {
+	case "gradle":
+		required = config.policy.gradleVersion
+		configured = config.gradle.minVersion
+	case "maven":
+		required = config.policy.mavenVersion
+		configured = config.maven.minVersion
+	}
+
+	if len(required) == 0 && len(configured) == 0 {
+		return true
+	}
+
+	version := resolveToolVersion(context, inv.tool, inv.executable)
+	if len(version) == 0 {
+		fmt.Fprintln(gumOutput, "WARNING: could not resolve the "+inv.tool+" version of "+inv.executable)
+		return true
+	}
+
+	if len(required) > 0 && compareVersions(version, required) < 0 {
+		if config.policy.versionsMode == "block" {
+			fmt.Fprintln(gumOutput, "Refusing to run "+inv.tool+" "+version+". Minimum required version is "+required)
+			return false
+		}
+		fmt.Fprintln(gumOutput, "WARNING: "+inv.tool+" "+version+" is older than the minimum required version "+required)
+	} else if len(configured) > 0 && compareVersions(version, configured) < 0 {
+		fmt.Fprintln(gumOutput, "WARNING: "+inv.tool+" "+version+" is older than the minimum required version "+configured)
+	}
+
+	return true
+}
+
+// Verifies the detached signature (path + ".sig") of a project config file
+// against the given public keys
+func verifyConfigSignature(path string, keysFile string) bool {
+	signature, err := ioutil.ReadFile(path + ".sig")
+	if err != nil {
+		fmt.Fprintln(gumOutput, "Missing signature for "+path)
+		return false
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		fmt.Fprintln(gumOutput, "Invalid signature for "+path)
+		fmt.Fprintln(gumOutput, err)
+		return false
+	}
+
+	doc, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
+		return false
+	}
+
+	keys, err := readPublicKeys(keysFile)
+	if err != nil {
+		fmt.Fprintln(gumOutput, "Could not read public keys from "+keysFile)
+		fmt.Fprintln(gumOutput, err)
+		return false
+	}
+
+	for _, key := range keys {
+		if ed25519.Verify(key, doc, sig) {
+			return true
+		}
+	}
+
+	fmt.Fprintln(gumOutput, "Signature verification failed for "+path)
+	return false
+}
+
+// Reads PEM encoded ed25519 public keys
+func readPublicKeys(path string) ([]ed25519.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]ed25519.PublicKey, 0)
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		data = rest
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if k, ok := key.(ed25519.PublicKey); ok {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys, nil
+}
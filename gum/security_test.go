@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksumsAllowed(t *testing.T) {
+	// given:
+	checksums, _ := filepath.Abs(filepath.Join("..", "tests", "security", "checksums.txt"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "single-with-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd}
+
+	config := newConfig()
+	config.policy.checksums = checksums
+
+	// when:
+	verified := verifyChecksums(context, config, filepath.Join(pwd, "gradlew"))
+
+	// then:
+	if !verified {
+		t.Error("Expected executable to be allowed")
+	}
+}
+
+func TestChecksumsNotAllowed(t *testing.T) {
+	// given:
+	checksums, _ := filepath.Abs(filepath.Join("..", "tests", "security", "checksums.txt"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "security"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd}
+
+	config := newConfig()
+	config.policy.checksums = checksums
+
+	// when:
+	verified := verifyChecksums(context, config, filepath.Join(pwd, "gradlew"))
+
+	// then:
+	if verified {
+		t.Error("Expected executable to be rejected")
+	}
+}
+
+func TestDistributionHosts(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "security", "wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd}
+
+	var checks = []struct {
+		title    string
+		hosts    []string
+		mode     string
+		expected bool
+	}{
+		{"Allowed", []string{"downloads.example.com"}, "block", true},
+		{"Wildcard", []string{"*.example.com"}, "block", true},
+		{"Warn", []string{"services.gradle.org"}, "warn", true},
+		{"Block", []string{"services.gradle.org"}, "block", false},
+	}
+
+	for _, check := range checks {
+		config := newConfig()
+		config.policy.hosts = check.hosts
+		config.policy.hostsMode = check.mode
+
+		// when:
+		actual := verifyDistributionHosts(context, config, filepath.Join(pwd, "gradlew"))
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %t, want %t", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestConfigSignature(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+
+	public, private, _ := ed25519.GenerateKey(nil)
+	der, _ := x509.MarshalPKIXPublicKey(public)
+	keys := filepath.Join(dir, "keys.pem")
+	ioutil.WriteFile(keys, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644)
+
+	config := filepath.Join(dir, ".gm.toml")
+	doc := []byte("[general]\nquiet = true\n")
+	ioutil.WriteFile(config, doc, 0644)
+	ioutil.WriteFile(config+".sig", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, doc))), 0644)
+
+	// when:
+	verified := verifyConfigSignature(config, keys)
+	ioutil.WriteFile(config, []byte("[general]\nquiet = false\n"), 0644)
+	tampered := verifyConfigSignature(config, keys)
+
+	// then:
+	if !verified {
+		t.Error("Expected config signature to be verified")
+	}
+	if tampered {
+		t.Error("Expected tampered config to fail verification")
+	}
+}
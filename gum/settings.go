@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var gradleIncludePattern = regexp.MustCompile(`(?m)^\s*include\b\s*\(?([^)\n]*)`)
+var quotedStringPattern = regexp.MustCompile(`['"]([^'"]+)['"]`)
+var gradleProjectDirPattern = regexp.MustCompile(`project\(\s*['"]([^'"]+)['"]\s*\)\.projectDir\s*=\s*(?:new\s+)?(?:file|File)\(\s*(?:(?:rootDir|settingsDir|rootProject\.projectDir)\s*,\s*)?['"]([^'"]+)['"]`)
+var gradleIncludeBuildPattern = regexp.MustCompile(`(?m)^\s*includeBuild\b\s*\(?\s*['"]([^'"]+)['"]`)
+
+// Reads the project paths included by a Gradle settings file, such as
+// include 'core', ':plugins:foo' (Groovy) or include(":core") (Kotlin)
+func readGradleIncludes(settingsFile string) []string {
+	includes := make([]string, 0)
+
+	doc, err := ioutil.ReadFile(settingsFile)
+	if err != nil {
+		return includes
+	}
+
+	for _, m := range gradleIncludePattern.FindAllStringSubmatch(string(doc), -1) {
+		for _, q := range quotedStringPattern.FindAllStringSubmatch(m[1], -1) {
+			path := q[1]
+			if !strings.HasPrefix(path, ":") {
+				path = ":" + path
+			}
+			includes = append(includes, path)
+		}
+	}
+
+	return includes
+}
+
+// Reads the dirs of the builds included by a Gradle settings file, such as
+// includeBuild '../plugins' (Groovy) or includeBuild("build-logic") (Kotlin)
+func readGradleIncludedBuilds(settingsFile string) []string {
+	dirs := make([]string, 0)
+
+	doc, err := ioutil.ReadFile(settingsFile)
+	if err != nil {
+		return dirs
+	}
+
+	settingsDir := filepath.Dir(settingsFile)
+	for _, m := range gradleIncludeBuildPattern.FindAllStringSubmatch(string(doc), -1) {
+		dir := filepath.FromSlash(m[1])
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(settingsDir, dir)
+		}
+		dirs = append(dirs, filepath.Clean(dir))
+	}
+
+	return dirs
+}
+
+// Reads the dirs of projects relocated by a Gradle settings file, per project path, such as
+// project(':core').projectDir = file('modules/core') or File(rootDir, "modules/core")
+func readGradleProjectDirs(settingsFile string) map[string]string {
+	dirs := make(map[string]string)
+
+	doc, err := ioutil.ReadFile(settingsFile)
+	if err != nil {
+		return dirs
+	}
+
+	settingsDir := filepath.Dir(settingsFile)
+	for _, m := range gradleProjectDirPattern.FindAllStringSubmatch(string(doc), -1) {
+		path := m[1]
+		if !strings.HasPrefix(path, ":") {
+			path = ":" + path
+		}
+		dir := filepath.FromSlash(m[2])
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(settingsDir, dir)
+		}
+		dirs[path] = filepath.Clean(dir)
+	}
+
+	return dirs
+}
+
+// Resolves the path (such as :core) of the included project that contains dir, the deepest one
+// if projects are nested. Projects are located after their path, such as :plugins:foo at plugins/foo,
+// unless relocated by projectDirs. Returns an empty string if dir is not in an included project of rootDir
+func resolveGradleProjectPath(rootDir string, dir string, includes []string, projectDirs map[string]string) string {
+	path, depth := "", 0
+
+	for _, include := range includes {
+		// including :plugins:foo includes :plugins too
+		segments := strings.Split(strings.TrimPrefix(include, ":"), ":")
+		for i := range segments {
+			candidate := ":" + strings.Join(segments[:i+1], ":")
+			projectDir, ok := projectDirs[candidate]
+			if !ok {
+				projectDir = filepath.Join(append([]string{rootDir}, segments[:i+1]...)...)
+			}
+
+			if projectDir != rootDir && isParentDir(projectDir, dir) && len(projectDir) > depth {
+				path, depth = candidate, len(projectDir)
+			}
+		}
+	}
+
+	return path
+}
+
+// Computes the path (such as :core) of the project located at dir relative to rootDir.
+// Returns an empty string if dir is rootDir or not one of its children
+func gradleProjectPath(rootDir string, dir string) string {
+	rel, err := filepath.Rel(rootDir, dir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return ":" + strings.Join(strings.Split(filepath.ToSlash(rel), "/"), ":")
+}
+
+// Qualifies task names with the given project path, such as build -> :core:build.
+// Tasks that are already absolute are left untouched
+func qualifyGradleTasks(projectPath string, args []string) []string {
+	qualified := make([]string, len(args))
+	copy(qualified, args)
+
+	for _, i := range findTaskArgs("gradle", qualified) {
+		if !strings.HasPrefix(qualified[i], ":") {
+			qualified[i] = projectPath + ":" + qualified[i]
+		}
+	}
+
+	return qualified
+}
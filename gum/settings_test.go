@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadGradleIncludes(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	groovy := filepath.Join(dir, "settings.gradle")
+	kotlin := filepath.Join(dir, "settings.gradle.kts")
+	ioutil.WriteFile(groovy, []byte("rootProject.name = 'app'\ninclude 'core', ':plugins:foo'\ninclude \"docs\"\n"), 0644)
+	ioutil.WriteFile(kotlin, []byte("pluginManagement {\n    includeBuild(\"build-logic\")\n}\ninclude(\":core\", \"cli\")\nincludeBuild(\"../plugins\")\n"), 0644)
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Groovy", strings.Join(readGradleIncludes(groovy), ","), ":core,:plugins:foo,:docs"},
+		{"Kotlin", strings.Join(readGradleIncludes(kotlin), ","), ":core,:cli"},
+		{"Missing", strings.Join(readGradleIncludes(filepath.Join(dir, "missing")), ","), ""},
+		{"ProjectPath", resolveGradleProjectPath(dir, filepath.Join(dir, "plugins", "foo"), readGradleIncludes(groovy), nil), ":plugins:foo"},
+		{"NotIncluded", resolveGradleProjectPath(dir, filepath.Join(dir, "other"), readGradleIncludes(groovy), nil), ""},
+		{"Root", resolveGradleProjectPath(dir, dir, readGradleIncludes(groovy), nil), ""},
+		{"IncludedBuilds", strings.Join(readGradleIncludedBuilds(kotlin), ","), filepath.Join(dir, "build-logic") + "," + filepath.Join(filepath.Dir(dir), "plugins")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestReadGradleProjectDirs(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	groovy := filepath.Join(dir, "settings.gradle")
+	kotlin := filepath.Join(dir, "settings.gradle.kts")
+	ioutil.WriteFile(groovy, []byte("include 'core', 'cli'\nproject(':core').projectDir = file('modules/core')\n"+
+		"project(\":cli\").projectDir = new File(rootDir, \"tools/cli\")\n"), 0644)
+	ioutil.WriteFile(kotlin, []byte("include(\"api\")\nproject(\":api\").projectDir = file(\"../shared/api\")\n"), 0644)
+	includes := readGradleIncludes(groovy)
+	projectDirs := readGradleProjectDirs(groovy)
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Groovy", projectDirs[":core"] + "," + projectDirs[":cli"], filepath.Join(dir, "modules", "core") + "," + filepath.Join(dir, "tools", "cli")},
+		{"Kotlin", readGradleProjectDirs(kotlin)[":api"], filepath.Join(filepath.Dir(dir), "shared", "api")},
+		{"Relocated", resolveGradleProjectPath(dir, filepath.Join(dir, "modules", "core", "src"), includes, projectDirs), ":core"},
+		{"DefaultDir", resolveGradleProjectPath(dir, filepath.Join(dir, "core"), includes, projectDirs), ""},
+		{"Nested", resolveGradleProjectPath(dir, filepath.Join(dir, "plugins", "foo", "src"), []string{":plugins", ":plugins:foo"}, nil), ":plugins:foo"},
+		{"ImplicitParent", resolveGradleProjectPath(dir, filepath.Join(dir, "plugins"), []string{":plugins:foo"}, nil), ":plugins"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestReadAmperModules(t *testing.T) {
+	// given:
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "amper"))
+
+	// when:
+	actual := strings.Join(readAmperModules(root), ",")
+
+	// then:
+	if actual != ":app,:libs:core" {
+		t.Errorf("got %s, want %s", actual, ":app,:libs:core")
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// promptSegment describes how a tool is displayed by "gm gum prompt"
+type promptSegment struct {
+	tool    string
+	icon    string
+	name    string
+	markers []string
+	wrapper func(context Context) string
+}
+
+var promptSegments = []promptSegment{
+	{tool: "gradle", icon: "🐘", name: "gradle",
+		markers: []string{"settings.gradle", "settings.gradle.kts", "build.gradle", "build.gradle.kts"},
+		wrapper: resolveGradleWrapperExec},
+	{tool: "maven", icon: "Ⓜ", name: "mvn",
+		markers: []string{"pom.xml"},
+		wrapper: resolveMavenWrapperExec},
+	{tool: "ant", icon: "🐜", name: "ant",
+		markers: []string{"build.xml"}},
+}
+
+// Handles "gm gum prompt [--no-icons]". Prints a compact segment such as
+// "🐘 gradle 7.0" suitable for shell prompts, or nothing if no project is found.
+// Detection only looks at files and never launches the build tool
+func runPromptCommand(context Context, args *ParsedArgs, params []string) int {
+	noIcons, _ := hasOption("--no-icons", params)
+
+	dir, _ := filepath.Abs(context.GetWorkingDir())
+	segment := findPromptSegment(context, dir)
+	if len(segment) > 0 && noIcons {
+		segment = segment[strings.Index(segment, " ")+1:]
+	}
+	if len(segment) > 0 {
+		fmt.Println(segment)
+	}
+
+	return 0
+}
+
+// Walks up from dir until a build file is found and formats its segment
+func findPromptSegment(context Context, dir string) string {
+	for {
+		for _, s := range promptSegments {
+			for _, marker := range s.markers {
+				if context.FileExists(filepath.Join(dir, marker)) {
+					return s.format(context, dir)
+				}
+			}
+		}
+
+		parentdir := filepath.Dir(dir)
+		if parentdir == dir {
+			return ""
+		}
+		dir = parentdir
+	}
+}
+
+func (s *promptSegment) format(context Context, dir string) string {
+	segment := s.icon + " " + s.name
+	if s.wrapper == nil {
+		return segment
+	}
+
+	// the wrapper may be located at the root of a multi-project build
+	for {
+		path := resolveWrapperProperties(context, filepath.Join(dir, s.wrapper(context)))
+		if len(path) > 0 {
+			props, err := readProperties(path)
+			if err == nil {
+				version := parseDistributionVersion(s.tool, props["distributionUrl"])
+				if len(version) > 0 {
+					return segment + " " + shortVersion(version)
+				}
+			}
+			return segment
+		}
+
+		parentdir := filepath.Dir(dir)
+		if parentdir == dir {
+			return segment
+		}
+		dir = parentdir
+	}
+}
+
+// Shortens a version such as 3.9.6 to 3.9
+func shortVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) > 2 {
+		return parts[0] + "." + parts[1]
+	}
+	return version
+}
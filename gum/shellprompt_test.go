@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPromptSegment(t *testing.T) {
+	// given:
+	root, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(root)
+	child := filepath.Join(root, "child")
+	os.MkdirAll(filepath.Join(root, "gradle", "wrapper"), 0755)
+	os.MkdirAll(child, 0755)
+	ioutil.WriteFile(filepath.Join(root, "settings.gradle"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(root, "gradlew"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(root, "gradle", "wrapper", "gradle-wrapper.properties"),
+		[]byte("distributionUrl=https\\://services.gradle.org/distributions/gradle-7.0.2-bin.zip\n"), 0644)
+	ioutil.WriteFile(filepath.Join(child, "build.gradle"), []byte(""), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: child}
+
+	// when:
+	segment := findPromptSegment(context, child)
+
+	// then:
+	if segment != "🐘 gradle 7.0" {
+		t.Errorf("Expected '🐘 gradle 7.0', got '%s'", segment)
+	}
+}
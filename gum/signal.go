@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"time"
+)
+
+// Time given to the child process to shut down once a signal has been forwarded
+const shutdownGracePeriod = 10 * time.Second
+
+// Runs cmd relaying SIGINT/SIGTERM to it, thus Gum exits only once the child has shut down.
+// The child and its process group are killed once the timeout expires, if greater than zero,
+// or once ctx is done. The output of the child is drained from pty, if not nil, before returning.
+// The started func, if not nil, is called right after the child has been started.
+// Reports whether a signal was received while the child was running
+func runForwardingSignals(ctx context.Context, cmd *exec.Cmd, timeout time.Duration, pty *ptyOutput, started func(*exec.Cmd)) (bool, error) {
+	defer pty.finish()
+
+	if timeout > 0 || ctx.Done() != nil {
+		setProcessGroup(cmd)
+	}
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+	pty.started()
+	if started != nil {
+		started(cmd)
+	}
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		deadline = time.After(timeout)
+	}
+
+	return superviseCommand(ctx, cmd, signals, shutdownGracePeriod, deadline, timeout)
+}
+
+// Waits for a started cmd. The first signal is forwarded to the child process;
+// a second signal, or the expiry of the grace period, kills it. Reports whether a signal was received
+func superviseCommand(ctx context.Context, cmd *exec.Cmd, signals <-chan os.Signal, grace time.Duration, deadline <-chan time.Time, timeout time.Duration) (bool, error) {
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var graceTimeout <-chan time.Time
+	for {
+		select {
+		case err := <-done:
+			return graceTimeout != nil, err
+		case sig := <-signals:
+			if graceTimeout != nil {
+				cmd.Process.Kill()
+				continue
+			}
+			forwardSignal(cmd, sig)
+			graceTimeout = time.After(grace)
+		case <-graceTimeout:
+			cmd.Process.Kill()
+		case <-deadline:
+			killProcessGroup(cmd)
+			<-done
+			return graceTimeout != nil, &TimeoutError{Timeout: timeout}
+		case <-ctx.Done():
+			killProcessGroup(cmd)
+			<-done
+			return graceTimeout != nil, ctx.Err()
+		}
+	}
+}
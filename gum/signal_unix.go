@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package gum
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+var forwardedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// Relays the signal to the child process, or to its process group if it has one.
+// Wrappers such as gradlew and mvnw exec the JVM, thus the signal reaches the build tool itself
+func forwardSignal(cmd *exec.Cmd, sig os.Signal) {
+	if hasProcessGroup(cmd) {
+		syscall.Kill(-cmd.Process.Pid, sig.(syscall.Signal))
+		return
+	}
+	cmd.Process.Signal(sig)
+}
+
+// Launches the child in its own process group so that it may be killed as a whole
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func hasProcessGroup(cmd *exec.Cmd) bool {
+	return cmd.SysProcAttr != nil && cmd.SysProcAttr.Setpgid
+}
+
+// Kills the child and its process group
+func killProcessGroup(cmd *exec.Cmd) {
+	if hasProcessGroup(cmd) {
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		return
+	}
+	cmd.Process.Kill()
+}
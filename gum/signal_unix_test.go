@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package gum
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSuperviseCommand(t *testing.T) {
+	var checks = []struct {
+		title, script string
+		expected      int
+	}{
+		{"GracefulShutdown", "trap 'exit 7' TERM; while :; do sleep 0.1; done", 7},
+		{"KilledAfterGracePeriod", "trap '' TERM; while :; do sleep 0.1; done", -1},
+	}
+
+	for _, check := range checks {
+		// given:
+		cmd := exec.Command("sh", "-c", check.script)
+		if err := cmd.Start(); err != nil {
+			t.Skip("requires a POSIX shell")
+		}
+		signals := make(chan os.Signal, 1)
+
+		// when:
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			signals <- syscall.SIGTERM
+		}()
+		_, err := superviseCommand(context.Background(), cmd, signals, 500*time.Millisecond, nil, 0)
+
+		// then:
+		actual := 0
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			actual = exitErr.ExitCode()
+		}
+		if actual != check.expected {
+			t.Errorf("%s: got %d, want %d", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestSuperviseCommandTimeout(t *testing.T) {
+	// given:
+	cmd := exec.Command("sh", "-c", "sleep 5 & wait")
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Skip("requires a POSIX shell")
+	}
+	start := time.Now()
+
+	// when:
+	_, err := superviseCommand(context.Background(), cmd, make(chan os.Signal), time.Second, time.After(200*time.Millisecond), 200*time.Millisecond)
+
+	// then:
+	if ExitCode(err) != timeoutExitCode {
+		t.Errorf("got %v, want a timeout", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Errorf("Expected the process group to be killed, took %s", time.Since(start))
+	}
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// The child process shares the console of Gum, thus it receives console ctrl events
+// (Ctrl-C, Ctrl-Break) by itself. Gum only waits for it to shut down
+func forwardSignal(cmd *exec.Cmd, sig os.Signal) {
+}
+
+// A new process group would stop console ctrl events from reaching the child,
+// thus the process tree is killed with taskkill instead
+func setProcessGroup(cmd *exec.Cmd) {
+}
+
+// Kills the child and its descendants
+func killProcessGroup(cmd *exec.Cmd) {
+	pid := strconv.Itoa(cmd.Process.Pid)
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", pid).Run(); err != nil {
+		cmd.Process.Kill()
+	}
+}
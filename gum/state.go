@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Resolves the directory where Gum keeps its config (OS dependent).
+// Follows the XDG Base Directory specification unless $GUM_HOME is set
+func resolveConfigDir(context Context) string {
+	if home := resolveGumHome(context); len(home) > 0 {
+		return home
+	}
+	if context.IsWindows() {
+		return filepath.Join(context.GetHomeDir(), "Gum")
+	}
+	return filepath.Join(resolveXdgDir(context, "XDG_CONFIG_HOME", ".config"), "gum")
+}
+
+// Resolves the directory where Gum keeps caches that may be safely deleted (OS dependent).
+// Follows the XDG Base Directory specification unless $GUM_HOME is set
+func resolveCacheDir(context Context) string {
+	if home := resolveGumHome(context); len(home) > 0 {
+		return filepath.Join(home, "cache")
+	}
+	if context.IsWindows() {
+		dir := context.Getenv("LOCALAPPDATA")
+		if len(dir) == 0 {
+			dir = context.GetHomeDir()
+		}
+		return filepath.Join(dir, "Gum", "cache")
+	}
+	return filepath.Join(resolveXdgDir(context, "XDG_CACHE_HOME", ".cache"), "gum")
+}
+
+// Resolves the directory where Gum keeps its state (OS dependent), such as history
+// and trust decisions. Follows the XDG Base Directory specification unless $GUM_HOME is set
+func resolveStateDir(context Context) string {
+	if home := resolveGumHome(context); len(home) > 0 {
+		return home
+	}
+	if context.IsWindows() {
+		return filepath.Join(context.GetHomeDir(), "Gum")
+	}
+
+	dir := filepath.Join(resolveXdgDir(context, "XDG_STATE_HOME", filepath.Join(".local", "state")), "gum")
+	migrateLegacyStateDir(context, dir)
+	return dir
+}
+
+// Resolves $GUM_HOME, the single directory holding Gum's config and state when set
+func resolveGumHome(context Context) string {
+	home := context.Getenv("GUM_HOME")
+	if len(home) == 0 {
+		return ""
+	}
+
+	abs, err := filepath.Abs(home)
+	if err != nil {
+		return home
+	}
+	return abs
+}
+
+// Resolves an XDG base directory, falling back to its default relative to $HOME
+func resolveXdgDir(context Context, key string, defaultDir string) string {
+	dir := context.Getenv(key)
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(context.GetHomeDir(), defaultDir)
+}
+
+// Moves $HOME/.gum to the XDG state directory. Caches are moved to the XDG cache directory
+func migrateLegacyStateDir(context Context, stateDir string) {
+	legacy := filepath.Join(context.GetHomeDir(), ".gum")
+	if len(context.GetHomeDir()) == 0 || !context.FileExists(legacy) || context.FileExists(stateDir) {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(stateDir), 0755); err != nil {
+		return
+	}
+	if err := os.Rename(legacy, stateDir); err != nil {
+		return
+	}
+
+	cacheDir := resolveCacheDir(context)
+	for _, name := range []string{"tasks", "versions"} {
+		path := filepath.Join(stateDir, name)
+		if context.FileExists(path) && os.MkdirAll(cacheDir, 0755) == nil {
+			os.Rename(path, filepath.Join(cacheDir, name))
+		}
+	}
+}
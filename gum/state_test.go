@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGumHome(t *testing.T) {
+	// given:
+	home, _ := filepath.Abs(filepath.Join("..", "tests"))
+	relocated := testContext{
+		homeDir: home,
+		env:     map[string]string{"GUM_HOME": filepath.Join(home, "gum")}}
+	regular := testContext{homeDir: home}
+	xdg := testContext{
+		homeDir: home,
+		env:     map[string]string{"XDG_CONFIG_HOME": "/xdg"}}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"State", resolveStateDir(relocated), filepath.Join(home, "gum")},
+		{"UserConfig", resolveUserConfigFile(relocated), filepath.Join(home, "gum", "gm.toml")},
+		{"Cache", resolveCacheDir(relocated), filepath.Join(home, "gum", "cache")},
+		{"DefaultState", resolveStateDir(regular), filepath.Join(home, ".local", "state", "gum")},
+		{"DefaultCache", resolveCacheDir(regular), filepath.Join(home, ".cache", "gum")},
+		{"DefaultUserConfig", resolveUserConfigFile(regular), filepath.Join(home, ".config", "gum", "gm.toml")},
+		{"XdgConfig", resolveUserConfigFile(xdg), filepath.Join("/xdg", "gum", "gm.toml")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestMigrateLegacyStateDir(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+	os.MkdirAll(filepath.Join(home, ".gum", "tasks"), 0755)
+	ioutil.WriteFile(filepath.Join(home, ".gum", "trust"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(home, ".gm.toml"), []byte(""), 0644)
+
+	context := testContext{homeDir: home}
+
+	// when:
+	stateDir := resolveStateDir(context)
+
+	// then:
+	if !context.FileExists(filepath.Join(stateDir, "trust")) {
+		t.Error("Expected state to be migrated")
+	}
+	if !context.FileExists(filepath.Join(home, ".cache", "gum", "tasks")) {
+		t.Error("Expected caches to be migrated")
+	}
+	if context.FileExists(filepath.Join(home, ".gum")) {
+		t.Error("Expected legacy state dir to be removed")
+	}
+	if resolveUserConfigFile(context) != filepath.Join(home, ".gm.toml") {
+		t.Error("Expected legacy user config file to be used")
+	}
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Matches the failures reported by Gradle and Maven when a task or phase is unknown
+var unknownTaskPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`[Tt]ask '([^']+)' not found in`),
+	regexp.MustCompile(`Unknown lifecycle phase "([^"]+)"`)}
+
+// taskWatcher captures the tasks/phases reported as unknown in the output of a build
+type taskWatcher struct {
+	mutex sync.Mutex
+	line  string
+	tasks []string
+}
+
+func (w *taskWatcher) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	lines := strings.Split(w.line+string(p), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		for _, pattern := range unknownTaskPatterns {
+			if m := pattern.FindStringSubmatch(line); m != nil {
+				w.tasks = append(w.tasks, m[1])
+			}
+		}
+	}
+	w.line = lines[len(lines)-1]
+	return len(p), nil
+}
+
+// Prints suggestions for tasks/goals the tool reported as unknown to the project.
+// Invoked after the tool failed
+func suggestTasks(context Context, inv invocation, watcher *taskWatcher) {
+	if watcher == nil {
+		return
+	}
+
+	requested := uniqueStrings(watcher.tasks)
+	if len(requested) == 0 {
+		return
+	}
+
+	var known []string
+	for _, refresh := range []bool{false, true} {
+		known = resolveTaskNames(context, inv, refresh)
+		if len(findUnknownTasks(requested, known)) == 0 {
+			return
+		}
+	}
+
+	for _, task := range findUnknownTasks(requested, known) {
+		candidates := findSimilar(taskName(task), known)
+		if len(candidates) > 0 {
+			fmt.Fprintln(gumOutput, "Unknown task '"+task+"', did you mean: "+strings.Join(candidates, ", ")+"?")
+		}
+	}
+}
+
+func findUnknownTasks(requested []string, known []string) []string {
+	set := make(map[string]struct{})
+	for _, k := range known {
+		set[k] = struct{}{}
+	}
+
+	unknown := make([]string, 0)
+	for _, r := range requested {
+		if _, ok := set[taskName(r)]; !ok {
+			unknown = append(unknown, r)
+		}
+	}
+	return unknown
+}
+
+// Strips the project path from a qualified Gradle task name such as :core:build
+func taskName(task string) string {
+	if i := strings.LastIndex(task, ":"); i > -1 {
+		return task[i+1:]
+	}
+	return task
+}
+
+// Finds candidates whose edit distance is small relative to the given name
+func findSimilar(name string, candidates []string) []string {
+	threshold := 1 + len(name)/4
+	if threshold > 3 {
+		threshold = 3
+	}
+
+	similar := make([]string, 0)
+	for _, c := range candidates {
+		if levenshtein(strings.ToLower(name), strings.ToLower(c)) <= threshold {
+			similar = append(similar, c)
+		}
+	}
+	return similar
+}
+
+func levenshtein(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a int, b int, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
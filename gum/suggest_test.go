@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindSimilarTasks(t *testing.T) {
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"buidl", strings.Join(findSimilar("buidl", gradleBuiltinTasks), ","), "build"},
+		{"tset", strings.Join(findSimilar("tset", gradleBuiltinTasks), ","), "test"},
+		{"verfy", strings.Join(findSimilar("verfy", mavenLifecyclePhases), ","), "verify"},
+		{"xyzzy", strings.Join(findSimilar("xyzzy", mavenLifecyclePhases), ","), ""},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestTaskWatcher(t *testing.T) {
+	var checks = []struct {
+		title, output, expected string
+	}{
+		{"Gradle", "* What went wrong:\nTask 'buidl' not found in root project 'app'.\n", "buidl"},
+		{"Gradle subproject", "Cannot locate tasks that match ':core:tset' as task 'tset' not found in project ':core'.\n", "tset"},
+		{"Maven", "[ERROR] Unknown lifecycle phase \"verfy\". You must specify a valid lifecycle phase\n", "verfy"},
+		{"Compilation failure", "* What went wrong:\nExecution failed for task ':compileJava'.\n", ""},
+	}
+
+	for _, check := range checks {
+		// given:
+		watcher := &taskWatcher{}
+
+		// when:
+		for _, chunk := range strings.SplitAfter(check.output, "'") {
+			watcher.Write([]byte(chunk))
+		}
+
+		// then:
+		if actual := strings.Join(watcher.tasks, ","); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
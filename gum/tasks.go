@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var gradleBuiltinTasks = []string{
+	"assemble", "build", "buildDependents", "buildEnvironment", "buildNeeded", "check", "classes", "clean",
+	"compileJava", "compileTestJava", "components", "dependencies", "dependencyInsight", "dependentComponents",
+	"help", "init", "jar", "javadoc", "javaToolchains", "model", "outgoingVariants", "processResources",
+	"processTestResources", "projects", "properties", "publish", "publishToMavenLocal", "run", "tasks",
+	"test", "testClasses", "wrapper"}
+
+var mavenLifecyclePhases = []string{
+	"validate", "initialize", "generate-sources", "process-sources", "generate-resources", "process-resources",
+	"compile", "process-classes", "generate-test-sources", "process-test-sources", "generate-test-resources",
+	"process-test-resources", "test-compile", "process-test-classes", "test", "prepare-package", "package",
+	"pre-integration-test", "integration-test", "post-integration-test", "verify", "install", "deploy",
+	"pre-clean", "clean", "post-clean", "pre-site", "site", "post-site", "site-deploy"}
+
+// Gradle and Maven flags whose value is given as the next argument
+var flagsWithValues = map[string]struct{}{
+	"-b": {}, "--build-file": {}, "-c": {}, "--settings-file": {}, "-p": {}, "--project-dir": {},
+	"-x": {}, "--exclude-task": {}, "-I": {}, "--init-script": {}, "-g": {}, "--gradle-user-home": {},
+	"-f": {}, "--file": {}, "-s": {}, "--settings": {}, "-pl": {}, "--projects": {}, "-P": {},
+	"--activate-profiles": {}, "-rf": {}, "--resume-from": {}, "-T": {}, "--threads": {},
+	"--tests": {}, "--include-build": {}, "--project-cache-dir": {}, "--console": {}, "--warning-mode": {},
+	"--priority": {}, "--max-workers": {}, "--update-locks": {}, "-D": {}, "-l": {}, "--log-file": {},
+	"-gs": {}, "--global-settings": {}, "--toolchains": {}}
+
+var gradleTaskLinePattern = regexp.MustCompile(`^([A-Za-z0-9_][A-Za-z0-9_:\-]*)( - .*)?$`)
+
+// Resolves the names of tasks (Gradle) or phases (Maven) known for the given invocation.
+// Gradle tasks are read from a per project cache when refresh is false,
+// otherwise the cache is refreshed by running the tasks task
+func resolveTaskNames(context Context, inv invocation, refresh bool) []string {
+	names := make([]string, 0)
+
+	switch inv.tool {
+	case "gradle":
+		names = append(names, gradleBuiltinTasks...)
+		names = append(names, readTaskCache(context, inv, refresh)...)
+	case "maven":
+		names = append(names, mavenLifecyclePhases...)
+	}
+
+	return uniqueStrings(names)
+}
+
+// Reads cached task names, refreshing the cache if it's stale and refresh is true
+func readTaskCache(context Context, inv invocation, refresh bool) []string {
+	if len(inv.rootDir) == 0 {
+		return make([]string, 0)
+	}
+
+	cache := resolveTaskCacheFile(context, inv.rootDir)
+	if !isTaskCacheStale(cache, inv) {
+		data, err := ioutil.ReadFile(cache)
+		if err == nil {
+			return strings.Fields(string(data))
+		}
+	}
+
+	if !refresh {
+		return make([]string, 0)
+	}
+
+	cmd := exec.Command(inv.executable, "tasks", "--all", "-q")
+	cmd.Dir = inv.rootDir
+	out, err := cmd.Output()
+	if err != nil {
+		return make([]string, 0)
+	}
+
+	names := parseGradleTasks(string(out))
+	if err := os.MkdirAll(filepath.Dir(cache), 0755); err == nil {
+		ioutil.WriteFile(cache, []byte(strings.Join(names, "\n")+"\n"), 0644)
+	}
+
+	return names
+}
+
+// A cache is stale when missing or older than the build files in the root dir
+func isTaskCacheStale(cache string, inv invocation) bool {
+	info, err := os.Stat(cache)
+	if err != nil {
+		return true
+	}
+
+	files, _ := filepath.Glob(filepath.Join(inv.rootDir, "*.gradle*"))
+	if len(inv.buildFile) > 0 {
+		files = append(files, inv.buildFile)
+	}
+
+	for _, file := range files {
+		fi, err := os.Stat(file)
+		if err == nil && fi.ModTime().After(info.ModTime()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func resolveTaskCacheFile(context Context, rootDir string) string {
+	hash := sha256.Sum256([]byte(rootDir))
+	return filepath.Join(resolveCacheDir(context), "tasks", hex.EncodeToString(hash[:8])+".txt")
+}
+
+// Parses the output of "gradle tasks --all"
+func parseGradleTasks(output string) []string {
+	names := make([]string, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		m := gradleTaskLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		name := m[1]
+		if i := strings.LastIndex(name, ":"); i > -1 {
+			name = name[i+1:]
+		}
+		if len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+
+	return uniqueStrings(names)
+}
+
+// Finds the indexes of args that name tasks (Gradle) or phases (Maven).
+// Skips flags, their values, and Maven plugin goals
+func findTaskArgs(tool string, args []string) []int {
+	indexes := make([]int, 0)
+	skip := false
+
+	for i, arg := range args {
+		if skip {
+			skip = false
+			continue
+		}
+		if strings.HasPrefix(arg, "-") {
+			_, skip = flagsWithValues[arg]
+			continue
+		}
+		if tool == "maven" && strings.Contains(arg, ":") {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+
+	return indexes
+}
+
+// Expands camel case abbreviations such as cTJ into compileTestJava when they match
+// a single known task or phase. Gradle tasks are expanded only if the project's task
+// cache is available, as Gradle resolves abbreviations on its own
+func expandTaskAbbreviations(context Context, inv invocation, args []string) []string {
+	var known []string
+	switch inv.tool {
+	case "gradle":
+		cached := readTaskCache(context, inv, false)
+		if len(cached) == 0 {
+			return args
+		}
+		known = uniqueStrings(append(cached, gradleBuiltinTasks...))
+	case "maven":
+		known = mavenLifecyclePhases
+	default:
+		return args
+	}
+
+	expanded := make([]string, len(args))
+	copy(expanded, args)
+
+	for _, i := range findTaskArgs(inv.tool, args) {
+		prefix := ""
+		name := args[i]
+		if j := strings.LastIndex(name, ":"); j > -1 {
+			prefix = name[:j+1]
+			name = name[j+1:]
+		}
+
+		match := matchAbbreviation(name, known)
+		if len(match) > 0 {
+			expanded[i] = prefix + match
+		}
+	}
+
+	return expanded
+}
+
+// Matches an abbreviation against candidates. Exact name matches win, followed by
+// abbreviations that cover all words of a single candidate, followed by abbreviations
+// that cover the leading words of a single candidate
+func matchAbbreviation(abbreviation string, candidates []string) string {
+	for _, c := range candidates {
+		if c == abbreviation {
+			return ""
+		}
+	}
+
+	parts := splitWords(abbreviation, true)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	for _, exact := range []bool{true, false} {
+		matches := make([]string, 0)
+		for _, c := range candidates {
+			words := splitWords(c, false)
+			if (exact && len(words) != len(parts)) || len(words) < len(parts) {
+				continue
+			}
+
+			matched := true
+			for k := range parts {
+				if !strings.HasPrefix(strings.ToLower(words[k]), strings.ToLower(parts[k])) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				matches = append(matches, c)
+			}
+		}
+
+		if len(matches) == 1 {
+			return matches[0]
+		} else if len(matches) > 1 {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// Splits camelCase and kebab-case names into words
+func splitWords(name string, abbreviation bool) []string {
+	words := make([]string, 0)
+	var word strings.Builder
+
+	for _, r := range name {
+		if r == '-' {
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			continue
+		}
+		if r >= 'A' && r <= 'Z' && word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+		word.WriteRune(r)
+	}
+
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+
+	if abbreviation && len(words) < 2 && !strings.Contains(name, "-") {
+		// a single lower case word is not an abbreviation
+		return make([]string, 0)
+	}
+
+	return words
+}
+
+func uniqueStrings(s []string) []string {
+	set := make(map[string]struct{})
+	unique := make([]string, 0)
+	for _, e := range s {
+		if _, ok := set[e]; !ok {
+			set[e] = struct{}{}
+			unique = append(unique, e)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
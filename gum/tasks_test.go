@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGradleTasks(t *testing.T) {
+	// given:
+	output := `
+Build tasks
+-----------
+assemble - Assembles the outputs of this project.
+core:integrationTest - Runs the integration tests.
+
+Other tasks
+-----------
+prepareKotlinBuildScriptModel
+`
+
+	// when:
+	tasks := parseGradleTasks(output)
+
+	// then:
+	expected := "assemble,integrationTest,prepareKotlinBuildScriptModel"
+	if strings.Join(tasks, ",") != expected {
+		t.Errorf("tasks: got %s, want %s", tasks, expected)
+	}
+}
+
+func TestMatchAbbreviation(t *testing.T) {
+	tasks := []string{"compileJava", "compileTestJava", "compileBuildTest", "build", "integrationTest"}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"cBT", matchAbbreviation("cBT", tasks), "compileBuildTest"},
+		{"cTJ", matchAbbreviation("cTJ", tasks), "compileTestJava"},
+		{"cJ", matchAbbreviation("cJ", tasks), "compileJava"},
+		{"cT", matchAbbreviation("cT", tasks), "compileTestJava"},
+		{"iT", matchAbbreviation("iT", tasks), "integrationTest"},
+		{"build", matchAbbreviation("build", tasks), ""},
+		{"bu", matchAbbreviation("bu", tasks), ""},
+		{"tC", matchAbbreviation("tC", mavenLifecyclePhases), "test-compile"},
+		{"pIT", matchAbbreviation("pIT", mavenLifecyclePhases), ""},
+		{"prIT", matchAbbreviation("prIT", mavenLifecyclePhases), "pre-integration-test"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestFindTaskArgs(t *testing.T) {
+	var checks = []struct {
+		tool     string
+		args     []string
+		expected string
+	}{
+		{"gradle", []string{"test", "--tests", "com.acme.FooTest", "check"}, "test,check"},
+		{"gradle", []string{"--console", "plain", "-x", "javadoc", "build"}, "build"},
+		{"maven", []string{"-pl", "core", "-gs", "settings.xml", "verify", "versions:set"}, "verify"},
+	}
+
+	for _, check := range checks {
+		// given:
+		tasks := make([]string, 0)
+
+		// when:
+		for _, i := range findTaskArgs(check.tool, check.args) {
+			tasks = append(tasks, check.args[i])
+		}
+
+		// then:
+		if actual := strings.Join(tasks, ","); actual != check.expected {
+			t.Errorf("%s: got %s, want %s", strings.Join(check.args, " "), actual, check.expected)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Checks if running under TeamCity, which sets $TEAMCITY_VERSION for build steps
+func isTeamCity(context Context) bool {
+	return len(context.Getenv("TEAMCITY_VERSION")) > 0
+}
+
+// Formats a TeamCity service message such as ##teamcity[blockOpened name='build'].
+// Attributes are given as name/value pairs
+func formatTeamCityMessage(name string, attributes ...string) string {
+	message := "##teamcity[" + name
+	for i := 0; i+1 < len(attributes); i += 2 {
+		message += " " + attributes[i] + "='" + escapeTeamCity(attributes[i+1]) + "'"
+	}
+	return message + "]"
+}
+
+func escapeTeamCity(s string) string {
+	return strings.NewReplacer(
+		"|", "||",
+		"'", "|'",
+		"\n", "|n",
+		"\r", "|r",
+		"[", "|[",
+		"]", "|]").Replace(s)
+}
+
+// Names the block wrapping a single run of the build, such as gradle build (attempt 2)
+func teamCityBlockName(inv invocation, attempt int) string {
+	name := strings.TrimSpace(inv.tool + " " + strings.Join(inv.args.Args, " "))
+	if attempt > 1 {
+		name += " (attempt " + strconv.Itoa(attempt) + ")"
+	}
+	return name
+}
+
+// Service messages must go to stdout, where TeamCity reads them from
+func printTeamCityMessage(name string, attributes ...string) {
+	fmt.Fprintln(os.Stdout, formatTeamCityMessage(name, attributes...))
+}
+
+// Reports compiler messages and, unless the build succeeded, a build problem with its summary
+func printTeamCityProblems(inv invocation, compiler *compilerWatcher, summary string, err error) {
+	if compiler != nil {
+		for _, m := range compiler.messages {
+			printTeamCityMessage("message",
+				"text", m.file+":"+strconv.Itoa(m.line)+": "+m.message,
+				"status", strings.ToUpper(m.severity))
+		}
+	}
+
+	if err != nil {
+		description := summary
+		if len(description) == 0 {
+			description = "Could not launch " + inv.executable
+		}
+		_, code, _ := buildOutcome(err)
+		printTeamCityMessage("buildProblem",
+			"description", description,
+			"identity", inv.tool+"-exit-"+strconv.Itoa(code))
+	}
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"testing"
+)
+
+func TestFormatTeamCityMessage(t *testing.T) {
+	var checks = []struct {
+		title      string
+		name       string
+		attributes []string
+		expected   string
+	}{
+		{"Block", "blockOpened", []string{"name", "gradle build"}, "##teamcity[blockOpened name='gradle build']"},
+		{"Escaped", "buildProblem", []string{"description", "it's [broken]|\nbadly", "identity", "make-exit-2"},
+			"##teamcity[buildProblem description='it|'s |[broken|]|||nbadly' identity='make-exit-2']"},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := formatTeamCityMessage(check.name, check.attributes...)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, actual, check.expected)
+		}
+	}
+}
+
+func TestTeamCityBlockName(t *testing.T) {
+	// given:
+	args := ParseArgs([]string{"clean", "build"})
+	inv := invocation{tool: "gradle", args: &args}
+
+	// when:
+	first := teamCityBlockName(inv, 1)
+	retry := teamCityBlockName(inv, 2)
+
+	// then:
+	if first != "gradle clean build" || retry != "gradle clean build (attempt 2)" {
+		t.Errorf("got %q and %q", first, retry)
+	}
+}
@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+)
+
+// Maximum number of failed tests displayed by the summary
+const testSummaryLimit = 20
+
+// Gradle tasks that run tests, besides those ending with test/Test such as integrationTest
+var gradleTestTasks = []string{"build", "check"}
+
+// Maven phases (and goals) that run tests
+var mavenTestGoals = []string{"test", "integration-test", "verify", "package", "install", "deploy", "surefire:test", "failsafe:integration-test"}
+
+// Directories holding XML test reports written by Gradle, Surefire, and Failsafe
+var testReportDirs = []string{"test-results", "surefire-reports", "failsafe-reports"}
+
+// Directories that never hold test reports of the project
+var testReportSkipDirs = []string{".git", ".gradle", ".idea", "node_modules", "src"}
+
+type testSuite struct {
+	Name      string     `xml:"name,attr"`
+	TestCases []testCase `xml:"testcase"`
+}
+
+type testCase struct {
+	Name      string    `xml:"name,attr"`
+	ClassName string    `xml:"classname,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+}
+
+// testResults summarizes the test reports written by a build
+type testResults struct {
+	passed  int
+	skipped int
+	failed  []string
+}
+
+// Checks if the given args of a Gradle or Maven build run tests
+func hasTestTasks(inv invocation) bool {
+	for _, arg := range inv.args.Args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		switch inv.tool {
+		case "gradle":
+			task := arg[strings.LastIndex(arg, ":")+1:]
+			if strings.HasSuffix(task, "test") || strings.HasSuffix(task, "Test") || contains(gradleTestTasks, task) {
+				return true
+			}
+		case "maven":
+			if contains(mavenTestGoals, arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Collects the results of XML test reports found under rootDir that were written since the given time
+func collectTestResults(rootDir string, since time.Time) *testResults {
+	// some file systems keep modification times with a precision of one second
+	since = since.Truncate(time.Second)
+	results := &testResults{failed: make([]string, 0)}
+	filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if contains(testReportSkipDirs, info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isTestReport(path) || info.ModTime().Before(since) {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		results.add(content)
+		return nil
+	})
+	return results
+}
+
+// Checks if the given file is a TEST-*.xml report inside a known report directory
+func isTestReport(path string) bool {
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, "TEST-") || !strings.HasSuffix(name, ".xml") {
+		return false
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if contains(testReportDirs, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *testResults) add(report []byte) {
+	suite := testSuite{}
+	if err := xml.Unmarshal(report, &suite); err != nil {
+		return
+	}
+
+	for _, tc := range suite.TestCases {
+		if tc.Failure != nil || tc.Error != nil {
+			className := tc.ClassName
+			if len(className) == 0 {
+				className = suite.Name
+			}
+			r.failed = append(r.failed, className+" > "+tc.Name)
+		} else if tc.Skipped != nil {
+			r.skipped++
+		} else {
+			r.passed++
+		}
+	}
+}
+
+func (r *testResults) total() int {
+	return r.passed + r.skipped + len(r.failed)
+}
+
+// Prints the number of passed, failed, and skipped tests followed by the names of failed tests
+func printTestSummary(config *Config, results *testResults) {
+	if results == nil || results.total() == 0 {
+		return
+	}
+
+	summary := "Tests: " + strconv.Itoa(results.passed) + " passed, " +
+		strconv.Itoa(len(results.failed)) + " failed, " +
+		strconv.Itoa(results.skipped) + " skipped"
+	if isInstanceOf(config.theme.t, (*ColoredTheme)(nil)) {
+		if len(results.failed) > 0 {
+			summary = color.Red.Sprint(summary)
+		} else {
+			summary = color.Green.Sprint(summary)
+		}
+	}
+	fmt.Fprintln(gumOutput, summary)
+
+	for i, name := range results.failed {
+		if i == testSummaryLimit {
+			fmt.Fprintln(gumOutput, "  ... and "+strconv.Itoa(len(results.failed)-i)+" more")
+			break
+		}
+		fmt.Fprintln(gumOutput, "  "+name)
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHasTestTasks(t *testing.T) {
+	var checks = []struct {
+		tool     string
+		input    []string
+		expected bool
+	}{
+		{"gradle", []string{"build"}, true},
+		{"gradle", []string{":core:integrationTest"}, true},
+		{"gradle", []string{"--offline", "test"}, true},
+		{"gradle", []string{"assemble"}, false},
+		{"maven", []string{"clean", "verify"}, true},
+		{"maven", []string{"compile"}, false},
+		{"make", []string{"test"}, false},
+	}
+
+	for _, check := range checks {
+		// given:
+		args := ParsedArgs{Args: check.input}
+
+		// when:
+		actual := hasTestTasks(invocation{tool: check.tool, args: &args})
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s %v: got %t, want %t", check.tool, check.input, actual, check.expected)
+		}
+	}
+}
+
+func TestCollectTestResults(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	gradleReports := filepath.Join(dir, "core", "build", "test-results", "test")
+	mavenReports := filepath.Join(dir, "app", "target", "surefire-reports")
+	os.MkdirAll(gradleReports, 0755)
+	os.MkdirAll(mavenReports, 0755)
+
+	ioutil.WriteFile(filepath.Join(gradleReports, "TEST-com.acme.FooTest.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="com.acme.FooTest" tests="3" failures="1" errors="0" skipped="1">
+  <testcase name="passes" classname="com.acme.FooTest" time="0.01"/>
+  <testcase name="fails" classname="com.acme.FooTest" time="0.01">
+    <failure message="expected 1 but was 2" type="AssertionError">stack trace</failure>
+  </testcase>
+  <testcase name="ignored" classname="com.acme.FooTest" time="0.0">
+    <skipped/>
+  </testcase>
+</testsuite>`), 0644)
+	ioutil.WriteFile(filepath.Join(mavenReports, "TEST-com.acme.BarTest.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="com.acme.BarTest" tests="2" failures="0" errors="1">
+  <testcase name="passes" classname="com.acme.BarTest" time="0.01"/>
+  <testcase name="breaks" classname="com.acme.BarTest" time="0.01">
+    <error message="boom" type="java.lang.IllegalStateException"/>
+  </testcase>
+</testsuite>`), 0644)
+	stale := filepath.Join(mavenReports, "TEST-com.acme.OldTest.xml")
+	ioutil.WriteFile(stale, []byte(`<testsuite name="com.acme.OldTest"><testcase name="old" classname="com.acme.OldTest"/></testsuite>`), 0644)
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(stale, old, old)
+	ioutil.WriteFile(filepath.Join(mavenReports, "com.acme.BarTest.txt"), []byte("Tests run: 2"), 0644)
+
+	// when:
+	results := collectTestResults(dir, time.Now().Add(-time.Minute))
+
+	// then:
+	if results.passed != 2 || results.skipped != 1 || len(results.failed) != 2 {
+		t.Fatalf("got %d passed, %d skipped, %v failed", results.passed, results.skipped, results.failed)
+	}
+	if results.failed[0] != "com.acme.BarTest > breaks" || results.failed[1] != "com.acme.FooTest > fails" {
+		t.Errorf("unexpected failed tests %v", results.failed)
+	}
+}
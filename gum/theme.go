@@ -76,6 +76,13 @@ func (t *ColoredTheme) PrintKeyValueLiteral(key string, value string) {
 	t.literal.Println("\"")
 }
 
+// PrintKeyValueInt prints a key/value pair as key = i
+func (t *ColoredTheme) PrintKeyValueInt(key string, value int) {
+	t.key.Print(key)
+	t.symbol.Print(" = ")
+	t.symbol.Println(value)
+}
+
 // PrintKeyValueArrayS prints a key/value pair as key = ["v1", "v2"]
 func (t *ColoredTheme) PrintKeyValueArrayS(key string, value []string) {
 	t.key.Print(key)
@@ -139,6 +146,11 @@ func (t *noneTheme) PrintKeyValueLiteral(key string, value string) {
 	fmt.Println("\"")
 }
 
+// PrintKeyValueInt prints a key/value pair as key = i
+func (t *noneTheme) PrintKeyValueInt(key string, value int) {
+	fmt.Println(key+" =", value)
+}
+
 // PrintKeyValueArrayS prints a key/value pair as key = ["v1", "v2"]
 func (t *noneTheme) PrintKeyValueArrayS(key string, value []string) {
 	fmt.Print(key)
@@ -18,98 +18,103 @@ package gum
 
 import (
 	"fmt"
-	"os"
 	"strings"
 )
 
-// FindTool Executes gradle/maven/ant/bach/jbang based on config discovery
-func FindTool(args *ParsedArgs) {
-	context := NewDefaultContext(false)
-	config := ReadUserConfig(context)
-	config.merge(nil)
+// defaultDiscovery defines the order in which tools are discovered
+var defaultDiscovery = []string{"node", "grails", "gradle", "maven", "sbt", "bazel", "buck2", "lein", "clojure", "boot", "bld", "ant", "bach", "cmake", "make", "jbang"}
 
-	if len(config.general.discovery) == 5 {
-		discoverTool(config, context, args)
-	}
-
-	doFindGradle(context, args)
-	doFindMaven(context, args)
-	doFindAnt(context, args)
-	doFindBach(context, args)
-	doFindJbang(context, args)
+// explicitContext marks the tool as explicitly requested, thus a missing project is reported
+type explicitContext struct {
+	Context
+}
 
-	if args.HasGumFlag("gc") {
-		config.print()
-		os.Exit(0)
-	} else {
-		fmt.Println("Did not find a Gradle, Maven, Bach, JBang or Ant project")
-		os.Exit(-1)
-	}
+func (c explicitContext) IsExplicit() bool {
+	return true
 }
 
-func discoverTool(config *Config, context Context, args *ParsedArgs) {
-	for i := range config.general.discovery {
-		tool := strings.TrimSpace(strings.ToLower(config.general.discovery[i]))
+// FindTool finds the tool of the current project: the one forced with --gum-tool or a flag such as -gg,
+// otherwise the one with the highest detection score. Configured discovery order breaks ties between
+// equally scored tools. Returns nil if no tool was found
+func FindTool(context Context, args *ParsedArgs) Command {
+	memo := newMemoContext(context)
+	defer debugMemoContext(memo, args)
+	context = newRealDirContext(memo)
+
+	// Gum's output is selected once, outside of detectors as these run concurrently
+	configs := newConfigCache(context)
+	config := configs.userConfig()
+	setGumOutput(config)
 
-		switch tool {
-		case "gradle":
-			doFindGradle(context, args)
-			break
-		case "maven":
-			doFindMaven(context, args)
-			break
-		case "jbang":
-			doFindJbang(context, args)
-			break
-		case "bach":
-			doFindBach(context, args)
-			break
-		case "ant":
-			doFindAnt(context, args)
-			break
-		default:
-			fmt.Println("Unsupported tool: " + tool)
-			os.Exit(-1)
+	if tool := resolveForcedTool(args); len(tool) > 0 {
+		detect := resolveDetector(config, tool)
+		if detect == nil {
+			fmt.Fprintln(gumOutput, "Unsupported tool: "+tool)
+			context.Exit(-1)
+			return nil
 		}
+		if d := detect(configContext{explicitContext{context}, configs}, args); d != nil {
+			setGumOutput(d.command.configuration())
+			return d.command
+		}
+		return nil
 	}
-}
 
-func doFindGradle(context Context, args *ParsedArgs) {
-	gradle := FindGradle(context, args)
-	if gradle != nil {
-		gradle.Execute()
-		os.Exit(0)
+	for _, tool := range config.general.discovery {
+		tool = strings.TrimSpace(strings.ToLower(tool))
+		if resolveDetector(config, tool) == nil {
+			fmt.Fprintln(gumOutput, "Unsupported tool: "+tool)
+			context.Exit(-1)
+			return nil
+		}
 	}
-}
 
-func doFindMaven(context Context, args *ParsedArgs) {
-	maven := FindMaven(context, args)
-	if maven != nil {
-		maven.Execute()
-		os.Exit(0)
+	if d := detectTool(configContext{context, configs}, config, args); d != nil {
+		setGumOutput(d.command.configuration())
+		return d.command
 	}
+	return nil
 }
 
-func doFindJbang(context Context, args *ParsedArgs) {
-	jbang := FindJbang(context, args)
-	if jbang != nil {
-		jbang.Execute()
-		os.Exit(0)
+// Releases the memoized file checks of a detection, displaying how many were saved with -gd
+func debugMemoContext(memo *memoContext, args *ParsedArgs) {
+	memo.release()
+	if args.HasGumFlag("gd") {
+		fmt.Fprintln(gumOutput, "file checks          = ", memo.calls)
+		fmt.Fprintln(gumOutput, "file checks saved    = ", memo.saved)
+		fmt.Fprintln(gumOutput, "")
 	}
 }
 
-func doFindBach(context Context, args *ParsedArgs) {
-	bach := FindBach(context, args)
-	if bach != nil {
-		bach.Execute()
-		os.Exit(0)
+// ReportNoTool handles a dir where FindTool found no tool, printing the configuration if -gc
+// is given. Returns the exit code
+func ReportNoTool(context Context, args *ParsedArgs) int {
+	if args.HasGumFlag("gc") {
+		config := ReadUserConfig(context)
+		config.merge(nil)
+		config.policy = ReadPolicy(context)
+		config.print()
+		return 0
 	}
+
+	fmt.Fprintln(gumOutput, noProjectMessage())
+	return -1
 }
 
-func doFindAnt(context Context, args *ParsedArgs) {
-	ant := FindAnt(context, args)
-	if ant != nil {
-		ant.Execute()
-		os.Exit(0)
+// Describes a dir where no project was found for any of the supported tools
+func noProjectMessage() string {
+	return "Did not find a project for any of " + strings.Join(defaultDiscovery, ", ")
+}
+
+// Resolves the tool forced with --gum-tool or a flag such as -gg, if any
+func resolveForcedTool(args *ParsedArgs) string {
+	if tool := args.GumFlagValue("-gum-tool"); len(tool) > 0 {
+		return strings.TrimSpace(strings.ToLower(tool))
+	}
+	for flag, tool := range forcedToolFlags {
+		if args.HasGumFlag(flag) {
+			return tool
+		}
 	}
+	return ""
 }
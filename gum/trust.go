@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type trustDecision struct {
+	trusted  bool
+	checksum string
+	path     string
+}
+
+// Verifies that a project local executable (such as gradlew or mvnw) has been
+// trusted before. Decisions are remembered per wrapper path and checksum
+func verifyTrust(context Context, config *Config, executable string, args *ParsedArgs) bool {
+	if !config.policy.tofu || !isProjectLocal(context, executable) {
+		return true
+	}
+
+	checksum, err := computeWrapperChecksum(context, executable)
+	if err != nil {
+		fmt.Fprintln(gumOutput, "Could not compute checksum of "+executable)
+		fmt.Fprintln(gumOutput, err)
+		return false
+	}
+
+	store := filepath.Join(resolveStateDir(context), "trust")
+	decisions := readTrustDecisions(store)
+
+	// the latest decision wins
+	var previous *trustDecision
+	changed := false
+	for i := range decisions {
+		if decisions[i].path != executable {
+			continue
+		}
+		if decisions[i].checksum == checksum {
+			previous = &decisions[i]
+		} else {
+			changed = true
+		}
+	}
+
+	if previous != nil && previous.trusted {
+		return true
+	} else if previous != nil && !args.HasGumFlag("gy") {
+		fmt.Fprintln(gumOutput, "Refusing to run "+executable+" as it was previously rejected")
+		fmt.Fprintln(gumOutput, "Run again with -gy to trust it")
+		return false
+	}
+
+	var trusted bool
+	if args.HasGumFlag("gy") {
+		trusted = true
+	} else if isInteractive() {
+		if changed {
+			fmt.Fprintln(gumOutput, executable+" has changed since it was last seen")
+		}
+		trusted = confirm("Do you trust " + executable + "?")
+	} else {
+		fmt.Fprintln(gumOutput, "Refusing to run "+executable+" as it has not been trusted yet")
+		fmt.Fprintln(gumOutput, "Run again with -gy to trust it")
+		return false
+	}
+
+	err = writeTrustDecision(store, trustDecision{trusted: trusted, checksum: checksum, path: executable})
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
+	}
+
+	return trusted
+}
+
+// Checks if the executable lives outside of $PATH, such as a wrapper script
+func isProjectLocal(context Context, executable string) bool {
+	dir := filepath.Dir(executable)
+	for _, p := range context.GetPaths() {
+		path, _ := filepath.Abs(p)
+		if path == dir {
+			return false
+		}
+	}
+	return true
+}
+
+// Computes the checksum of a wrapper script plus its jar (if any)
+func computeWrapperChecksum(context Context, executable string) (string, error) {
+	checksum, err := computeChecksum(executable)
+	if err != nil {
+		return "", err
+	}
+
+	jar := resolveWrapperJar(context, executable)
+	if len(jar) > 0 {
+		s, err := computeChecksum(jar)
+		if err != nil {
+			return "", err
+		}
+		checksum = checksum + ":" + s
+	}
+
+	return checksum, nil
+}
+
+// Reads trust decisions. Each line has the format "yes|no checksum path"
+func readTrustDecisions(path string) []trustDecision {
+	decisions := make([]trustDecision, 0)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return decisions
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), " ", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		decisions = append(decisions, trustDecision{
+			trusted:  parts[0] == "yes",
+			checksum: parts[1],
+			path:     parts[2]})
+	}
+
+	return decisions
+}
+
+func writeTrustDecision(path string, decision trustDecision) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	answer := "no"
+	if decision.trusted {
+		answer = "yes"
+	}
+
+	_, err = fmt.Fprintln(file, answer+" "+decision.checksum+" "+decision.path)
+	return err
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrustOnFirstUse(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "single-with-wrapper"))
+	gradlew := filepath.Join(pwd, "gradlew")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		homeDir:    home,
+		paths:      []string{bin}}
+
+	config := newConfig()
+	config.policy.tofu = true
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	untrusted := verifyTrust(context, config, gradlew, &args)
+	args = ParseArgs([]string{"-gq", "-gy"})
+	trusted := verifyTrust(context, config, gradlew, &args)
+	args = ParseArgs([]string{"-gq"})
+	remembered := verifyTrust(context, config, gradlew, &args)
+
+	// then:
+	if untrusted {
+		t.Error("Expected wrapper to be rejected")
+	}
+	if !trusted {
+		t.Error("Expected wrapper to be trusted")
+	}
+	if !remembered {
+		t.Error("Expected trust decision to be remembered")
+	}
+	if !verifyTrust(context, config, filepath.Join(bin, "gradle"), &args) {
+		t.Error("Expected executables in path to be trusted")
+	}
+}
@@ -18,8 +18,8 @@ package gum
 
 // Command defines an executable command (gradle/maven)
 type Command interface {
-	// Execute executes the given command
-	Execute()
+	// Execute executes the given command, returning the exit code of the tool
+	Execute() int
 }
 
 // Context provides an abstraction over the OS and Environment as required by Gum
@@ -39,6 +39,9 @@ type Context interface {
 	// GetPaths gets the paths in $PATH
 	GetPaths() []string
 
+	// Getenv retrieves the value of the environment variable named by the key
+	Getenv(key string) string
+
 	// FileExists checks if a file exists
 	FileExists(name string) bool
 
@@ -57,6 +60,9 @@ type Theme interface {
 	// PrintKeyValueLiteral prints a key/value pair as key = "value"
 	PrintKeyValueLiteral(key string, value string)
 
+	// PrintKeyValueInt prints a key/value pair as key = i
+	PrintKeyValueInt(key string, value int)
+
 	// PrintKeyValueArrayS prints a key/value pair as key = ["v1", "v2"]
 	PrintKeyValueArrayS(key string, value []string)
 
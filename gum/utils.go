@@ -16,7 +16,11 @@
 
 package gum
 
-import "reflect"
+import (
+	"os"
+	"reflect"
+	"sort"
+)
 
 func appendSafe(dst []string, src []string) []string {
 	for _, e := range src {
@@ -30,3 +34,19 @@ func appendSafe(dst []string, src []string) []string {
 func isInstanceOf(objectPtr, typePtr interface{}) bool {
 	return reflect.TypeOf(objectPtr) == reflect.TypeOf(typePtr)
 }
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0)
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Checks if the given path exists and is not a directory, as wrapper scripts
+// may share the name of a directory
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,339 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// configSchema lists the known keys of each config section along with the kind of their values.
+// Sections with user defined keys, such as aliases, branch, tools, and profiles are validated apart
+var configSchema = map[string]map[string]string{
+	"theme": {
+		"name":    "theme",
+		"symbol":  "color",
+		"section": "color",
+		"key":     "color",
+		"boolean": "color",
+		"literal": "color"},
+	"general": {
+		"quiet":           "bool",
+		"debug":           "bool",
+		"discovery":       "strings",
+		"confirm":         "strings",
+		"interactive":     "bool",
+		"summarizeErrors": "bool",
+		"annotations":     "bool",
+		"timeout":         "duration",
+		"retries":         "int",
+		"retryDelay":      "duration",
+		"notify":          "notify",
+		"output":          "output",
+		"console":         "console",
+		"remoteIncludes":  "bool",
+		"telemetry":       "bool"},
+	"gradle": {
+		"replace":           "bool",
+		"defaults":          "bool",
+		"minVersion":        "string",
+		"projectPaths":      "bool",
+		"wrapperValidation": "wrapperValidation",
+		"initScripts":       "strings",
+		"buildScan":         "buildScan",
+		"mappings":          "mappings"},
+	"maven": {
+		"replace":     "bool",
+		"defaults":    "bool",
+		"minVersion":  "string",
+		"daemon":      "bool",
+		"projectList": "bool",
+		"alsoMake":    "bool",
+		"mappings":    "mappings"},
+	"jbang": {
+		"discovery": "strings"},
+	"bach": {
+		"version": "string"},
+	"notify": {
+		"url":       "string",
+		"threshold": "duration"},
+	"hooks": {
+		"before": "strings",
+		"after":  "strings"},
+	"discovery": {
+		"vcsBoundary":     "bool",
+		"boundaryMarkers": "strings",
+		"maxDepth":        "int",
+		"priority":        "strings",
+		"resolveSymlinks": "bool"},
+}
+
+// Allowed values of enumerated kinds
+var configEnums = map[string][]string{
+	"theme":             {"none", "dark", "light", "custom"},
+	"notify":            {"desktop", "webhook"},
+	"output":            {"stdout", "stderr"},
+	"console":           {"off", "auto", "plain", "rich"},
+	"buildScan":         {"always", "on-failure", "never"},
+	"wrapperValidation": {"off", "warn", "block"},
+}
+
+var customToolSchema = map[string]string{
+	"markers":    "strings",
+	"executable": "string",
+	"wrapper":    "string",
+	"args":       "strings"}
+
+var toolArgsSchema = map[string]string{
+	"args": "strings"}
+
+var branchSchema = map[string]string{
+	"defaults": "strings"}
+
+// configProblem is an issue found in a config file. Unknown keys are reported as warnings,
+// anything else as an error as the offending setting cannot be applied
+type configProblem struct {
+	location string
+	position toml.Position
+	key      string
+	message  string
+	warning  bool
+}
+
+func (p configProblem) String() string {
+	severity := "ERROR"
+	if p.warning {
+		severity = "WARNING"
+	}
+
+	location := p.location
+	if !p.position.Invalid() {
+		location += ":" + strconv.Itoa(p.position.Line) + ":" + strconv.Itoa(p.position.Col)
+	}
+	return severity + ": " + location + ": " + p.key + " " + p.message
+}
+
+// configValidator collects the problems found in a single config file
+type configValidator struct {
+	location string
+	root     *toml.Tree
+	problems []configProblem
+}
+
+// Validates the given config tree, as read from location
+func validateConfigTree(location string, t *toml.Tree) []configProblem {
+	v := &configValidator{location: location, root: t, problems: make([]configProblem, 0)}
+
+	for _, section := range sortedTreeKeys(t) {
+		path := []string{section}
+		switch section {
+		case "include":
+			v.checkValue(path, "strings", t.GetPath(path))
+		case "aliases":
+			if table := v.checkTable(path, t.GetPath(path)); table != nil {
+				for _, key := range sortedTreeKeys(table) {
+					v.checkValue(append(path, key), "string", table.GetPath([]string{key}))
+				}
+			}
+		case "branch":
+			v.checkNestedTables(path, t.GetPath(path), func(tool []string, table *toml.Tree) {
+				v.checkSchema(tool, table, branchSchema)
+			})
+		case "profiles":
+			v.checkNestedTables(path, t.GetPath(path), func(entry []string, table *toml.Tree) {
+				if entry[len(entry)-1] != "env" {
+					v.checkSchema(entry, table, toolArgsSchema)
+					return
+				}
+				for _, key := range sortedTreeKeys(table) {
+					v.checkValue(append(entry, key), "string", table.GetPath([]string{key}))
+				}
+			})
+		case "tools":
+			if table := v.checkTable(path, t.GetPath(path)); table != nil {
+				for _, name := range sortedTreeKeys(table) {
+					tool := append(path, name)
+					if sub := v.checkTable(tool, table.GetPath([]string{name})); sub != nil {
+						v.checkSchema(tool, sub, customToolSchema)
+					}
+				}
+			}
+		default:
+			schema, ok := configSchema[section]
+			if !ok {
+				v.report(path, "is not a known section", true)
+				continue
+			}
+			if table := v.checkTable(path, t.GetPath(path)); table != nil {
+				v.checkSchema(path, table, schema)
+			}
+		}
+	}
+
+	return v.problems
+}
+
+// Checks the entries of a section with two levels of user defined tables, such as branch."release/*".gradle
+func (v *configValidator) checkNestedTables(path []string, value interface{}, check func([]string, *toml.Tree)) {
+	table := v.checkTable(path, value)
+	if table == nil {
+		return
+	}
+
+	for _, name := range sortedTreeKeys(table) {
+		outer := append(append(make([]string, 0), path...), name)
+		sub := v.checkTable(outer, table.GetPath([]string{name}))
+		if sub == nil {
+			continue
+		}
+		for _, key := range sortedTreeKeys(sub) {
+			inner := append(append(make([]string, 0), outer...), key)
+			if entry := v.checkTable(inner, sub.GetPath([]string{key})); entry != nil {
+				check(inner, entry)
+			}
+		}
+	}
+}
+
+func (v *configValidator) checkSchema(path []string, table *toml.Tree, schema map[string]string) {
+	for _, key := range sortedTreeKeys(table) {
+		keyPath := append(append(make([]string, 0), path...), key)
+		kind, ok := schema[key]
+		if !ok {
+			v.report(keyPath, "is not a known key", true)
+			continue
+		}
+		v.checkValue(keyPath, kind, table.GetPath([]string{key}))
+	}
+}
+
+func (v *configValidator) checkTable(path []string, value interface{}) *toml.Tree {
+	table, ok := value.(*toml.Tree)
+	if !ok {
+		v.report(path, "must be a table", false)
+	}
+	return table
+}
+
+func (v *configValidator) checkValue(path []string, kind string, value interface{}) {
+	switch kind {
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			v.report(path, "must be a boolean", false)
+		}
+	case "int":
+		if _, ok := value.(int64); !ok {
+			v.report(path, "must be an integer", false)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			v.report(path, "must be a string", false)
+		}
+	case "strings":
+		if !isStringArray(value) {
+			v.report(path, "must be an array of strings", false)
+		}
+	case "duration":
+		s, ok := value.(string)
+		if _, err := time.ParseDuration(s); !ok || err != nil {
+			v.report(path, "must be a duration, such as \"30m\"", false)
+		}
+	case "color":
+		if !isColorPair(value) {
+			v.report(path, "must be an array of two numbers between 0 and 255", false)
+		}
+	case "mappings":
+		table := v.checkTable(path, value)
+		if table == nil {
+			return
+		}
+		for _, key := range sortedTreeKeys(table) {
+			keyPath := append(append(make([]string, 0), path...), key)
+			v.checkValue(keyPath, "string", table.GetPath([]string{key}))
+			if strings.HasPrefix(key, "^") {
+				if _, err := regexp.Compile(key); err != nil {
+					v.report(keyPath, "is not a valid regular expression: "+err.Error(), false)
+				}
+			}
+		}
+	default:
+		s, ok := value.(string)
+		if !ok || !contains(configEnums[kind], s) {
+			v.report(path, "must be one of ["+strings.Join(configEnums[kind], ", ")+"]", false)
+		}
+	}
+}
+
+func (v *configValidator) report(path []string, message string, warning bool) {
+	v.problems = append(v.problems, configProblem{
+		location: v.location,
+		position: v.root.GetPositionPath(path),
+		key:      formatConfigKey(path),
+		message:  message,
+		warning:  warning})
+}
+
+// Prints the given problems. Returns false if any of them is an error
+func reportConfigProblems(problems []configProblem) bool {
+	ok := true
+	for _, p := range problems {
+		fmt.Fprintln(gumOutput, p.String())
+		if !p.warning {
+			ok = false
+		}
+	}
+	return ok
+}
+
+func isStringArray(value interface{}) bool {
+	data, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, e := range data {
+		if _, ok := e.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func isColorPair(value interface{}) bool {
+	data, ok := value.([]interface{})
+	if !ok || len(data) != 2 {
+		return false
+	}
+	for _, e := range data {
+		n, ok := e.(int64)
+		if !ok || n < 0 || n > 255 {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedTreeKeys(t *toml.Tree) []string {
+	keys := t.Keys()
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+)
+
+func TestValidateConfigTree(t *testing.T) {
+	// given:
+	doc := `include = ["common.toml"]
+
+[general]
+quiet = "yes"
+quite = true
+timeout = "soon"
+retries = 2
+
+[theme]
+name = "custom"
+symbol = [125, 300]
+
+[gradle.mappings]
+build = "check"
+"^it(.*$" = "integrationTest$1"
+compile = 1
+
+[branch."release/*".gradle]
+defaults = ["--no-daemon"]
+argz = []
+
+[profiles.ci.env]
+CI = true
+
+[tools.acme]
+markers = "acme.yml"
+
+[extras]
+foo = "bar"
+`
+	tree, _ := toml.Load(doc)
+
+	// when:
+	problems := validateConfigTree("gm.toml", tree)
+
+	// then:
+	var checks = []struct {
+		key, message string
+		line         int
+		warning      bool
+	}{
+		{"branch.\"release/*\".gradle.argz", "is not a known key", 20, true},
+		{"extras", "is not a known section", 28, true},
+		{"general.quiet", "must be a boolean", 4, false},
+		{"general.quite", "is not a known key", 5, true},
+		{"general.timeout", "must be a duration, such as \"30m\"", 6, false},
+		{"gradle.mappings.\"^it(.*$\"", "is not a valid regular expression: error parsing regexp: missing closing ): `^it(.*$`", 15, false},
+		{"gradle.mappings.compile", "must be a string", 16, false},
+		{"profiles.ci.env.CI", "must be a string", 23, false},
+		{"theme.symbol", "must be an array of two numbers between 0 and 255", 11, false},
+		{"tools.acme.markers", "must be an array of strings", 26, false},
+	}
+
+	if len(problems) != len(checks) {
+		t.Errorf("problems: got %d, want %d", len(problems), len(checks))
+		for _, p := range problems {
+			t.Log(p.String())
+		}
+		return
+	}
+	for i, check := range checks {
+		p := problems[i]
+		if p.key != check.key || p.message != check.message || p.warning != check.warning || p.position.Line != check.line {
+			t.Errorf("problem %d: got %s, want %s %s at line %d", i, p.String(), check.key, check.message, check.line)
+		}
+	}
+}
+
+func TestReadInvalidConfigFile(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".gm.toml")
+	ioutil.WriteFile(path, []byte("[general]\nquiet = 1\n"), 0644)
+
+	// when:
+	config := ReadConfigFile(testContext{}, path)
+
+	// then:
+	if config.general.q.WithMaybeAsFalse() {
+		t.Error("general.quiet: got true, want the invalid config to be skipped")
+	}
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".gm.toml")
+	ioutil.WriteFile(path, []byte("include = [\"common.yml\", \"missing.toml\"]\n\n[general]\nquiet = true\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "common.yml"), []byte("general:\n  debug: 1\n  colour: red\n"), 0644)
+
+	// when:
+	count := validateConfigFile(testContext{}, path, false, map[string]bool{path: true})
+
+	// then:
+	if count != 3 {
+		t.Errorf("got %d problems, want 3", count)
+	}
+}
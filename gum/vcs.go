@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+)
+
+// Discovery settings read from the user config, per version of the config file.
+// Guarded by discoveryMutex as detectors run concurrently
+var discoveryCache = make(map[string]*discovery)
+var discoveryMutex sync.Mutex
+
+// Entries that mark the root of a version control checkout
+var vcsMarkers = []string{".git", ".hg", ".svn"}
+
+// Finds the root of the version control checkout that contains dir
+func findVcsRootDir(context Context, dir string) (string, error) {
+	parentdir := filepath.Join(dir, "..")
+
+	for _, marker := range vcsMarkers {
+		if context.FileExists(filepath.Join(dir, marker)) {
+			return filepath.Abs(dir)
+		}
+	}
+
+	if parentdir == dir {
+		return "", errors.New("Did not find a VCS root")
+	}
+
+	return findVcsRootDir(context, parentdir)
+}
+
+// Resolves the directory where upward searches stop: the VCS root if dir
+// is inside a checkout, otherwise dir itself
+func resolveSearchBoundary(context Context, dir string) string {
+	if root, err := findVcsRootDir(context, dir); err == nil {
+		return root
+	}
+	abs, _ := filepath.Abs(dir)
+	return abs
+}
+
+// Checks if an upward search stops at dir once it has been searched, that is, dir is the root
+// of a VCS checkout, contains one of the configured boundary markers, or is maxDepth levels
+// above the working dir
+func isSearchBoundary(context Context, dir string) bool {
+	settings := resolveDiscovery(context)
+	if settings.maxDepth > 0 {
+		pwd, _ := filepath.Abs(context.GetWorkingDir())
+		abs, _ := filepath.Abs(dir)
+		if isParentDir(abs, pwd) && pathDistance(abs, pwd) >= settings.maxDepth {
+			return true
+		}
+	}
+
+	markers := settings.boundaryMarkers
+	if settings.vcsBoundary {
+		markers = append(append(make([]string, 0), vcsMarkers...), markers...)
+	}
+
+	for _, marker := range markers {
+		if context.FileExists(filepath.Join(dir, marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolves the discovery settings. Searches run before the project config is found,
+// thus only the user config applies
+func resolveDiscovery(context Context) *discovery {
+	path := findUserConfigFile(context)
+	key := versionCacheKey(path)
+	discoveryMutex.Lock()
+	defer discoveryMutex.Unlock()
+	if settings, ok := discoveryCache[key]; ok {
+		return settings
+	}
+
+	config := ReadUserConfig(context)
+	config.merge(nil)
+	discoveryCache[key] = &config.discovery
+	return &config.discovery
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSearchStopsAtBoundary(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "build.gradle"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "pom.xml"), []byte(""), 0644)
+	repo := filepath.Join(dir, "repo")
+	marked := filepath.Join(dir, "marked")
+	os.MkdirAll(filepath.Join(repo, ".git"), 0755)
+	os.MkdirAll(filepath.Join(repo, "module"), 0755)
+	os.MkdirAll(filepath.Join(marked, "module"), 0755)
+	os.MkdirAll(filepath.Join(dir, "deep", "module"), 0755)
+	ioutil.WriteFile(filepath.Join(marked, ".gum-boundary"), []byte(""), 0644)
+
+	var checks = []struct {
+		title  string
+		pwd    string
+		config string
+		found  bool
+	}{
+		{"VcsRoot", filepath.Join(repo, "module"), "", false},
+		{"VcsBoundaryDisabled", filepath.Join(repo, "module"), "[discovery]\nvcsBoundary = false\n", true},
+		{"NoMarker", filepath.Join(marked, "module"), "", true},
+		{"BoundaryMarker", filepath.Join(marked, "module"), "[discovery]\nboundaryMarkers = [\".gum-boundary\"]\n", false},
+		{"MaxDepthReached", filepath.Join(dir, "deep", "module"), "[discovery]\nmaxDepth = 1\n", false},
+		{"WithinMaxDepth", filepath.Join(dir, "deep", "module"), "[discovery]\nmaxDepth = 2\n", true},
+	}
+
+	for _, check := range checks {
+		home := filepath.Join(dir, "home", check.title)
+		os.MkdirAll(home, 0755)
+		if len(check.config) > 0 {
+			ioutil.WriteFile(filepath.Join(home, "gm.toml"), []byte(check.config), 0644)
+		}
+		context := testContext{
+			quiet:      true,
+			workingDir: check.pwd,
+			homeDir:    home,
+			env:        map[string]string{"GUM_HOME": home}}
+
+		// when:
+		walk := walkUp(context, check.pwd)
+		gradle, gradleErr := findGradleBuildFile(walk, check.pwd)
+		maven, mavenErr := findMavenBuildFile(walk, check.pwd)
+
+		// then:
+		if (gradleErr == nil) != check.found {
+			t.Errorf("%s: got Gradle build file %q, want found = %t", check.title, gradle, check.found)
+		}
+		if (mavenErr == nil) != check.found {
+			t.Errorf("%s: got Maven build file %q, want found = %t", check.title, maven, check.found)
+		}
+	}
+}
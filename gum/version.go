@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var gradleDistributionPattern = regexp.MustCompile(`gradle-(.+)-(bin|all)\.zip$`)
+var mavenDistributionPattern = regexp.MustCompile(`apache-maven-(.+)-bin\.(zip|tar\.gz)$`)
+var gradleVersionPattern = regexp.MustCompile(`(?m)^Gradle (\S+)`)
+var mavenVersionPattern = regexp.MustCompile(`(?m)^Apache Maven (\S+)`)
+var javaVersionPattern = regexp.MustCompile(`version "([^"]+)"`)
+
+// Resolves the version of a Gradle or Maven installation. Reads the wrapper
+// properties when available, falls back to running the tool with --version.
+// Results of the latter are cached until the executable changes. As the tool
+// may be a project wrapper, only call it once the wrapper has been trusted
+func resolveToolVersion(context Context, tool string, executable string) string {
+	if version := readKnownToolVersion(context, tool, executable); len(version) > 0 {
+		return version
+	}
+
+	cache := filepath.Join(resolveCacheDir(context), "versions")
+	key := versionCacheKey(executable)
+	versions := readVersionCache(cache)
+	version := runToolVersion(tool, executable)
+	if len(version) > 0 && len(key) > 0 {
+		versions[key] = version
+		writeVersionCache(cache, versions)
+	}
+	return version
+}
+
+// Resolves the version of a Gradle or Maven installation from the wrapper properties
+// or the versions cache, without running the tool. Returns an empty string if unknown
+func readKnownToolVersion(context Context, tool string, executable string) string {
+	path := resolveWrapperProperties(context, executable)
+	if len(path) > 0 {
+		props, err := readProperties(path)
+		if err == nil {
+			version := parseDistributionVersion(tool, props["distributionUrl"])
+			if len(version) > 0 {
+				return version
+			}
+		}
+	}
+
+	versions := readVersionCache(filepath.Join(resolveCacheDir(context), "versions"))
+	return versions[versionCacheKey(executable)]
+}
+
+func runToolVersion(tool string, executable string) string {
+	var out []byte
+	var err error
+	if tool == "java" {
+		// java -version prints to stderr
+		out, err = exec.Command(executable, "-version").CombinedOutput()
+	} else {
+		out, err = exec.Command(executable, "--version").Output()
+	}
+	if err != nil {
+		return ""
+	}
+
+	var m []string
+	switch tool {
+	case "java":
+		m = javaVersionPattern.FindStringSubmatch(string(out))
+	case "gradle":
+		m = gradleVersionPattern.FindStringSubmatch(string(out))
+	case "maven":
+		m = mavenVersionPattern.FindStringSubmatch(string(out))
+	}
+
+	if len(m) > 1 {
+		return m[1]
+	}
+	return ""
+}
+
+// Resolves the version of the JDK found at $JAVA_HOME or in $PATH
+func resolveJavaVersion(context Context) string {
+	java := "java"
+	if context.IsWindows() {
+		java = "java.exe"
+	}
+
+	candidates := make([]string, 0)
+	if home := context.Getenv("JAVA_HOME"); len(home) > 0 {
+		candidates = append(candidates, filepath.Join(home, "bin", java))
+	}
+	for _, path := range context.GetPaths() {
+		candidates = append(candidates, filepath.Join(path, java))
+	}
+
+	for _, candidate := range candidates {
+		if context.FileExists(candidate) {
+			return resolveToolVersion(context, "java", candidate)
+		}
+	}
+	return ""
+}
+
+// Identifies an executable by its path and modification time
+func versionCacheKey(executable string) string {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatInt(info.ModTime().Unix(), 10) + " " + executable
+}
+
+// Reads cached versions. Each line has the format "version mtime path"
+func readVersionCache(path string) map[string]string {
+	versions := make(map[string]string)
+
+	doc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return versions
+	}
+
+	for _, line := range strings.Split(string(doc), "\n") {
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) == 2 {
+			versions[parts[1]] = parts[0]
+		}
+	}
+
+	return versions
+}
+
+func writeVersionCache(path string, versions map[string]string) {
+	lines := make([]string, 0)
+	for key, version := range versions {
+		lines = append(lines, version+" "+key)
+	}
+	sort.Strings(lines)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+		ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	}
+}
+
+// Returns the major version, such as 7 for 7.0-rc-1, or 0 if unknown
+func majorVersion(version string) int {
+	parts, _ := splitVersion(version)
+	if len(parts) == 0 {
+		return 0
+	}
+	return parts[0]
+}
+
+// Extracts the version from a wrapper distribution URL
+func parseDistributionVersion(tool string, url string) string {
+	var m []string
+	switch tool {
+	case "gradle":
+		m = gradleDistributionPattern.FindStringSubmatch(url)
+	case "maven":
+		m = mavenDistributionPattern.FindStringSubmatch(url)
+	}
+
+	if len(m) > 1 {
+		return m[1]
+	}
+	return ""
+}
+
+// Resolves the version and type (bin or all) of the distribution used by a Gradle wrapper.
+// Returns empty strings if the executable is not a wrapper
+func resolveGradleDistribution(context Context, executable string) (string, string) {
+	if filepath.Base(executable) != resolveGradleWrapperExec(context) {
+		return "", ""
+	}
+
+	path := resolveWrapperProperties(context, executable)
+	if len(path) == 0 {
+		return "", ""
+	}
+	props, err := readProperties(path)
+	if err != nil {
+		return "", ""
+	}
+
+	if m := gradleDistributionPattern.FindStringSubmatch(props["distributionUrl"]); len(m) > 2 {
+		return m[1], m[2]
+	}
+	return "", ""
+}
+
+// Compares two versions such as 6.8.3 and 7.0-rc-1.
+// Returns -1, 0, or 1. Pre-releases are lower than their final release
+func compareVersions(a string, b string) int {
+	pa, qa := splitVersion(a)
+	pb, qb := splitVersion(b)
+
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	}
+
+	if qa == qb {
+		return 0
+	} else if len(qa) == 0 {
+		return 1
+	} else if len(qb) == 0 {
+		return -1
+	} else if qa < qb {
+		return -1
+	}
+	return 1
+}
+
+// Splits a version into its numeric parts and qualifier
+func splitVersion(v string) ([]int, string) {
+	qualifier := ""
+	if i := strings.IndexAny(v, "-_+"); i > -1 {
+		qualifier = strings.ToLower(v[i+1:])
+		v = v[:i]
+	}
+
+	parts := make([]int, 0)
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+
+	return parts, qualifier
+}
+
+// Caches the version of a Gradle installation that could not be read from its wrapper
+// properties, such as gradle found in path, thus the next configure step can pick args
+// supported by that version. Only called once the executable has been trusted
+func probeToolVersion(context Context, inv invocation) {
+	if inv.tool == "gradle" {
+		resolveToolVersion(context, inv.tool, inv.executable)
+	}
+}
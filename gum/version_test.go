@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	var checks = []struct {
+		a, b     string
+		expected int
+	}{
+		{"7.0", "7.0", 0},
+		{"7.0", "7.0.0", 0},
+		{"6.8.3", "7.0", -1},
+		{"7.0.1", "7.0", 1},
+		{"7.0-rc-1", "7.0", -1},
+		{"3.8.1", "3.6.3", 1},
+	}
+
+	for _, check := range checks {
+		actual := compareVersions(check.a, check.b)
+		if actual != check.expected {
+			t.Errorf("%s <=> %s: got %d, want %d", check.a, check.b, actual, check.expected)
+		}
+	}
+}
+
+func TestParseDistributionVersion(t *testing.T) {
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Gradle bin", parseDistributionVersion("gradle", "https://services.gradle.org/distributions/gradle-7.0-bin.zip"), "7.0"},
+		{"Gradle all", parseDistributionVersion("gradle", "https://services.gradle.org/distributions/gradle-6.8.3-all.zip"), "6.8.3"},
+		{"Gradle rc", parseDistributionVersion("gradle", "https://services.gradle.org/distributions/gradle-7.1-rc-1-bin.zip"), "7.1-rc-1"},
+		{"Maven", parseDistributionVersion("maven", "https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.8.1/apache-maven-3.8.1-bin.zip"), "3.8.1"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestResolveGradleDistribution(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	os.MkdirAll(filepath.Join(dir, "gradle", "wrapper"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "gradle", "wrapper", "gradle-wrapper.properties"),
+		[]byte("distributionUrl=https\\://services.gradle.org/distributions/gradle-8.7-all.zip\n"), 0644)
+	context := testContext{workingDir: dir}
+
+	var checks = []struct {
+		title, executable, version, distribution string
+	}{
+		{"Wrapper", filepath.Join(dir, "gradlew"), "8.7", "all"},
+		{"NotAWrapper", filepath.Join(dir, "gradle"), "", ""},
+		{"NoProperties", filepath.Join(dir, "other", "gradlew"), "", ""},
+	}
+
+	for _, check := range checks {
+		// when:
+		version, distribution := resolveGradleDistribution(context, check.executable)
+
+		// then:
+		if version != check.version || distribution != check.distribution {
+			t.Errorf("%s: got %s (%s), want %s (%s)", check.title, version, distribution, check.version, check.distribution)
+		}
+	}
+}
+
+func TestVersionCache(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+	cache := filepath.Join(home, "versions")
+	executable := filepath.Join(home, "gradle")
+	ioutil.WriteFile(executable, []byte(""), 0755)
+
+	// when:
+	writeVersionCache(cache, map[string]string{versionCacheKey(executable): "6.8.3"})
+	versions := readVersionCache(cache)
+
+	// then:
+	if versions[versionCacheKey(executable)] != "6.8.3" {
+		t.Errorf("Expected cached version 6.8.3, got %v", versions)
+	}
+	if majorVersion("7.0-rc-1") != 7 || majorVersion("") != 0 {
+		t.Error("Unexpected major version")
+	}
+}
+
+func TestMinimumVersion(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "security", "wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd}
+
+	inv := invocation{tool: "gradle", executable: filepath.Join(pwd, "gradlew")}
+
+	var checks = []struct {
+		title    string
+		version  string
+		mode     string
+		expected bool
+	}{
+		{"Newer", "6.8", "block", true},
+		{"Warn", "7.1", "warn", true},
+		{"Block", "7.1", "block", false},
+	}
+
+	for _, check := range checks {
+		config := newConfig()
+		config.policy.gradleVersion = check.version
+		config.policy.versionsMode = check.mode
+
+		// when:
+		actual := verifyMinimumVersion(context, config, inv)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %t, want %t", check.title, actual, check.expected)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"path/filepath"
+)
+
+// upwardWalk holds the listings of every dir from a starting dir up to the search
+// boundary, or the file system root, thus finders share a single pass over the tree
+type upwardWalk struct {
+	context Context
+	dirs    []dirListing
+}
+
+// dirListing holds the names found in a dir. Names is nil if the dir could not be listed
+type dirListing struct {
+	dir   string
+	names map[string]bool
+}
+
+// Walks up from dir, reading each listing once. The file system root is not searched,
+// as with the per-file finders the walk replaces
+func walkUp(context Context, dir string) *upwardWalk {
+	walk := &upwardWalk{context: context}
+	dir = filepath.Clean(dir)
+
+	for {
+		parentdir := filepath.Join(dir, "..")
+		if parentdir == dir {
+			break
+		}
+
+		walk.dirs = append(walk.dirs, listDir(dir))
+		if isSearchBoundary(context, dir) {
+			break
+		}
+		dir = parentdir
+	}
+
+	return walk
+}
+
+// Reads the names found in dir
+func listDir(dir string) dirListing {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return dirListing{dir: dir}
+	}
+
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		names[entry.Name()] = true
+	}
+	return dirListing{dir: dir, names: names}
+}
+
+// Returns the listings from dir up. Dirs outside of the walk, such as an explicit
+// project dir, are walked on their own
+func (w *upwardWalk) from(dir string) []dirListing {
+	dir = filepath.Clean(dir)
+	for i := range w.dirs {
+		if w.dirs[i].dir == dir {
+			return w.dirs[i:]
+		}
+	}
+	return walkUp(w.context, dir).dirs
+}
+
+// Resolves the symlinks of path, such as a wrapper or a dir linked by direnv or Nix, unless
+// discovery.resolveSymlinks is disabled. Returns path as is if it cannot be resolved
+func resolveRealPath(context Context, path string) string {
+	if len(path) == 0 || !resolveDiscovery(context).resolveSymlinks {
+		return path
+	}
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return path
+}
+
+// Finds the first of names in dir. Falls back to probing each name if dir could not be listed
+func (w *upwardWalk) find(listing dirListing, names ...string) (string, bool) {
+	for _, name := range names {
+		path := filepath.Join(listing.dir, name)
+		if listing.names == nil {
+			if w.context.FileExists(path) {
+				return path, true
+			}
+		} else if listing.names[name] {
+			return path, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWalkUp(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	root := filepath.Join(dir, "root")
+	module := filepath.Join(root, "module")
+	nested := filepath.Join(module, "src", "main")
+	os.MkdirAll(nested, 0755)
+	os.MkdirAll(filepath.Join(root, ".git"), 0755)
+	ioutil.WriteFile(filepath.Join(root, "settings.gradle"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(root, "build.gradle"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(root, "gradlew"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(root, "pom.xml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(module, "module.gradle.kts"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(module, "pom.xml"), []byte(""), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "pom.xml"), []byte(""), 0644)
+	home := filepath.Join(dir, "home")
+	os.MkdirAll(home, 0755)
+	context := testContext{
+		quiet:      true,
+		workingDir: nested,
+		homeDir:    home,
+		env:        map[string]string{"GUM_HOME": home}}
+
+	// when:
+	walk := walkUp(context, nested)
+
+	// then:
+	if len(walk.dirs) != 4 || walk.dirs[3].dir != root {
+		t.Errorf("Walk: got %d dirs, want 4 stopping at %s", len(walk.dirs), root)
+	}
+
+	var checks = []struct {
+		title    string
+		find     func() (string, error)
+		expected string
+	}{
+		{"BuildFile", func() (string, error) { return findGradleBuildFile(walk, nested) }, filepath.Join(module, "module.gradle.kts")},
+		{"SettingsFile", func() (string, error) { return findGradleSettingsFile(walk, nested) }, filepath.Join(root, "settings.gradle")},
+		{"RootFile", func() (string, error) { return findGradleRootFile(walk, module, "") }, filepath.Join(root, "build.gradle")},
+		{"GradleWrapper", func() (string, error) { return findGradleWrapperExec(walk, nested) }, filepath.Join(root, "gradlew")},
+		{"Pom", func() (string, error) { return findMavenBuildFile(walk, nested) }, filepath.Join(module, "pom.xml")},
+		{"RootPom", func() (string, error) { return findMavenRootFile(walk, filepath.Join(module, "..")) }, filepath.Join(root, "pom.xml")},
+		{"MavenWrapper", func() (string, error) { return findMavenWrapperExec(walk, nested) }, ""},
+		{"OutsideWalk", func() (string, error) { return findMavenBuildFile(walk, dir) }, filepath.Join(dir, "pom.xml")},
+	}
+
+	for _, check := range checks {
+		// when:
+		path, err := check.find()
+
+		// then:
+		if len(check.expected) == 0 {
+			if err == nil {
+				t.Errorf("%s: got %s, want none", check.title, path)
+			}
+		} else if path != check.expected {
+			t.Errorf("%s: got %q (%v), want %s", check.title, path, err, check.expected)
+		}
+	}
+}
+
+func TestResolveSymlinks(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	dir, _ = filepath.EvalSymlinks(dir)
+	shared := filepath.Join(dir, "shared")
+	project := filepath.Join(dir, "project")
+	linked := filepath.Join(dir, "linked")
+	os.MkdirAll(shared, 0755)
+	os.MkdirAll(project, 0755)
+	ioutil.WriteFile(filepath.Join(shared, "mvnw"), []byte(""), 0755)
+	ioutil.WriteFile(filepath.Join(project, "pom.xml"), []byte(""), 0644)
+	os.Symlink(filepath.Join(shared, "mvnw"), filepath.Join(project, "mvnw"))
+	os.Symlink(project, linked)
+
+	var checks = []struct {
+		title      string
+		config     string
+		buildFile  string
+		executable string
+	}{
+		{"Resolved", "", filepath.Join(project, "pom.xml"), filepath.Join(shared, "mvnw")},
+		{"Disabled", "[discovery]\nresolveSymlinks = false\n", filepath.Join(linked, "pom.xml"), filepath.Join(linked, "mvnw")},
+	}
+
+	for _, check := range checks {
+		home := filepath.Join(dir, "home", check.title)
+		os.MkdirAll(home, 0755)
+		ioutil.WriteFile(filepath.Join(home, "gm.toml"), []byte(check.config), 0644)
+		context := testContext{
+			quiet:      true,
+			workingDir: linked,
+			homeDir:    home,
+			env:        map[string]string{"GUM_HOME": home}}
+
+		// when:
+		args := ParseArgs([]string{"--gum-tool", "maven", "verify"})
+		cmd, ok := FindTool(context, &args).(*MavenCommand)
+
+		// then:
+		if !ok {
+			t.Errorf("%s: Maven not found", check.title)
+			continue
+		}
+		if cmd.buildFile != check.buildFile {
+			t.Errorf("%s: got build file %s, want %s", check.title, cmd.buildFile, check.buildFile)
+		}
+		if cmd.executable != check.executable {
+			t.Errorf("%s: got executable %s, want %s", check.title, cmd.executable, check.executable)
+		}
+	}
+}
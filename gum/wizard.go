@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RunWizard offers to set up user preferences on the very first execution,
+// that is, when neither Gum's state directory nor the user config exist.
+// Skipped with --no-wizard, $GUM_NO_WIZARD, -gq, when not interactive, or for
+// gum subcommands such as "gm gum doctor", as those may be run by scripts
+func RunWizard(context Context, args *ParsedArgs) {
+	if IsGumCommand(args) {
+		return
+	}
+
+	stateDir := resolveStateDir(context)
+	if context.FileExists(stateDir) || context.FileExists(findUserConfigFile(context)) {
+		return
+	}
+
+	if args.HasGumFlag("gq") || !isInteractive() || len(context.Getenv("CI")) > 0 {
+		return
+	}
+
+	// remember the wizard has been offered, even if skipped
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return
+	}
+
+	if args.HasGumFlag("-no-wizard") || isTruthy(context.Getenv("GUM_NO_WIZARD")) {
+		return
+	}
+
+	runWizard(context, os.Stdin)
+}
+
+func runWizard(context Context, in io.Reader) {
+	reader := bufio.NewReader(in)
+
+	fmt.Fprintln(gumOutput, "Welcome to Gum! Let's set up your preferences (press enter to accept defaults).")
+	quiet := ask(reader, "Run in quiet mode?", "no")
+	theme := ask(reader, "Color theme (dark, light, none)?", "dark")
+	discovery := ask(reader, "Tool discovery order?", strings.Join(defaultDiscovery, ", "))
+	telemetry := ask(reader, "Share anonymous usage statistics?", "no")
+	fmt.Fprintln(gumOutput, "")
+
+	path := resolveUserConfigFile(context)
+	err := writeConfigLines(path, formatWizardConfig(quiet, theme, discovery, telemetry))
+	if err != nil {
+		fmt.Fprintln(gumOutput, err)
+		return
+	}
+
+	fmt.Fprintln(gumOutput, "Preferences saved to "+path)
+	fmt.Fprintln(gumOutput, "")
+	fmt.Fprintln(gumOutput, "Some flags you may find useful")
+	fmt.Fprintln(gumOutput, "  -gd\tdisplays debug information")
+	fmt.Fprintln(gumOutput, "  -gn\texecutes nearest build file")
+	fmt.Fprintln(gumOutput, "  -gq\trun gm in quiet mode")
+	fmt.Fprintln(gumOutput, "  -gr\tdo not replace goals/tasks")
+	fmt.Fprintln(gumOutput, "  -gh\tdisplays help information")
+	fmt.Fprintln(gumOutput, "")
+}
+
+// Asks a question, returning the default value if no answer is given
+func ask(reader *bufio.Reader, question string, defaultValue string) string {
+	fmt.Fprint(gumOutput, question+" ["+defaultValue+"] ")
+
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if len(answer) == 0 {
+		return defaultValue
+	}
+	return answer
+}
+
+// Formats the answers given to the wizard as config lines. Invalid answers
+// fall back to their defaults
+func formatWizardConfig(quiet string, theme string, discovery string, telemetry string) []string {
+	switch theme {
+	case "dark", "light", "none":
+	default:
+		theme = "dark"
+	}
+
+	tools := make([]string, 0)
+	for _, tool := range strings.Split(discovery, ",") {
+		tool = strings.ToLower(strings.TrimSpace(tool))
+		if contains(defaultDiscovery, tool) && !contains(tools, tool) {
+			tools = append(tools, tool)
+		}
+	}
+	// unlisted tools are discovered afterwards in default order
+	if len(tools) == 0 {
+		tools = defaultDiscovery
+	}
+
+	quoted := make([]string, len(tools))
+	for i, tool := range tools {
+		quoted[i] = strconv.Quote(tool)
+	}
+
+	return []string{
+		"[theme]",
+		"name = " + strconv.Quote(theme),
+		"",
+		"[general]",
+		"quiet = " + strconv.FormatBool(isYes(quiet)),
+		"discovery = [" + strings.Join(quoted, ", ") + "]",
+		"telemetry = " + strconv.FormatBool(isYes(telemetry))}
+}
+
+// Checks if a wizard answer is a yes, anything else is considered a no
+func isYes(answer string) bool {
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes"
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWizard(t *testing.T) {
+	// given:
+	home, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(home)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: home,
+		homeDir:    home}
+
+	// when:
+	runWizard(context, strings.NewReader("yes\nsepia\nmaven, gradle, ant, jbang, bach\ny\n"))
+
+	// then:
+	config := ReadConfigFile(context, resolveUserConfigFile(context))
+	if !config.general.q.WithMaybeAsFalse() {
+		t.Error("Expected quiet to be enabled")
+	}
+	if config.theme.name != "dark" {
+		t.Errorf("Expected invalid theme to fall back to dark, got %s", config.theme.name)
+	}
+	if strings.Join(config.general.discovery, ",") != "maven,gradle,ant,jbang,bach" {
+		t.Errorf("Unexpected discovery order %v", config.general.discovery)
+	}
+	if !config.general.telemetry {
+		t.Error("Expected telemetry to be enabled")
+	}
+}
+
+func TestWizardConfigDefaults(t *testing.T) {
+	lines := formatWizardConfig("no", "light", "scons", "")
+
+	if lines[5] != `discovery = ["node", "grails", "gradle", "maven", "sbt", "bazel", "buck2", "lein", "clojure", "boot", "bld", "ant", "bach", "cmake", "make", "jbang"]` {
+		t.Errorf("Expected default discovery order, got %s", lines[5])
+	}
+	if lines[4] != "quiet = false" {
+		t.Errorf("Expected quiet = false, got %s", lines[4])
+	}
+	if lines[6] != "telemetry = false" {
+		t.Errorf("Expected telemetry = false, got %s", lines[6])
+	}
+}
+
+func TestWizardConfigPartialDiscovery(t *testing.T) {
+	lines := formatWizardConfig("no", "light", "sbt, gradle", "no")
+
+	if lines[5] != `discovery = ["sbt", "gradle"]` {
+		t.Errorf("Expected partial discovery order, got %s", lines[5])
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Location of Gradle distributions, along with the wrapper jar of each release
+var gradleDistributionsURL = "https://services.gradle.org/distributions"
+
+// Resolves the jar used by a wrapper script, if the executable is a wrapper
+func resolveWrapperJar(context Context, executable string) string {
+	dir := filepath.Dir(executable)
+	var jar string
+
+	switch filepath.Base(executable) {
+	case resolveGradleWrapperExec(context):
+		jar = filepath.Join(dir, "gradle", "wrapper", "gradle-wrapper.jar")
+	case resolveMavenWrapperExec(context):
+		jar = filepath.Join(dir, ".mvn", "wrapper", "maven-wrapper.jar")
+	}
+
+	if len(jar) > 0 && context.FileExists(jar) {
+		return jar
+	}
+	return ""
+}
+
+// Resolves the properties file used by a wrapper script, if the executable is a wrapper
+func resolveWrapperProperties(context Context, executable string) string {
+	dir := filepath.Dir(executable)
+	var props string
+
+	switch filepath.Base(executable) {
+	case resolveGradleWrapperExec(context):
+		props = filepath.Join(dir, "gradle", "wrapper", "gradle-wrapper.properties")
+	case resolveMavenWrapperExec(context):
+		props = filepath.Join(dir, ".mvn", "wrapper", "maven-wrapper.properties")
+	}
+
+	if len(props) > 0 && context.FileExists(props) {
+		return props
+	}
+	return ""
+}
+
+// Offers to download the wrapper jar of the Gradle version set in gradle-wrapper.properties
+// when gradlew exists but its jar is missing, such as after aggressive .gitignore rules.
+// Returns false if the jar is still missing
+func repairGradleWrapperJar(context Context, config *Config, inv invocation) bool {
+	if filepath.Base(inv.executable) != resolveGradleWrapperExec(context) {
+		return true
+	}
+
+	jar := filepath.Join(filepath.Dir(inv.executable), "gradle", "wrapper", "gradle-wrapper.jar")
+	if context.FileExists(jar) {
+		return true
+	}
+
+	version, _ := resolveGradleDistribution(context, inv.executable)
+	if len(version) == 0 {
+		// nothing to repair from, let the wrapper report the problem
+		return true
+	}
+
+	fmt.Fprintln(gumOutput, "Missing "+jar)
+	if !inv.args.HasGumFlag("gy") {
+		if !isInteractive() {
+			fmt.Fprintln(gumOutput, "Refusing to run "+inv.executable)
+			fmt.Fprintln(gumOutput, "Run again with -gy to download the wrapper jar of Gradle "+version)
+			return false
+		}
+		if !confirm("Download the wrapper jar of Gradle " + version + "?") {
+			return false
+		}
+	}
+
+	if err := downloadGradleWrapperJar(version, jar); err != nil {
+		fmt.Fprintln(gumOutput, "Could not download the wrapper jar of Gradle "+version)
+		fmt.Fprintln(gumOutput, err)
+		return false
+	}
+
+	if !config.general.quiet {
+		fmt.Fprintln(gumOutput, "Downloaded the wrapper jar of Gradle "+version+" to "+jar)
+	}
+	return true
+}
+
+// Downloads the wrapper jar of a Gradle release, verified against its published checksum
+func downloadGradleWrapperJar(version string, jar string) error {
+	location := gradleDistributionsURL + "/gradle-" + version + "-wrapper.jar"
+	data, err := fetchURL(location)
+	if err != nil {
+		return err
+	}
+	expected, err := fetchURL(location + ".sha256")
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != strings.ToLower(strings.TrimSpace(string(expected))) {
+		return errors.New("checksum of " + location + " does not match")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(jar), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(jar, data, 0644)
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepairGradleWrapperJar(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	sum := sha256.Sum256([]byte("wrapper"))
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gradle-8.7-wrapper.jar", "/gradle-8.6-wrapper.jar":
+			w.Write([]byte("wrapper"))
+		case "/gradle-8.7-wrapper.jar.sha256":
+			w.Write([]byte(checksum + "\n"))
+		case "/gradle-8.6-wrapper.jar.sha256":
+			w.Write([]byte("0000\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	gradleDistributionsURL = server.URL
+	defer func() { gradleDistributionsURL = "https://services.gradle.org/distributions" }()
+
+	var checks = []struct {
+		title    string
+		version  string
+		present  bool
+		expected bool
+		content  string
+	}{
+		{"Present", "8.7", true, true, "existing"},
+		{"Missing", "8.7", false, true, "wrapper"},
+		{"Mismatch", "8.6", false, false, ""},
+		{"Unknown", "9.9", false, false, ""},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			pwd := filepath.Join(dir, check.title)
+			jar := filepath.Join(pwd, "gradle", "wrapper", "gradle-wrapper.jar")
+			os.MkdirAll(filepath.Dir(jar), 0755)
+			ioutil.WriteFile(filepath.Join(pwd, "gradlew"), []byte(""), 0755)
+			ioutil.WriteFile(filepath.Join(pwd, "gradle", "wrapper", "gradle-wrapper.properties"),
+				[]byte("distributionUrl=https\\://services.gradle.org/distributions/gradle-"+check.version+"-bin.zip\n"), 0644)
+			if check.present {
+				ioutil.WriteFile(jar, []byte("existing"), 0644)
+			}
+
+			context := testContext{
+				quiet:      true,
+				explicit:   true,
+				windows:    false,
+				workingDir: pwd}
+
+			config := newConfig()
+			config.general.quiet = true
+			args := ParseArgs([]string{"-gq", "-gy"})
+			inv := invocation{executable: filepath.Join(pwd, "gradlew"), args: &args}
+
+			// when:
+			repaired := repairGradleWrapperJar(context, config, inv)
+
+			// then:
+			if repaired != check.expected {
+				t.Errorf("Repaired: got %v, want %v", repaired, check.expected)
+			}
+			content, _ := ioutil.ReadFile(jar)
+			if string(content) != check.content {
+				t.Errorf("Jar: got %q, want %q", string(content), check.content)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lists every Gradle release along with the location of its wrapper jar checksum
+var gradleVersionsURL = "https://services.gradle.org/versions/all"
+
+// Time given to fetch the list of releases and each checksum
+const wrapperChecksumsTimeout = 10 * time.Second
+
+// Minimum time between online refreshes of the cached checksums
+const wrapperChecksumsRefresh = 24 * time.Hour
+
+// Number of checksums fetched at the same time
+const wrapperChecksumsWorkers = 4
+
+// Checksums of official wrapper jars known at build time, one "<checksum> <version>"
+// entry per line. Newer releases are fetched online and cached
+var bundledWrapperChecksums = ``
+
+type gradleRelease struct {
+	Version            string `json:"version"`
+	Snapshot           bool   `json:"snapshot"`
+	Nightly            bool   `json:"nightly"`
+	WrapperChecksumURL string `json:"wrapperChecksumUrl"`
+}
+
+// Verifies that the wrapper jar used by gradlew matches one published by Gradle.
+// Returns false only if validation is set to block
+func verifyGradleWrapperJar(context Context, config *Config, executable string) bool {
+	mode := config.gradle.validation
+	if (mode != "warn" && mode != "block") || filepath.Base(executable) != resolveGradleWrapperExec(context) {
+		return true
+	}
+
+	jar := resolveWrapperJar(context, executable)
+	if len(jar) == 0 {
+		return true
+	}
+
+	checksum, err := computeChecksum(jar)
+	if err != nil {
+		fmt.Fprintln(gumOutput, "Could not compute checksum of "+jar)
+		fmt.Fprintln(gumOutput, err)
+		return mode != "block"
+	}
+
+	known, err := resolveWrapperChecksums(context, config, checksum)
+	if err != nil && len(known) == 0 {
+		fmt.Fprintln(gumOutput, "Could not verify "+jar)
+		fmt.Fprintln(gumOutput, err)
+		return mode != "block"
+	}
+
+	if version, ok := known[checksum]; ok {
+		if config.general.debug {
+			fmt.Fprintln(gumOutput, "wrapper jar          = gradle "+version)
+		}
+		return true
+	}
+
+	if mode == "block" {
+		fmt.Fprintln(gumOutput, "Refusing to run "+executable)
+	} else {
+		fmt.Fprintln(gumOutput, "WARNING: wrapper jar does not match any official Gradle release")
+	}
+	fmt.Fprintln(gumOutput, "  jar    = "+jar)
+	fmt.Fprintln(gumOutput, "  sha256 = "+checksum)
+	return mode != "block"
+}
+
+// Resolves the known wrapper jar checksums, keyed by checksum. The cache is refreshed
+// online when the given checksum is unknown, at most once per refresh period
+func resolveWrapperChecksums(context Context, config *Config, checksum string) (map[string]string, error) {
+	known := parseWrapperChecksums(strings.NewReader(bundledWrapperChecksums))
+
+	cache := filepath.Join(resolveCacheDir(context), "wrapper-checksums")
+	if file, err := os.Open(cache); err == nil {
+		for k, v := range parseWrapperChecksums(file) {
+			known[k] = v
+		}
+		file.Close()
+	}
+
+	if _, ok := known[checksum]; ok {
+		return known, nil
+	}
+	if info, err := os.Stat(cache); err == nil && time.Since(info.ModTime()) < wrapperChecksumsRefresh {
+		return known, nil
+	}
+
+	fetched, err := fetchWrapperChecksums(known)
+	for k, v := range fetched {
+		known[k] = v
+	}
+	if err != nil {
+		return known, err
+	}
+
+	if err := writeWrapperChecksums(cache, known); err != nil && config.general.debug {
+		fmt.Fprintln(gumOutput, "Could not write "+cache)
+		fmt.Fprintln(gumOutput, err)
+	}
+	return known, nil
+}
+
+// Reads "<checksum> <version>" entries, skipping blank lines and comments
+func parseWrapperChecksums(r io.Reader) map[string]string {
+	checksums := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		version := ""
+		if len(fields) > 1 {
+			version = fields[1]
+		}
+		checksums[strings.ToLower(fields[0])] = version
+	}
+	return checksums
+}
+
+func writeWrapperChecksums(path string, checksums map[string]string) error {
+	lines := make([]string, 0, len(checksums))
+	for k, v := range checksums {
+		lines = append(lines, k+" "+v)
+	}
+	sort.Strings(lines)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// Fetches the wrapper jar checksums of releases missing in known
+func fetchWrapperChecksums(known map[string]string) (map[string]string, error) {
+	body, err := fetchURL(gradleVersionsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []gradleRelease
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]bool)
+	for _, v := range known {
+		versions[v] = true
+	}
+
+	pending := make(chan gradleRelease)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var failure error
+	fetched := make(map[string]string)
+
+	for i := 0; i < wrapperChecksumsWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for release := range pending {
+				body, err := fetchURL(release.WrapperChecksumURL)
+				mutex.Lock()
+				if err != nil {
+					failure = err
+				} else {
+					fetched[strings.ToLower(strings.TrimSpace(string(body)))] = release.Version
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, release := range releases {
+		if release.Snapshot || release.Nightly || len(release.WrapperChecksumURL) == 0 || versions[release.Version] {
+			continue
+		}
+		pending <- release
+	}
+	close(pending)
+	wg.Wait()
+
+	return fetched, failure
+}
+
+func fetchURL(location string) ([]byte, error) {
+	client := &http.Client{Timeout: wrapperChecksumsTimeout}
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(location + " responded with " + resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyGradleWrapperJar(t *testing.T) {
+	// given:
+	dir, _ := ioutil.TempDir("", "gum")
+	defer os.RemoveAll(dir)
+	sum := sha256.Sum256([]byte("official"))
+	official := hex.EncodeToString(sum[:])
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/versions/all":
+			w.Write([]byte(`[
+  {"version": "8.8-20240101000000+0000", "snapshot": true, "wrapperChecksumUrl": "` + server.URL + `/snapshot.sha256"},
+  {"version": "8.7", "snapshot": false, "wrapperChecksumUrl": "` + server.URL + `/8.7.sha256"}
+]`))
+		case "/8.7.sha256":
+			w.Write([]byte(official + "\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	gradleVersionsURL = server.URL + "/versions/all"
+	defer func() { gradleVersionsURL = "https://services.gradle.org/versions/all" }()
+
+	var checks = []struct {
+		title    string
+		mode     string
+		jar      string
+		offline  bool
+		expected bool
+	}{
+		{"Off", "off", "tampered", false, true},
+		{"Warn", "warn", "tampered", false, true},
+		{"Block", "block", "tampered", false, false},
+		{"Official", "block", "official", false, true},
+		{"Cached", "block", "official", true, true},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			pwd := filepath.Join(dir, check.title)
+			os.MkdirAll(filepath.Join(pwd, "gradle", "wrapper"), 0755)
+			ioutil.WriteFile(filepath.Join(pwd, "gradlew"), []byte(""), 0755)
+			ioutil.WriteFile(filepath.Join(pwd, "gradle", "wrapper", "gradle-wrapper.jar"), []byte(check.jar), 0644)
+			if check.offline {
+				server.Close()
+			}
+
+			context := testContext{
+				quiet:      true,
+				explicit:   true,
+				windows:    false,
+				workingDir: pwd,
+				env:        map[string]string{"GUM_HOME": filepath.Join(dir, "home")}}
+
+			config := newConfig()
+			config.gradle.validation = check.mode
+
+			// when:
+			verified := verifyGradleWrapperJar(context, config, filepath.Join(pwd, "gradlew"))
+
+			// then:
+			if verified != check.expected {
+				t.Errorf("Verified: got %v, want %v", verified, check.expected)
+			}
+		})
+	}
+
+	// then:
+	cached, _ := ioutil.ReadFile(filepath.Join(dir, "home", "cache", "wrapper-checksums"))
+	if string(cached) != official+" 8.7\n" {
+		t.Errorf("Cache: got %q", string(cached))
+	}
+}
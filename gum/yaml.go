@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2021 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Checks if the given config file is written in YAML, judging by its extension
+func isYamlFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// Parses a YAML document into a TOML tree, thus both formats share the same semantics
+func loadYamlTree(doc []byte) (*toml.Tree, error) {
+	data := make(map[string]interface{})
+	if err := yaml.Unmarshal(doc, &data); err != nil {
+		return nil, err
+	}
+
+	t, err := toml.TreeFromMap(map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	setYamlValues(t, nil, normalizeYamlValue(data).(map[string]interface{}))
+	return t, nil
+}
+
+// Copies values one by one, as TreeFromMap would turn []interface{} into typed slices
+// which the config readers do not expect
+func setYamlValues(t *toml.Tree, path []string, values map[string]interface{}) {
+	for k, v := range values {
+		p := append(append([]string{}, path...), k)
+		if m, ok := v.(map[string]interface{}); ok {
+			if len(m) == 0 {
+				empty, _ := toml.TreeFromMap(m)
+				t.SetPath(p, empty)
+			}
+			setYamlValues(t, p, m)
+			continue
+		}
+		t.SetPath(p, v)
+	}
+}
+
+// Converts YAML values into the types produced by the TOML parser
+func normalizeYamlValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			if e != nil {
+				result[k] = normalizeYamlValue(e)
+			}
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			if e != nil {
+				result[fmt.Sprint(k)] = normalizeYamlValue(e)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, e := range value {
+			result[i] = normalizeYamlValue(e)
+		}
+		return result
+	case int:
+		return int64(value)
+	case uint64:
+		return int64(value)
+	}
+
+	return v
+}
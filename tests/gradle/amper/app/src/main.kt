@@ -0,0 +1 @@
+fun main() = println("Hello")
@@ -0,0 +1 @@
+fun greeting() = "Hello"
@@ -0,0 +1,10 @@
+pluginManagement {
+    repositories {
+        mavenCentral()
+        gradlePluginPortal()
+    }
+}
+
+plugins {
+    id("org.jetbrains.amper.settings.plugin").version("0.4.0")
+}
@@ -0,0 +1,2 @@
+rootProject.name = "samples"
+includeBuild("..")
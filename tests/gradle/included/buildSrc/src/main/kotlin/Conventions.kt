@@ -0,0 +1 @@
+object Conventions
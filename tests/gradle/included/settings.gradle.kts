@@ -0,0 +1,6 @@
+pluginManagement {
+    includeBuild("build-logic")
+}
+
+rootProject.name = "included"
+include("app")
@@ -0,0 +1,9 @@
+///usr/bin/env jbang "$0" "$@" ; exit $?
+//DEPS info.picocli:picocli:4.6.1
+//JAVA 11+
+
+class hello {
+    public static void main(String... args) {
+        System.out.println("Hello World");
+    }
+}
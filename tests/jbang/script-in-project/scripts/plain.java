@@ -0,0 +1,5 @@
+class plain {
+    public static void main(String... args) {
+        System.out.println("Hello World");
+    }
+}
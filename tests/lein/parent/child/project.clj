@@ -0,0 +1 @@
+(defproject child "0.1.0")
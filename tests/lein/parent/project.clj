@@ -0,0 +1 @@
+(defproject parent "0.1.0" :sub ["child"])
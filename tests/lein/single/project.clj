@@ -0,0 +1 @@
+(defproject single "0.1.0")